@@ -0,0 +1,522 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package acctest
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fakeComputeKinds are the Compute Engine collections served by a
+// FakeGcpServer, and the kind of their resources.
+var fakeComputeKinds = map[string]string{
+	"networks":    "compute#network",
+	"subnetworks": "compute#subnetwork",
+	"firewalls":   "compute#firewall",
+}
+
+// fakeComputePath matches Compute Engine paths such as
+// "projects/p/regions/r/subnetworks/s/expandIpCidrRange", capturing the
+// project, the scope of the collection, the collection, the resource name and
+// the custom method.
+var fakeComputePath = regexp.MustCompile(`^projects/([^/]+)/(global|regions/[^/]+|zones/[^/]+)/([A-Za-z]+)(?:/([^/]+))?(?:/([A-Za-z]+))?$`)
+
+// computeServerFields are the fields of Compute Engine resources set by the
+// server, which are kept when a resource is replaced.
+var computeServerFields = []string{"kind", "id", "creationTimestamp", "selfLink", "region"}
+
+func (s *FakeGcpServer) serveCompute(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := fakeComputePath.FindStringSubmatch(strings.TrimPrefix(r.URL.Path, fakeComputePrefix))
+	if m == nil {
+		fakeNotImplemented(w, r)
+		return
+	}
+	project, scope, collection, name, method := m[1], m[2], m[3], m[4], m[5]
+	scopePath := fakeComputePrefix + "projects/" + project + "/" + scope
+	collectionPath := scopePath + "/" + collection
+	path := collectionPath + "/" + name
+
+	if collection == "operations" {
+		if name == "" || method != "" || r.Method != http.MethodGet {
+			fakeNotImplemented(w, r)
+			return
+		}
+		op, ok := s.operations[path]
+		if !ok {
+			fakeNotFound(w, path)
+			return
+		}
+		op["status"] = "DONE"
+		op["progress"] = 100
+		op["endTime"] = fakeTimestamp()
+		writeFakeJson(w, http.StatusOK, op)
+		return
+	}
+
+	kind, ok := fakeComputeKinds[collection]
+	if !ok {
+		fakeNotImplemented(w, r)
+		return
+	}
+
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJson(w, http.StatusOK, map[string]interface{}{
+				"kind":     kind + "List",
+				"id":       collectionPath,
+				"items":    s.list(collectionPath),
+				"selfLink": s.URL + collectionPath,
+			})
+		case http.MethodPost:
+			body, ok := readFakeBody(w, r)
+			if !ok {
+				return
+			}
+			dropFakeNulls(body)
+			name, _ := body["name"].(string)
+			if name == "" {
+				fakeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "required", "Required field 'resource.name' not specified")
+				return
+			}
+			path = collectionPath + "/" + name
+			if _, ok := s.resources[path]; ok {
+				fakeAlreadyExists(w, path)
+				return
+			}
+			body["kind"] = kind
+			body["id"] = s.newId()
+			body["creationTimestamp"] = fakeTimestamp()
+			body["selfLink"] = s.URL + path
+			body["fingerprint"] = s.newId()
+			if strings.HasPrefix(scope, "regions/") {
+				body["region"] = s.URL + scopePath
+			}
+			s.resources[path] = body
+			s.startComputeOperation(w, project, scope, "insert", path)
+		default:
+			fakeNotImplemented(w, r)
+		}
+		return
+	}
+
+	resource, ok := s.resources[path]
+	if !ok {
+		fakeNotFound(w, path)
+		return
+	}
+	switch {
+	case method == "" && r.Method == http.MethodGet:
+		writeFakeJson(w, http.StatusOK, resource)
+	case method == "" && r.Method == http.MethodPatch:
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		mergeInto(resource, body, "")
+		resource["fingerprint"] = s.newId()
+		s.startComputeOperation(w, project, scope, "patch", path)
+	case method == "" && r.Method == http.MethodPut:
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		for _, field := range computeServerFields {
+			if v, ok := resource[field]; ok {
+				body[field] = v
+			}
+		}
+		body["fingerprint"] = s.newId()
+		s.resources[path] = body
+		s.startComputeOperation(w, project, scope, "update", path)
+	case method == "" && r.Method == http.MethodDelete:
+		delete(s.resources, path)
+		s.startComputeOperation(w, project, scope, "delete", path)
+	case method != "" && r.Method == http.MethodPost:
+		// Custom methods such as setPrivateIpGoogleAccess set the fields in
+		// their request body.
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		mergeInto(resource, body, "")
+		resource["fingerprint"] = s.newId()
+		s.startComputeOperation(w, project, scope, method, path)
+	default:
+		fakeNotImplemented(w, r)
+	}
+}
+
+// startComputeOperation writes a running Compute Engine operation on the
+// resource at targetPath, which finishes when it is first polled.
+func (s *FakeGcpServer) startComputeOperation(w http.ResponseWriter, project, scope, operationType, targetPath string) {
+	scopePath := fakeComputePrefix + "projects/" + project + "/" + scope
+	name := "operation-" + s.newId()
+	path := scopePath + "/operations/" + name
+
+	op := map[string]interface{}{
+		"kind":          "compute#operation",
+		"id":            s.newId(),
+		"name":          name,
+		"operationType": operationType,
+		"targetLink":    s.URL + targetPath,
+		"status":        "RUNNING",
+		"progress":      0,
+		"insertTime":    fakeTimestamp(),
+		"startTime":     fakeTimestamp(),
+		"selfLink":      s.URL + path,
+	}
+	switch {
+	case strings.HasPrefix(scope, "regions/"):
+		op["region"] = s.URL + scopePath
+	case strings.HasPrefix(scope, "zones/"):
+		op["zone"] = s.URL + scopePath
+	}
+	s.operations[path] = op
+	writeFakeJson(w, http.StatusOK, op)
+}
+
+func (s *FakeGcpServer) serveStorage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, fakeStoragePrefix), "/")
+	if segments[0] != "b" {
+		fakeNotImplemented(w, r)
+		return
+	}
+	collectionPath := fakeStoragePrefix + "b"
+
+	if len(segments) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJson(w, http.StatusOK, map[string]interface{}{
+				"kind":  "storage#buckets",
+				"items": s.list(collectionPath),
+			})
+		case http.MethodPost:
+			body, ok := readFakeBody(w, r)
+			if !ok {
+				return
+			}
+			dropFakeNulls(body)
+			name, _ := body["name"].(string)
+			if name == "" {
+				fakeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "required", "Required field 'name' not specified")
+				return
+			}
+			path := collectionPath + "/" + name
+			if _, ok := s.resources[path]; ok {
+				fakeError(w, http.StatusConflict, "ALREADY_EXISTS", "conflict", "Your previous request to create the named bucket succeeded and you already own it.")
+				return
+			}
+			body["kind"] = "storage#bucket"
+			body["id"] = name
+			body["selfLink"] = s.URL + path
+			body["projectNumber"] = s.projectNumber(r.URL.Query().Get("project"))
+			body["metageneration"] = "1"
+			body["timeCreated"] = fakeTimestamp()
+			body["updated"] = fakeTimestamp()
+			body["etag"] = "CAE="
+			if _, ok := body["location"]; !ok {
+				body["location"] = "US"
+			}
+			body["location"] = strings.ToUpper(body["location"].(string))
+			if _, ok := body["storageClass"]; !ok {
+				body["storageClass"] = "STANDARD"
+			}
+			s.resources[path] = body
+			writeFakeJson(w, http.StatusOK, body)
+		default:
+			fakeNotImplemented(w, r)
+		}
+		return
+	}
+
+	path := collectionPath + "/" + segments[1]
+	bucket, ok := s.resources[path]
+	if !ok {
+		fakeNotFound(w, path)
+		return
+	}
+	switch {
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		writeFakeJson(w, http.StatusOK, bucket)
+	case len(segments) == 2 && (r.Method == http.MethodPatch || r.Method == http.MethodPut):
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		mergeInto(bucket, body, "")
+		metageneration, _ := strconv.Atoi(bucket["metageneration"].(string))
+		bucket["metageneration"] = strconv.Itoa(metageneration + 1)
+		bucket["updated"] = fakeTimestamp()
+		writeFakeJson(w, http.StatusOK, bucket)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		delete(s.resources, path)
+		delete(s.policies, path)
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 3 && segments[2] == "o" && r.Method == http.MethodGet:
+		// Buckets never hold objects, which are not supported.
+		writeFakeJson(w, http.StatusOK, map[string]interface{}{"kind": "storage#objects"})
+	case len(segments) == 3 && segments[2] == "iam" && r.Method == http.MethodGet:
+		policy := map[string]interface{}{}
+		mergeInto(policy, s.getIamPolicy(path), "")
+		policy["kind"] = "storage#policy"
+		policy["resourceId"] = "projects/_/buckets/" + segments[1]
+		writeFakeJson(w, http.StatusOK, policy)
+	case len(segments) == 3 && segments[2] == "iam" && r.Method == http.MethodPut:
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		if policy, ok := s.setIamPolicy(w, path, body); ok {
+			writeFakeJson(w, http.StatusOK, policy)
+		}
+	default:
+		fakeNotImplemented(w, r)
+	}
+}
+
+// fakePubsubResources are the Pub/Sub collections served by a FakeGcpServer,
+// and the field their resource is set in when it is updated.
+var fakePubsubResources = map[string]string{
+	"topics":        "topic",
+	"subscriptions": "subscription",
+}
+
+func (s *FakeGcpServer) servePubsub(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name, method := splitFakeCustomMethod(strings.TrimPrefix(r.URL.Path, fakePubsubPrefix))
+	segments := strings.Split(name, "/")
+	if len(segments) < 3 || len(segments) > 4 || segments[0] != "projects" {
+		fakeNotImplemented(w, r)
+		return
+	}
+	field, ok := fakePubsubResources[segments[2]]
+	if !ok {
+		fakeNotImplemented(w, r)
+		return
+	}
+	collectionPath := fakePubsubPrefix + strings.Join(segments[:3], "/")
+
+	if len(segments) == 3 {
+		if method != "" || r.Method != http.MethodGet {
+			fakeNotImplemented(w, r)
+			return
+		}
+		writeFakeJson(w, http.StatusOK, map[string]interface{}{
+			segments[2]: s.list(collectionPath),
+		})
+		return
+	}
+
+	path := fakePubsubPrefix + name
+	resource, exists := s.resources[path]
+	if !exists && !(method == "" && r.Method == http.MethodPut) {
+		fakeNotFound(w, path)
+		return
+	}
+	switch {
+	case method == "" && r.Method == http.MethodPut:
+		if exists {
+			fakeAlreadyExists(w, path)
+			return
+		}
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		dropFakeNulls(body)
+		body["name"] = name
+		if field == "subscription" {
+			topic, _ := body["topic"].(string)
+			if _, ok := s.resources[fakePubsubPrefix+topic]; !ok {
+				fakeNotFound(w, topic)
+				return
+			}
+			setFakeDefault(body, "ackDeadlineSeconds", 10)
+			setFakeDefault(body, "messageRetentionDuration", "604800s")
+			setFakeDefault(body, "expirationPolicy", map[string]interface{}{"ttl": "2678400s"})
+		}
+		s.resources[path] = body
+		writeFakeJson(w, http.StatusOK, body)
+	case method == "" && r.Method == http.MethodGet:
+		writeFakeJson(w, http.StatusOK, resource)
+	case method == "" && r.Method == http.MethodPatch:
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		update, _ := body[field].(map[string]interface{})
+		mask, _ := body["updateMask"].(string)
+		mergeInto(resource, update, mask)
+		writeFakeJson(w, http.StatusOK, resource)
+	case method == "" && r.Method == http.MethodDelete:
+		delete(s.resources, path)
+		delete(s.policies, path)
+		writeFakeJson(w, http.StatusOK, map[string]interface{}{})
+	default:
+		s.serveIamMethod(w, r, path, method)
+	}
+}
+
+func (s *FakeGcpServer) serveResourceManager(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name, method := splitFakeCustomMethod(strings.TrimPrefix(r.URL.Path, fakeResourceManagerPrefix))
+	path := fakeResourceManagerPrefix + name
+	segments := strings.Split(name, "/")
+
+	switch {
+	case len(segments) == 2 && segments[0] == "operations" && r.Method == http.MethodGet:
+		op, ok := s.operations[path]
+		if !ok {
+			fakeNotFound(w, path)
+			return
+		}
+		op["done"] = true
+		writeFakeJson(w, http.StatusOK, op)
+	case name == "projects" && r.Method == http.MethodGet:
+		writeFakeJson(w, http.StatusOK, map[string]interface{}{
+			"projects": s.list(path),
+		})
+	case name == "projects" && r.Method == http.MethodPost:
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		dropFakeNulls(body)
+		projectId, _ := body["projectId"].(string)
+		if projectId == "" {
+			fakeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "badRequest", "Request contains an invalid argument.")
+			return
+		}
+		path = path + "/" + projectId
+		if _, ok := s.resources[path]; ok {
+			fakeAlreadyExists(w, path)
+			return
+		}
+		body["projectNumber"] = s.newId()
+		body["lifecycleState"] = "ACTIVE"
+		body["createTime"] = fakeTimestamp()
+		s.resources[path] = body
+
+		opName := "operations/cp." + s.newId()
+		response := map[string]interface{}{"@type": "type.googleapis.com/google.cloudresourcemanager.v1.Project"}
+		mergeInto(response, body, "")
+		s.operations[fakeResourceManagerPrefix+opName] = map[string]interface{}{
+			"name":     opName,
+			"done":     false,
+			"response": response,
+		}
+		writeFakeJson(w, http.StatusOK, map[string]interface{}{"name": opName})
+	case len(segments) == 2 && segments[0] == "projects" && method != "":
+		// Tests commonly set the IAM policy of a project they did not
+		// create, so the project is not required to exist.
+		s.serveIamMethod(w, r, path, method)
+	case len(segments) == 2 && segments[0] == "projects":
+		project, ok := s.resources[path]
+		if !ok {
+			fakeNotFound(w, path)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJson(w, http.StatusOK, project)
+		case http.MethodPut:
+			body, ok := readFakeBody(w, r)
+			if !ok {
+				return
+			}
+			mergeInto(project, body, "name,labels,parent")
+			writeFakeJson(w, http.StatusOK, project)
+		case http.MethodDelete:
+			project["lifecycleState"] = "DELETE_REQUESTED"
+			writeFakeJson(w, http.StatusOK, map[string]interface{}{})
+		default:
+			fakeNotImplemented(w, r)
+		}
+	default:
+		fakeNotImplemented(w, r)
+	}
+}
+
+// serveCloudBilling serves the billing info of projects, which never have a
+// billing account.
+func (s *FakeGcpServer) serveCloudBilling(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, fakeCloudBillingPrefix)
+	segments := strings.Split(name, "/")
+	if len(segments) != 3 || segments[0] != "projects" || segments[2] != "billingInfo" || r.Method != http.MethodGet {
+		fakeNotImplemented(w, r)
+		return
+	}
+	writeFakeJson(w, http.StatusOK, map[string]interface{}{
+		"name":           name,
+		"projectId":      segments[1],
+		"billingEnabled": false,
+	})
+}
+
+// serveIamMethod serves the getIamPolicy and setIamPolicy custom methods of
+// the resource at path. Some APIs, such as Pub/Sub, get IAM policies with GET
+// rather than POST.
+func (s *FakeGcpServer) serveIamMethod(w http.ResponseWriter, r *http.Request, path, method string) {
+	switch {
+	case method == "getIamPolicy" && (r.Method == http.MethodGet || r.Method == http.MethodPost):
+		writeFakeJson(w, http.StatusOK, s.getIamPolicy(path))
+	case method == "setIamPolicy" && r.Method == http.MethodPost:
+		body, ok := readFakeBody(w, r)
+		if !ok {
+			return
+		}
+		policy, _ := body["policy"].(map[string]interface{})
+		if policy, ok := s.setIamPolicy(w, path, policy); ok {
+			writeFakeJson(w, http.StatusOK, policy)
+		}
+	default:
+		fakeNotImplemented(w, r)
+	}
+}
+
+// projectNumber returns the number of the project with the given ID if it was
+// created on the server, or a made up number otherwise.
+func (s *FakeGcpServer) projectNumber(projectId string) string {
+	if project, ok := s.resources[fakeResourceManagerPrefix+"projects/"+projectId]; ok {
+		return project["projectNumber"].(string)
+	}
+	return "123456789"
+}
+
+// splitFakeCustomMethod splits a path such as "projects/p:getIamPolicy" into
+// the resource name and the custom method.
+func splitFakeCustomMethod(path string) (string, string) {
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		return path[:i], path[i+1:]
+	}
+	return path, ""
+}
+
+// dropFakeNulls removes the fields set to null in the body of a create
+// request, which leaves them unset.
+func dropFakeNulls(body map[string]interface{}) {
+	for k, v := range body {
+		if v == nil {
+			delete(body, k)
+		}
+	}
+}
+
+func setFakeDefault(resource map[string]interface{}, field string, v interface{}) {
+	if _, ok := resource[field]; !ok {
+		resource[field] = v
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package acctest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/envvar"
+)
+
+// Path prefixes the FakeGcpServer serves each API under. Each is the path of
+// the API's custom endpoint on the server.
+const (
+	fakeComputePrefix         = "/compute/beta/"
+	fakeStoragePrefix         = "/storage/v1/"
+	fakePubsubPrefix          = "/pubsub/v1/"
+	fakeResourceManagerPrefix = "/resourcemanager/v1/"
+	fakeCloudBillingPrefix    = "/cloudbilling/v1/"
+)
+
+// fakeCustomEndpointEnvVars are the environment variables setting the custom
+// endpoint of each API served by a FakeGcpServer, and the path prefix the API
+// is served under.
+var fakeCustomEndpointEnvVars = map[string]string{
+	"GOOGLE_COMPUTE_CUSTOM_ENDPOINT":          fakeComputePrefix,
+	"GOOGLE_STORAGE_CUSTOM_ENDPOINT":          fakeStoragePrefix,
+	"GOOGLE_PUBSUB_CUSTOM_ENDPOINT":           fakePubsubPrefix,
+	"GOOGLE_RESOURCE_MANAGER_CUSTOM_ENDPOINT": fakeResourceManagerPrefix,
+	"GOOGLE_CLOUD_BILLING_CUSTOM_ENDPOINT":    fakeCloudBillingPrefix,
+}
+
+// FakeGcpServer is an in-memory fake of a core set of Google Cloud APIs, so
+// that acceptance tests can run without credentials or network access. It
+// keeps the resources created through it, and serves them back until they are
+// deleted. The following are supported:
+//
+//   - Compute Engine networks, subnetworks and firewalls, created, updated and
+//     deleted through operations that finish when first polled.
+//   - Cloud Storage buckets and their IAM policies.
+//   - Pub/Sub topics and subscriptions and their IAM policies.
+//   - Resource Manager projects and their IAM policies. Projects are created
+//     through an operation, and have billing disabled.
+//
+// Requests to any other API, or any other resource, fail with a 501 error.
+type FakeGcpServer struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// resources are keyed by their path on the server, such as
+	// "/compute/beta/projects/p/global/networks/n".
+	resources map[string]map[string]interface{}
+	// policies are IAM policies, keyed by the path of their resource.
+	policies map[string]map[string]interface{}
+	// operations are long-running operations, keyed by their path.
+	operations map[string]map[string]interface{}
+	nextId     int64
+}
+
+// NewFakeGcpServer starts a FakeGcpServer that is closed when the test ends.
+func NewFakeGcpServer(t *testing.T) *FakeGcpServer {
+	s := &FakeGcpServer{
+		resources:  make(map[string]map[string]interface{}),
+		policies:   make(map[string]map[string]interface{}),
+		operations: make(map[string]map[string]interface{}),
+		nextId:     1000000,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(fakeComputePrefix, s.serveCompute)
+	mux.HandleFunc(fakeStoragePrefix, s.serveStorage)
+	mux.HandleFunc(fakePubsubPrefix, s.servePubsub)
+	mux.HandleFunc(fakeResourceManagerPrefix, s.serveResourceManager)
+	mux.HandleFunc(fakeCloudBillingPrefix, s.serveCloudBilling)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fakeNotImplemented(w, r)
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetupTestEnvs points the provider at the server for the rest of the test:
+// the custom endpoints of the APIs it serves are set to the server, a fake
+// access token is used instead of credentials, and the project, region and
+// zone are set to the given values.
+func (s *FakeGcpServer) SetupTestEnvs(t *testing.T, project, region, zone string) {
+	UnsetTestProviderConfigEnvs(t)
+
+	envs := map[string]string{
+		"GOOGLE_OAUTH_ACCESS_TOKEN": "fake-access-token",
+		envvar.ProjectEnvVars[0]:    project,
+		envvar.RegionEnvVars[0]:     region,
+		envvar.ZoneEnvVars[0]:       zone,
+	}
+	for k, v := range s.CustomEndpoints() {
+		envs[k] = v
+	}
+	SetupTestEnvs(t, envs)
+}
+
+// CustomEndpoints returns the custom endpoint of each API the server serves,
+// keyed by the environment variable that sets it.
+func (s *FakeGcpServer) CustomEndpoints() map[string]string {
+	endpoints := make(map[string]string, len(fakeCustomEndpointEnvVars))
+	for k, prefix := range fakeCustomEndpointEnvVars {
+		endpoints[k] = s.URL + prefix
+	}
+	return endpoints
+}
+
+// newId returns a unique numeric ID.
+func (s *FakeGcpServer) newId() string {
+	s.nextId++
+	return strconv.FormatInt(s.nextId, 10)
+}
+
+// list returns the resources whose path is directly under collection, sorted
+// by path.
+func (s *FakeGcpServer) list(collection string) []interface{} {
+	var paths []string
+	for path := range s.resources {
+		if strings.HasPrefix(path, collection+"/") && !strings.Contains(strings.TrimPrefix(path, collection+"/"), "/") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	items := make([]interface{}, 0, len(paths))
+	for _, path := range paths {
+		items = append(items, s.resources[path])
+	}
+	return items
+}
+
+// getIamPolicy returns the IAM policy of the resource at path, or an empty
+// policy if none was set.
+func (s *FakeGcpServer) getIamPolicy(path string) map[string]interface{} {
+	if policy, ok := s.policies[path]; ok {
+		return policy
+	}
+	return map[string]interface{}{"version": 1, "etag": "BwAAAAAAAAA="}
+}
+
+// setIamPolicy replaces the IAM policy of the resource at path. As in the
+// real APIs, the update fails if the policy has an etag that does not match
+// the current policy's.
+func (s *FakeGcpServer) setIamPolicy(w http.ResponseWriter, path string, policy map[string]interface{}) (map[string]interface{}, bool) {
+	current := s.getIamPolicy(path)
+	if etag, ok := policy["etag"]; ok && etag != "" && etag != current["etag"] {
+		fakeError(w, http.StatusConflict, "ABORTED", "aborted", "There were concurrent policy changes. Please retry the whole read-modify-write with exponential backoff.")
+		return nil, false
+	}
+
+	updated := make(map[string]interface{}, len(policy)+1)
+	for k, v := range policy {
+		updated[k] = v
+	}
+	updated["etag"] = fmt.Sprintf("BwY%s=", s.newId())
+	if _, ok := updated["version"]; !ok {
+		updated["version"] = 1
+	}
+	s.policies[path] = updated
+	return updated, true
+}
+
+// mergeInto sets the fields of update on resource, as a PATCH request would.
+// Fields set to null in update are cleared. If mask is set, only the
+// top-level fields it names are changed, and those missing from update are
+// cleared.
+func mergeInto(resource, update map[string]interface{}, mask string) {
+	if mask == "" {
+		for k, v := range update {
+			if v == nil {
+				delete(resource, k)
+				continue
+			}
+			resource[k] = v
+		}
+		return
+	}
+
+	for _, field := range strings.Split(mask, ",") {
+		field = fakeCamelCase(strings.SplitN(strings.TrimSpace(field), ".", 2)[0])
+		if v, ok := update[field]; ok && v != nil {
+			resource[field] = v
+		} else {
+			delete(resource, field)
+		}
+	}
+}
+
+// fakeCamelCase converts a snake_case field name in an update mask to the
+// camelCase name used in JSON.
+func fakeCamelCase(field string) string {
+	parts := strings.Split(field, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+func fakeTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// readFakeBody decodes the JSON object in the body of r. An empty body is
+// decoded as an empty object.
+func readFakeBody(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	body := make(map[string]interface{})
+	if r.Body == nil {
+		return body, true
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		fakeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "badRequest", fmt.Sprintf("Invalid JSON payload received: %s", err))
+		return nil, false
+	}
+	return body, true
+}
+
+func writeFakeJson(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// fakeError writes an error in the format of Google APIs, so that it is
+// parsed into a *googleapi.Error.
+func fakeError(w http.ResponseWriter, code int, status, reason, message string) {
+	writeFakeJson(w, code, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"status":  status,
+			"errors": []interface{}{
+				map[string]interface{}{
+					"message": message,
+					"domain":  "global",
+					"reason":  reason,
+				},
+			},
+		},
+	})
+}
+
+func fakeNotFound(w http.ResponseWriter, path string) {
+	fakeError(w, http.StatusNotFound, "NOT_FOUND", "notFound", fmt.Sprintf("The resource '%s' was not found", path))
+}
+
+func fakeAlreadyExists(w http.ResponseWriter, path string) {
+	fakeError(w, http.StatusConflict, "ALREADY_EXISTS", "alreadyExists", fmt.Sprintf("The resource '%s' already exists", path))
+}
+
+func fakeNotImplemented(w http.ResponseWriter, r *http.Request) {
+	fakeError(w, http.StatusNotImplemented, "UNIMPLEMENTED", "notImplemented", fmt.Sprintf("%s %s is not implemented by the fake server", r.Method, r.URL.Path))
+}
@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package acctest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	compute "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/pubsub/v1"
+	"google.golang.org/api/storage/v1"
+)
+
+func fakeClientOptions(s *FakeGcpServer, prefix string) []option.ClientOption {
+	return []option.ClientOption{
+		option.WithEndpoint(s.URL + prefix),
+		option.WithoutAuthentication(),
+	}
+}
+
+func isFakeErrorCode(err error, code int) bool {
+	gerr, ok := err.(*googleapi.Error)
+	return ok && gerr.Code == code
+}
+
+func TestFakeGcpServer_ComputeOperations(t *testing.T) {
+	s := NewFakeGcpServer(t)
+	client, err := compute.NewService(context.Background(), fakeClientOptions(s, fakeComputePrefix)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op, err := client.Subnetworks.Insert("p", "us-central1", &compute.Subnetwork{Name: "subnet", IpCidrRange: "10.0.0.0/24"}).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op.Status != "RUNNING" || op.Region == "" {
+		t.Fatalf("expected a running regional operation, got %+v", op)
+	}
+	op, err = client.RegionOperations.Get("p", "us-central1", op.Name).Do()
+	if err != nil || op.Status != "DONE" {
+		t.Fatalf("expected the operation to be done when polled, got %+v, %v", op, err)
+	}
+
+	if _, err := client.Subnetworks.Insert("p", "us-central1", &compute.Subnetwork{Name: "subnet"}).Do(); !isFakeErrorCode(err, http.StatusConflict) {
+		t.Fatalf("expected creating a duplicate subnetwork to fail with 409, got %v", err)
+	}
+
+	if _, err := client.Subnetworks.ExpandIpCidrRange("p", "us-central1", "subnet", &compute.SubnetworksExpandIpCidrRangeRequest{IpCidrRange: "10.0.0.0/16"}).Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subnet, err := client.Subnetworks.Get("p", "us-central1", "subnet").Do()
+	if err != nil || subnet.IpCidrRange != "10.0.0.0/16" || subnet.Fingerprint == "" {
+		t.Fatalf("expected the custom method to update the subnetwork, got %+v, %v", subnet, err)
+	}
+
+	list, err := client.Subnetworks.List("p", "us-central1").Do()
+	if err != nil || len(list.Items) != 1 {
+		t.Fatalf("expected 1 subnetwork to be listed, got %+v, %v", list, err)
+	}
+
+	if _, err := client.Subnetworks.Delete("p", "us-central1", "subnet").Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Subnetworks.Get("p", "us-central1", "subnet").Do(); !isFakeErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("expected the deleted subnetwork to be gone, got %v", err)
+	}
+
+	if _, err := client.Instances.Get("p", "us-central1-a", "vm").Do(); !isFakeErrorCode(err, http.StatusNotImplemented) {
+		t.Fatalf("expected unsupported resources to fail with 501, got %v", err)
+	}
+}
+
+func TestFakeGcpServer_StorageBucketIam(t *testing.T) {
+	s := NewFakeGcpServer(t)
+	client, err := storage.NewService(context.Background(), fakeClientOptions(s, fakeStoragePrefix)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket, err := client.Buckets.Insert("p", &storage.Bucket{Name: "bucket", Location: "us-central1"}).Do()
+	if err != nil || bucket.Location != "US-CENTRAL1" || bucket.StorageClass != "STANDARD" {
+		t.Fatalf("expected a bucket with server defaults, got %+v, %v", bucket, err)
+	}
+
+	policy, err := client.Buckets.GetIamPolicy("bucket").Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	policy.Bindings = []*storage.PolicyBindings{{Role: "roles/storage.admin", Members: []string{"user:a@example.com"}}}
+	updated, err := client.Buckets.SetIamPolicy("bucket", policy).Do()
+	if err != nil || len(updated.Bindings) != 1 {
+		t.Fatalf("unexpected policy %+v, %v", updated, err)
+	}
+	if _, err := client.Buckets.SetIamPolicy("bucket", policy).Do(); !isFakeErrorCode(err, http.StatusConflict) {
+		t.Fatalf("expected setting a policy with a stale etag to fail with 409, got %v", err)
+	}
+
+	if err := client.Buckets.Delete("bucket").Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Buckets.Get("bucket").Do(); !isFakeErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("expected the deleted bucket to be gone, got %v", err)
+	}
+}
+
+func TestFakeGcpServer_PubsubUpdateMask(t *testing.T) {
+	s := NewFakeGcpServer(t)
+	client, err := pubsub.NewService(context.Background(), fakeClientOptions(s, "/pubsub/")...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Projects.Subscriptions.Create("projects/p/subscriptions/sub", &pubsub.Subscription{Topic: "projects/p/topics/topic"}).Do(); !isFakeErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("expected a subscription to a missing topic to fail with 404, got %v", err)
+	}
+
+	if _, err := client.Projects.Topics.Create("projects/p/topics/topic", &pubsub.Topic{Labels: map[string]string{"a": "b"}, KmsKeyName: "key"}).Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	topic, err := client.Projects.Topics.Patch("projects/p/topics/topic", &pubsub.UpdateTopicRequest{
+		Topic:      &pubsub.Topic{KmsKeyName: "other-key"},
+		UpdateMask: "labels,kms_key_name",
+	}).Do()
+	if err != nil || topic.KmsKeyName != "other-key" || len(topic.Labels) != 0 {
+		t.Fatalf("expected only the fields in the update mask to change, got %+v, %v", topic, err)
+	}
+
+	sub, err := client.Projects.Subscriptions.Create("projects/p/subscriptions/sub", &pubsub.Subscription{Topic: "projects/p/topics/topic"}).Do()
+	if err != nil || sub.AckDeadlineSeconds != 10 {
+		t.Fatalf("expected a subscription with server defaults, got %+v, %v", sub, err)
+	}
+	list, err := client.Projects.Subscriptions.List("projects/p").Do()
+	if err != nil || len(list.Subscriptions) != 1 {
+		t.Fatalf("expected 1 subscription to be listed, got %+v, %v", list, err)
+	}
+
+	if _, err := client.Projects.Topics.SetIamPolicy("projects/p/topics/topic", &pubsub.SetIamPolicyRequest{
+		Policy: &pubsub.Policy{Bindings: []*pubsub.Binding{{Role: "roles/pubsub.publisher", Members: []string{"allUsers"}}}},
+	}).Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	policy, err := client.Projects.Topics.GetIamPolicy("projects/p/topics/topic").Do()
+	if err != nil || len(policy.Bindings) != 1 {
+		t.Fatalf("expected the policy to be stored, got %+v, %v", policy, err)
+	}
+}
+
+func TestFakeGcpServer_ResourceManagerProject(t *testing.T) {
+	s := NewFakeGcpServer(t)
+	client, err := cloudresourcemanager.NewService(context.Background(), fakeClientOptions(s, "/resourcemanager/")...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op, err := client.Projects.Create(&cloudresourcemanager.Project{ProjectId: "proj", Name: "Project"}).Do()
+	if err != nil || op.Done {
+		t.Fatalf("expected a running operation, got %+v, %v", op, err)
+	}
+	op, err = client.Operations.Get(op.Name).Do()
+	if err != nil || !op.Done || op.Response == nil {
+		t.Fatalf("expected the operation to be done when polled, got %+v, %v", op, err)
+	}
+
+	project, err := client.Projects.Get("proj").Do()
+	if err != nil || project.LifecycleState != "ACTIVE" || project.ProjectNumber == 0 {
+		t.Fatalf("unexpected project %+v, %v", project, err)
+	}
+
+	if _, err := client.Projects.Delete("proj").Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	project, err = client.Projects.Get("proj").Do()
+	if err != nil || project.LifecycleState != "DELETE_REQUESTED" {
+		t.Fatalf("expected the project to be pending deletion, got %+v, %v", project, err)
+	}
+}
@@ -232,6 +232,7 @@ func providerConfigEnvNames() []string {
 	envs = append(envs, "GOOGLE_IMPERSONATE_SERVICE_ACCOUNT") // impersonate_service_account field
 	envs = append(envs, "USER_PROJECT_OVERRIDE")              // user_project_override field
 	envs = append(envs, "CLOUDSDK_CORE_REQUEST_REASON")       // request_reason field
+	envs = append(envs, "GOOGLE_LOG_FORMAT")                  // log_format field
 
 	envs = append(envs, "GOOGLE_APPLICATION_CREDENTIALS") // ADC used to configure clients when provider lacks credentials and access_token
 
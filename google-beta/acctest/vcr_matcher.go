@@ -0,0 +1,301 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package acctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+)
+
+// VcrFieldDiff is a field of a request that differs from a recorded request.
+type VcrFieldDiff struct {
+	// Field is "method", "url", "url.query.<name>" for a query parameter,
+	// "body", or "body.<path>" for a field of a JSON body, such as
+	// "body.labels.env" or "body.rules[0].ports".
+	Field string
+	// Recorded and Requested are the values of the field in the recorded and
+	// the new request, or nil if the field is not set in that request.
+	Recorded  interface{}
+	Requested interface{}
+}
+
+func (d VcrFieldDiff) String() string {
+	return fmt.Sprintf("%s: recorded %s, requested %s", d.Field, formatVcrValue(d.Recorded), formatVcrValue(d.Requested))
+}
+
+func formatVcrValue(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// VcrMismatchError is returned when replaying a cassette, if a request
+// matches none of the recorded requests left. If VCR_MATCH_DIFF is set, Diffs
+// are the fields the request differs in from the closest recorded request
+// with the same method and URL path.
+type VcrMismatchError struct {
+	Method string
+	URL    string
+	Diffs  []VcrFieldDiff
+}
+
+func (e *VcrMismatchError) Error() string {
+	if len(e.Diffs) == 0 {
+		return fmt.Sprintf("%s for %s %s: no recorded request left has the same method and URL path", cassette.ErrInteractionNotFound, e.Method, e.URL)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s for %s %s. The closest recorded request differs in:", cassette.ErrInteractionNotFound, e.Method, e.URL)
+	for _, d := range e.Diffs {
+		fmt.Fprintf(&b, "\n  %s", d)
+	}
+	return b.String()
+}
+
+func (e *VcrMismatchError) Unwrap() error {
+	return cassette.ErrInteractionNotFound
+}
+
+// vcrTransport records or replays requests through a VCR recorder. Cassettes
+// are sanitized when saved, and placeholders in replayed responses are
+// replaced with the current identifier values.
+type vcrTransport struct {
+	recorder  *recorder.Recorder
+	mode      recorder.Mode
+	path      string
+	sanitizer *vcrSanitizer
+
+	// reportDiffs is whether requests that match no recorded request fail
+	// with the fields they differ in from the closest one.
+	reportDiffs bool
+	closestLock sync.Mutex
+	closest     map[*http.Request][]VcrFieldDiff
+}
+
+func newVcrTransport(path string, mode recorder.Mode, rndTripper http.RoundTripper, reportDiffs bool) (*vcrTransport, error) {
+	rec, err := recorder.NewAsMode(path, mode, rndTripper)
+	if err != nil {
+		return nil, err
+	}
+	t := &vcrTransport{
+		recorder:    rec,
+		mode:        mode,
+		path:        path,
+		sanitizer:   newVcrSanitizer(),
+		reportDiffs: reportDiffs,
+		closest:     make(map[*http.Request][]VcrFieldDiff),
+	}
+	// Defines how VCR will match requests to responses.
+	rec.SetMatcher(t.match)
+	return t, nil
+}
+
+func (t *vcrTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.recorder.RoundTrip(req)
+	if t.mode != recorder.ModeReplaying {
+		return resp, err
+	}
+
+	t.closestLock.Lock()
+	diffs := t.closest[req]
+	delete(t.closest, req)
+	t.closestLock.Unlock()
+	if err != nil {
+		if t.reportDiffs && errors.Is(err, cassette.ErrInteractionNotFound) {
+			return nil, &VcrMismatchError{Method: req.Method, URL: req.URL.String(), Diffs: diffs}
+		}
+		return nil, err
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	b = []byte(t.sanitizer.desanitize(string(b)))
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	if req.Method != http.MethodHead {
+		resp.ContentLength = int64(len(b))
+	}
+	resp.Header = t.sanitizer.desanitizeHeader(resp.Header)
+	return resp, nil
+}
+
+// Stop stops the recorder, saving and sanitizing the cassette if recording.
+func (t *vcrTransport) Stop() error {
+	if err := t.recorder.Stop(); err != nil {
+		return err
+	}
+	if t.mode != recorder.ModeRecording {
+		return nil
+	}
+	return t.sanitizer.sanitizeCassette(t.path)
+}
+
+// match reports whether r matches the recorded request i. Both are sanitized
+// before being compared, so that requests match cassettes whether or not they
+// were sanitized when saved.
+func (t *vcrTransport) match(r *http.Request, i cassette.Request) bool {
+	var body *string
+	if r.Body != nil {
+		var b bytes.Buffer
+		if _, err := b.ReadFrom(r.Body); err != nil {
+			return false
+		}
+		r.Body = io.NopCloser(&b)
+		s := b.String()
+		body = &s
+	}
+
+	diffs := diffVcrRequest(t.sanitizer, r.Method, r.URL.String(), r.Header.Get("Content-Type"), body, i)
+	if len(diffs) == 0 {
+		return true
+	}
+	if t.reportDiffs && diffs[0].Field != "method" && diffs[0].Field != "url" {
+		t.closestLock.Lock()
+		if closest, ok := t.closest[r]; !ok || len(diffs) < len(closest) {
+			t.closest[r] = diffs
+		}
+		t.closestLock.Unlock()
+	}
+	return false
+}
+
+// diffVcrRequest returns the fields a request differs in from the recorded
+// request i. Bodies are only compared if body is set, and not for media
+// uploads. A difference in method, or in the URL other than in its query, is
+// the only one returned.
+func diffVcrRequest(s *vcrSanitizer, method, rawURL, contentType string, body *string, i cassette.Request) []VcrFieldDiff {
+	if method != i.Method {
+		return []VcrFieldDiff{{Field: "method", Recorded: i.Method, Requested: method}}
+	}
+
+	var diffs []VcrFieldDiff
+	reqURL, recURL := s.sanitizeURL(rawURL), s.sanitizeURL(i.URL)
+	if reqURL != recURL {
+		diffs = diffVcrURL(recURL, reqURL)
+		if len(diffs) == 1 && diffs[0].Field == "url" {
+			return diffs
+		}
+	}
+
+	// If body contains media, don't try to compare
+	if body == nil || strings.Contains(contentType, "multipart/related") {
+		return diffs
+	}
+	reqBody, recBody := s.sanitizeBody(*body, "REDACTED"), s.sanitizeBody(i.Body, "REDACTED")
+	// If body matches identically, we are done
+	if reqBody == recBody {
+		return diffs
+	}
+
+	// JSON might be the same, but reordered. Try parsing json and comparing
+	if strings.Contains(contentType, "application/json") {
+		var reqJson, recJson interface{}
+		if json.Unmarshal([]byte(reqBody), &reqJson) == nil && json.Unmarshal([]byte(recBody), &recJson) == nil {
+			return append(diffs, diffVcrJson("body", recJson, reqJson)...)
+		}
+	}
+	return append(diffs, VcrFieldDiff{Field: "body", Recorded: recBody, Requested: reqBody})
+}
+
+// diffVcrURL returns the query parameters that differ between two URLs, or a
+// single "url" difference if they differ in anything else.
+func diffVcrURL(recorded, requested string) []VcrFieldDiff {
+	whole := []VcrFieldDiff{{Field: "url", Recorded: recorded, Requested: requested}}
+	recURL, recErr := url.Parse(recorded)
+	reqURL, reqErr := url.Parse(requested)
+	if recErr != nil || reqErr != nil {
+		return whole
+	}
+	recQuery, reqQuery := recURL.Query(), reqURL.Query()
+	recURL.RawQuery, reqURL.RawQuery = "", ""
+	if recURL.String() != reqURL.String() {
+		return whole
+	}
+
+	var diffs []VcrFieldDiff
+	for _, k := range sortedVcrKeys(recQuery, reqQuery) {
+		if !reflect.DeepEqual(recQuery[k], reqQuery[k]) {
+			diffs = append(diffs, VcrFieldDiff{Field: "url.query." + k, Recorded: vcrQueryValue(recQuery[k]), Requested: vcrQueryValue(reqQuery[k])})
+		}
+	}
+	if len(diffs) == 0 {
+		// The same parameters, in a different order.
+		return whole
+	}
+	return diffs
+}
+
+func vcrQueryValue(values []string) interface{} {
+	switch len(values) {
+	case 0:
+		return nil
+	case 1:
+		return values[0]
+	default:
+		return values
+	}
+}
+
+// diffVcrJson returns the fields that differ between two decoded JSON values,
+// at any depth. Lists of different lengths are reported whole.
+func diffVcrJson(field string, recorded, requested interface{}) []VcrFieldDiff {
+	switch rec := recorded.(type) {
+	case map[string]interface{}:
+		req, ok := requested.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var diffs []VcrFieldDiff
+		for _, k := range sortedVcrKeys(rec, req) {
+			diffs = append(diffs, diffVcrJson(field+"."+k, rec[k], req[k])...)
+		}
+		return diffs
+	case []interface{}:
+		req, ok := requested.([]interface{})
+		if !ok || len(rec) != len(req) {
+			break
+		}
+		var diffs []VcrFieldDiff
+		for i := range rec {
+			diffs = append(diffs, diffVcrJson(fmt.Sprintf("%s[%d]", field, i), rec[i], req[i])...)
+		}
+		return diffs
+	}
+	if reflect.DeepEqual(recorded, requested) {
+		return nil
+	}
+	return []VcrFieldDiff{{Field: field, Recorded: recorded, Requested: requested}}
+}
+
+// sortedVcrKeys returns the keys of two maps, sorted.
+func sortedVcrKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
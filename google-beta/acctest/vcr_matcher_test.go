@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package acctest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+)
+
+func vcrTestRequest(t *testing.T, client *http.Client, url, body string) (string, error) {
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret-token")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	return string(b), err
+}
+
+func TestVcrTransport_SanitizesCassette(t *testing.T) {
+	t.Setenv("GOOGLE_PROJECT", "my-project-123")
+	t.Setenv("GOOGLE_SERVICE_ACCOUNT", "sa@my-project-123.iam.gserviceaccount.com")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"projects/my-project-123/topics/t","accessToken":"ya29.secret","privateKeyData":"c2VjcmV0"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette")
+	url := server.URL + "/v1/projects/my-project-123/topics/t?access_token=secret-token"
+	body := `{"labels":{"env":"test"},"member":"serviceAccount:sa@my-project-123.iam.gserviceaccount.com"}`
+
+	rec, err := newVcrTransport(path, recorder.ModeRecording, http.DefaultTransport, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vcrTestRequest(t, &http.Client{Transport: rec}, url, body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path + ".yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"my-project-123", "secret-token", "ya29.secret", "c2VjcmV0"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("expected %q to be scrubbed from the cassette, got:\n%s", secret, b)
+		}
+	}
+	if !strings.Contains(string(b), "VCR_PLACEHOLDER_GOOGLE_SERVICE_ACCOUNT") {
+		t.Errorf("expected the service account to be replaced with a placeholder, got:\n%s", b)
+	}
+
+	replay, err := newVcrTransport(path, recorder.ModeReplaying, http.DefaultTransport, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := vcrTestRequest(t, &http.Client{Transport: replay}, url, body)
+	if err != nil {
+		t.Fatalf("expected the sanitized cassette to match the request, got %v", err)
+	}
+	if !strings.Contains(got, `"projects/my-project-123/topics/t"`) {
+		t.Errorf("expected placeholders to be restored in the replayed response, got %s", got)
+	}
+	var res map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &res); err != nil {
+		t.Fatalf("replayed response is not valid JSON: %s", got)
+	}
+	if data, err := base64.StdEncoding.DecodeString(res["privateKeyData"].(string)); err != nil || string(data) != "REDACTED" {
+		t.Errorf("expected redacted response fields to decode, got %s", got)
+	}
+}
+
+func TestVcrTransport_ReportsDiffs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette")
+	c := cassette.New(path)
+	c.AddInteraction(&cassette.Interaction{
+		Request: cassette.Request{
+			Method: "POST",
+			URL:    "https://example.com/v1/things?pageSize=10",
+			Body:   `{"labels":{"env":"test"},"ports":["80"]}`,
+		},
+		Response: cassette.Response{Code: 200, Status: "200 OK"},
+	})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := newVcrTransport(path, recorder.ModeReplaying, http.DefaultTransport, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: replay}
+
+	_, err = vcrTestRequest(t, client, "https://example.com/v1/things?pageSize=20", `{"labels":{"env":"prod"},"ports":["80"]}`)
+	var mismatch *VcrMismatchError
+	if !errors.As(err, &mismatch) || !errors.Is(err, cassette.ErrInteractionNotFound) {
+		t.Fatalf("expected a VcrMismatchError, got %v", err)
+	}
+	want := []VcrFieldDiff{
+		{Field: "url.query.pageSize", Recorded: "10", Requested: "20"},
+		{Field: "body.labels.env", Recorded: "test", Requested: "prod"},
+	}
+	if !reflect.DeepEqual(mismatch.Diffs, want) {
+		t.Fatalf("expected diffs %v, got %v", want, mismatch.Diffs)
+	}
+
+	_, err = vcrTestRequest(t, client, "https://example.com/v1/others", `{}`)
+	if !errors.As(err, &mismatch) || len(mismatch.Diffs) != 0 {
+		t.Fatalf("expected a VcrMismatchError without diffs, got %v", err)
+	}
+
+	if _, err := vcrTestRequest(t, client, "https://example.com/v1/things?pageSize=10", `{"ports":["80"],"labels":{"env":"test"}}`); err != nil {
+		t.Fatalf("expected a reordered JSON body to match, got %v", err)
+	}
+}
+
+func TestDiffVcrJson(t *testing.T) {
+	recorded := map[string]interface{}{
+		"name":  "a",
+		"rules": []interface{}{map[string]interface{}{"ports": "80"}},
+		"tags":  []interface{}{"x"},
+	}
+	requested := map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{"ports": "443"}},
+		"tags":  []interface{}{"x", "y"},
+		"extra": true,
+	}
+	want := []VcrFieldDiff{
+		{Field: "body.extra", Recorded: nil, Requested: true},
+		{Field: "body.name", Recorded: "a", Requested: nil},
+		{Field: "body.rules[0].ports", Recorded: "80", Requested: "443"},
+		{Field: "body.tags", Recorded: []interface{}{"x"}, Requested: []interface{}{"x", "y"}},
+	}
+	if got := diffVcrJson("body", recorded, requested); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected diffs %v, got %v", want, got)
+	}
+}
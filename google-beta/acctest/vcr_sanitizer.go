@@ -0,0 +1,215 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package acctest
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dnaeon/go-vcr/cassette"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/envvar"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+// vcrPlaceholderPrefix starts the placeholders identifiers are replaced with
+// in cassettes.
+const vcrPlaceholderPrefix = "VCR_PLACEHOLDER_"
+
+// vcrRedactedResponseValue replaces credentials in response bodies. Response
+// bodies are replayed to the provider, which decodes fields such as
+// `privateKeyData` and `plaintext` as base64, so the value decodes to
+// "REDACTED".
+var vcrRedactedResponseValue = base64.StdEncoding.EncodeToString([]byte("REDACTED"))
+
+// vcrMinIdentifierLength is the length below which identifier values are not
+// replaced, as short values are likely to appear in cassettes by chance.
+const vcrMinIdentifierLength = 4
+
+// vcrSanitizedEnvVars are the environment variables whose values are replaced
+// with placeholders in cassettes. Each list is replaced with a placeholder
+// named after its first variable. More variables can be listed, comma
+// separated, in VCR_SANITIZE_ENV.
+var vcrSanitizedEnvVars = [][]string{
+	envvar.ProjectEnvVars,
+	envvar.ProjectNumberEnvVars,
+	envvar.FirestoreProjectEnvVars,
+	envvar.OrgEnvVars,
+	envvar.OrgTargetEnvVars,
+	envvar.CustIdEnvVars,
+	envvar.IdentityUserEnvVars,
+	envvar.ServiceAccountEnvVars,
+	envvar.BillingAccountEnvVars,
+	envvar.MasterBillingAccountEnvVars,
+}
+
+// vcrReplacement replaces an identifier value with a placeholder.
+type vcrReplacement struct {
+	value       string
+	placeholder string
+}
+
+// vcrSanitizer scrubs credentials and identifiers from cassettes. Credentials
+// in headers, query parameters and JSON bodies are redacted as they are in
+// structured logs, and identifier values are replaced with placeholders that
+// are turned back into the current values when the cassette is replayed.
+type vcrSanitizer struct {
+	// replacements are ordered longest value first, so that values containing
+	// other values are replaced whole.
+	replacements []vcrReplacement
+	// values are the values placeholders are replaced with on replay. When
+	// several values share a placeholder, the first one set is used.
+	values map[string]string
+}
+
+// newVcrSanitizer returns a vcrSanitizer for the identifiers in the current
+// environment.
+func newVcrSanitizer() *vcrSanitizer {
+	envVars := append([][]string{}, vcrSanitizedEnvVars...)
+	for _, name := range strings.Split(os.Getenv("VCR_SANITIZE_ENV"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			envVars = append(envVars, []string{name})
+		}
+	}
+
+	s := &vcrSanitizer{values: make(map[string]string)}
+	seen := make(map[string]bool)
+	for _, names := range envVars {
+		placeholder := vcrPlaceholderPrefix + names[0]
+		for _, name := range names {
+			value := os.Getenv(name)
+			if len(value) < vcrMinIdentifierLength || seen[value] {
+				continue
+			}
+			seen[value] = true
+			s.replacements = append(s.replacements, vcrReplacement{value: value, placeholder: placeholder})
+			// Identifiers in URLs may be escaped, such as the "@" of a service
+			// account email. Escaped values get their own placeholder, so that
+			// they are restored escaped.
+			for _, escaped := range []string{url.PathEscape(value), url.QueryEscape(value)} {
+				if escaped != value && !seen[escaped] {
+					seen[escaped] = true
+					s.replacements = append(s.replacements, vcrReplacement{value: escaped, placeholder: placeholder + "_ESCAPED"})
+				}
+			}
+		}
+	}
+	for _, r := range s.replacements {
+		if _, ok := s.values[r.placeholder]; !ok {
+			s.values[r.placeholder] = r.value
+		}
+	}
+	sort.SliceStable(s.replacements, func(i, j int) bool {
+		return len(s.replacements[i].value) > len(s.replacements[j].value)
+	})
+	return s
+}
+
+// sanitize replaces identifier values in str with their placeholders.
+func (s *vcrSanitizer) sanitize(str string) string {
+	for _, r := range s.replacements {
+		str = strings.ReplaceAll(str, r.value, r.placeholder)
+	}
+	return str
+}
+
+// desanitize replaces placeholders in str with the current identifier values.
+func (s *vcrSanitizer) desanitize(str string) string {
+	if !strings.Contains(str, vcrPlaceholderPrefix) {
+		return str
+	}
+	// Placeholders are replaced longest first, so that the placeholder of an
+	// escaped value is not replaced by that of the unescaped one.
+	placeholders := make([]string, 0, len(s.values))
+	for placeholder := range s.values {
+		placeholders = append(placeholders, placeholder)
+	}
+	sort.Slice(placeholders, func(i, j int) bool {
+		return len(placeholders[i]) > len(placeholders[j])
+	})
+	for _, placeholder := range placeholders {
+		str = strings.ReplaceAll(str, placeholder, s.values[placeholder])
+	}
+	return str
+}
+
+// sanitizeURL redacts credentials in the query of rawURL and replaces the
+// identifiers in it.
+func (s *vcrSanitizer) sanitizeURL(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		rawURL = transport_tpg.RedactURL(u)
+	}
+	return s.sanitize(rawURL)
+}
+
+// sanitizeBody redacts credentials in body, if it is JSON, with value and
+// replaces the identifiers in it.
+func (s *vcrSanitizer) sanitizeBody(body, value string) string {
+	if body == "" {
+		return body
+	}
+	if redacted, ok := transport_tpg.RedactJSONWithValue([]byte(body), value); ok {
+		body = string(redacted)
+	}
+	return s.sanitize(body)
+}
+
+// sanitizeHeader redacts credentials in h and replaces the identifiers in it.
+func (s *vcrSanitizer) sanitizeHeader(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	sanitized := transport_tpg.RedactHTTPHeader(h)
+	for _, values := range sanitized {
+		for i, v := range values {
+			values[i] = s.sanitize(v)
+		}
+	}
+	return sanitized
+}
+
+// desanitizeHeader returns a copy of h with placeholders replaced with the
+// current identifier values.
+func (s *vcrSanitizer) desanitizeHeader(h http.Header) http.Header {
+	desanitized := h.Clone()
+	for _, values := range desanitized {
+		for i, v := range values {
+			values[i] = s.desanitize(v)
+		}
+	}
+	return desanitized
+}
+
+func (s *vcrSanitizer) sanitizeInteraction(i *cassette.Interaction) {
+	i.Request.URL = s.sanitizeURL(i.Request.URL)
+	i.Request.Headers = s.sanitizeHeader(i.Request.Headers)
+	i.Request.Body = s.sanitizeBody(i.Request.Body, "REDACTED")
+	for _, values := range i.Request.Form {
+		for j, v := range values {
+			values[j] = s.sanitize(v)
+		}
+	}
+	i.Response.Headers = s.sanitizeHeader(i.Response.Headers)
+	i.Response.Body = s.sanitizeBody(i.Response.Body, vcrRedactedResponseValue)
+}
+
+// sanitizeCassette sanitizes the cassette saved at path, without the .yaml
+// extension, in place. A missing cassette is left alone, as one is not saved
+// if no requests were made.
+func (s *vcrSanitizer) sanitizeCassette(path string) error {
+	c, err := cassette.Load(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, i := range c.Interactions {
+		s.sanitizeInteraction(i)
+	}
+	return c.Save()
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
+)
+
+var _ function.Function = CidrOverlapsFunction{}
+
+// cidrOverlapsAttributeTypes are the attributes of the object returned by
+// cidr_overlaps.
+var cidrOverlapsAttributeTypes = map[string]attr.Type{
+	"overlaps":     types.BoolType,
+	"a_contains_b": types.BoolType,
+	"b_contains_a": types.BoolType,
+}
+
+func NewCidrOverlapsFunction() function.Function {
+	return &CidrOverlapsFunction{
+		name: "cidr_overlaps",
+	}
+}
+
+type CidrOverlapsFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f CidrOverlapsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f CidrOverlapsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Checks whether two IP ranges overlap.",
+		Description: "Takes two IPv4 or IPv6 ranges in CIDR notation, such as \"10.0.0.0/16\" and \"10.0.4.0/24\", and returns an object with whether the ranges overlap, and whether either range contains the other. Ranges of different IP versions never overlap. Netmasks alone, such as \"/24\", are not accepted, as the range the API picks for them is not known in advance.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "a",
+				Description: "An IP range in CIDR notation.",
+			},
+			function.StringParameter{
+				Name:        "b",
+				Description: "An IP range in CIDR notation.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: cidrOverlapsAttributeTypes,
+		},
+	}
+}
+
+func (f CidrOverlapsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var a, b string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	var ipnets []*net.IPNet
+	for i, arg := range []string{a, b} {
+		if _, errs := verify.ValidateIpCidrRange(arg, "input"); len(errs) > 0 {
+			msg := fmt.Sprintf("The input string \"%s\" is not a valid IP CIDR range.", arg)
+			if strings.HasPrefix(arg, "/") {
+				msg += " Netmasks alone are not supported."
+			}
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i), msg))
+			return
+		}
+		_, ipnet, _ := net.ParseCIDR(arg)
+		ipnets = append(ipnets, ipnet)
+	}
+
+	aContainsB := tpgresource.CidrContains(ipnets[0], ipnets[1])
+	bContainsA := tpgresource.CidrContains(ipnets[1], ipnets[0])
+	result, diags := types.ObjectValue(cidrOverlapsAttributeTypes, map[string]attr.Value{
+		"overlaps":     types.BoolValue(aContainsB || bContainsA),
+		"a_contains_b": types.BoolValue(aContainsB),
+		"b_contains_a": types.BoolValue(bContainsA),
+	})
+	resp.Error = function.ConcatFuncErrors(function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
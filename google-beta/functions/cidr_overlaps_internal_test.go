@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFunctionRun_cidr_overlaps(t *testing.T) {
+	t.Parallel()
+
+	overlaps := func(overlaps, aContainsB, bContainsA bool) function.ResultData {
+		return function.NewResultData(types.ObjectValueMust(cidrOverlapsAttributeTypes, map[string]attr.Value{
+			"overlaps":     types.BoolValue(overlaps),
+			"a_contains_b": types.BoolValue(aContainsB),
+			"b_contains_a": types.BoolValue(bContainsA),
+		}))
+	}
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it returns that disjoint ranges do not overlap": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.0/24"), types.StringValue("10.0.1.0/24")}),
+			},
+			expected: function.RunResponse{
+				Result: overlaps(false, false, false),
+			},
+		},
+		"it returns that a range contains a smaller range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.0/16"), types.StringValue("10.0.4.0/24")}),
+			},
+			expected: function.RunResponse{
+				Result: overlaps(true, true, false),
+			},
+		},
+		"it returns that a range is contained in a larger range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.4.8/29"), types.StringValue("10.0.0.0/8")}),
+			},
+			expected: function.RunResponse{
+				Result: overlaps(true, false, true),
+			},
+		},
+		"it returns that equal ranges contain each other": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.1/24"), types.StringValue("10.0.0.0/24")}),
+			},
+			expected: function.RunResponse{
+				Result: overlaps(true, true, true),
+			},
+		},
+		"it supports IPv6 ranges": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("2600:1900::/48"), types.StringValue("2600:1900:0:1::/64")}),
+			},
+			expected: function.RunResponse{
+				Result: overlaps(true, true, false),
+			},
+		},
+		"it returns that ranges of different IP versions do not overlap": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("0.0.0.0/0"), types.StringValue("::/0")}),
+			},
+			expected: function.RunResponse{
+				Result: overlaps(false, false, false),
+			},
+		},
+		"it returns an error when given a netmask": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.0/24"), types.StringValue("/24")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(cidrOverlapsAttributeTypes)),
+				Error:  function.NewArgumentFuncError(1, "The input string \"/24\" is not a valid IP CIDR range. Netmasks alone are not supported."),
+			},
+		},
+		"it returns an error when given input is not a range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.0"), types.StringValue("10.0.0.0/24")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(cidrOverlapsAttributeTypes)),
+				Error:  function.NewArgumentFuncError(0, "The input string \"10.0.0.0\" is not a valid IP CIDR range."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(cidrOverlapsAttributeTypes)),
+			}
+
+			// Act
+			NewCidrOverlapsFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
+)
+
+var _ function.Function = CidrSubnetsNonOverlappingFunction{}
+
+// cidrSubnetAttributeTypes are the attributes of the objects returned by
+// cidr_subnets_non_overlapping.
+var cidrSubnetAttributeTypes = map[string]attr.Type{
+	"ip_cidr_range":        types.StringType,
+	"prefix_length":        types.Int64Type,
+	"gateway_address":      types.StringType,
+	"usable_address_count": types.Int64Type,
+}
+
+const (
+	// The smallest IPv4 range a subnetwork can have
+	maxSubnetworkPrefixLength = 29
+	// The addresses GCP reserves in each IPv4 subnetwork range: the network
+	// and gateway addresses, the second-to-last address and the broadcast
+	// address
+	subnetworkReservedAddressCount = 4
+)
+
+func NewCidrSubnetsNonOverlappingFunction() function.Function {
+	return &CidrSubnetsNonOverlappingFunction{
+		name: "cidr_subnets_non_overlapping",
+	}
+}
+
+type CidrSubnetsNonOverlappingFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f CidrSubnetsNonOverlappingFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f CidrSubnetsNonOverlappingFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Allocates non-overlapping IPv4 subnetwork ranges of the given sizes within a parent range.",
+		Description: "Takes an IPv4 parent range, such as \"10.0.0.0/16\", and a list of prefix lengths, such as [20, 24, 28], and allocates a range of each size within the parent range so that no two ranges overlap. Ranges are packed from the start of the parent range, largest first, so no addresses are wasted between them, and are returned in the order of the prefix lengths. Each range is returned as an object with its ip_cidr_range and prefix_length, the address of its gateway, and the number of addresses left for use after the four addresses GCP reserves in each subnetwork range. Prefix lengths must be at most 29, the smallest range GCP allows for a subnetwork.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "parent",
+				Description: "The IPv4 range to allocate ranges in, in CIDR notation.",
+			},
+			function.ListParameter{
+				Name:        "sizes",
+				Description: "The prefix lengths of the ranges to allocate.",
+				ElementType: types.Int64Type,
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.ObjectType{AttrTypes: cidrSubnetAttributeTypes},
+		},
+	}
+}
+
+func (f CidrSubnetsNonOverlappingFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var parent string
+	var sizes []int64
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &parent, &sizes))
+	if resp.Error != nil {
+		return
+	}
+
+	ipnet, err := parseIpv4Network(parent)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+	parentPrefixLength, _ := ipnet.Mask.Size()
+	parentStart := uint64(binary.BigEndian.Uint32(ipnet.IP.To4()))
+	parentEnd := parentStart + uint64(1)<<(32-parentPrefixLength)
+
+	for _, size := range sizes {
+		if size < int64(parentPrefixLength) || size > maxSubnetworkPrefixLength {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("The prefix length %d is not between the prefix length of the parent range, %d, and %d, the smallest range GCP allows for a subnetwork.", size, parentPrefixLength, maxSubnetworkPrefixLength)))
+			return
+		}
+	}
+
+	// Allocating the largest ranges first keeps every range aligned without
+	// leaving gaps between them
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sizes[order[i]] < sizes[order[j]]
+	})
+
+	subnets := make([]attr.Value, len(sizes))
+	next := parentStart
+	for _, i := range order {
+		count := uint64(1) << (32 - sizes[i])
+		if next+count > parentEnd {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("The ranges do not fit in the parent range \"%s\".", parent)))
+			return
+		}
+
+		subnet, diags := types.ObjectValue(cidrSubnetAttributeTypes, map[string]attr.Value{
+			"ip_cidr_range":        types.StringValue(fmt.Sprintf("%s/%d", ipv4FromUint(next), sizes[i])),
+			"prefix_length":        types.Int64Value(sizes[i]),
+			"gateway_address":      types.StringValue(ipv4FromUint(next + 1).String()),
+			"usable_address_count": types.Int64Value(int64(count - subnetworkReservedAddressCount)),
+		})
+		resp.Error = function.ConcatFuncErrors(function.FuncErrorFromDiags(ctx, diags))
+		if resp.Error != nil {
+			return
+		}
+		subnets[i] = subnet
+		next += count
+	}
+
+	result, diags := types.ListValue(types.ObjectType{AttrTypes: cidrSubnetAttributeTypes}, subnets)
+	resp.Error = function.ConcatFuncErrors(function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// parseIpv4Network parses an IPv4 range in CIDR notation, which must be the
+// network address of the range, as GCP requires for subnetwork ranges.
+func parseIpv4Network(cidr string) (*net.IPNet, error) {
+	if _, errs := verify.ValidateIpCidrRange(cidr, "input"); len(errs) > 0 {
+		return nil, fmt.Errorf("The input string \"%s\" is not a valid IP CIDR range.", cidr)
+	}
+	_, ipnet, _ := net.ParseCIDR(cidr)
+	if ipnet.IP.To4() == nil {
+		return nil, fmt.Errorf("The input string \"%s\" is not an IPv4 range.", cidr)
+	}
+	if ipnet.String() != cidr {
+		return nil, fmt.Errorf("The input string \"%s\" is not the network address of its range. Expected \"%s\".", cidr, ipnet)
+	}
+	return ipnet, nil
+}
+
+func ipv4FromUint(v uint64) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, uint32(v))
+	return ip
+}
@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFunctionRun_cidr_subnets_non_overlapping(t *testing.T) {
+	t.Parallel()
+
+	subnetType := types.ObjectType{AttrTypes: cidrSubnetAttributeTypes}
+	subnet := func(ipCidrRange string, prefixLength int64, gatewayAddress string, usableAddressCount int64) attr.Value {
+		return types.ObjectValueMust(cidrSubnetAttributeTypes, map[string]attr.Value{
+			"ip_cidr_range":        types.StringValue(ipCidrRange),
+			"prefix_length":        types.Int64Value(prefixLength),
+			"gateway_address":      types.StringValue(gatewayAddress),
+			"usable_address_count": types.Int64Value(usableAddressCount),
+		})
+	}
+	sizes := func(v ...int64) attr.Value {
+		elems := make([]attr.Value, len(v))
+		for i, size := range v {
+			elems[i] = types.Int64Value(size)
+		}
+		return types.ListValueMust(types.Int64Type, elems)
+	}
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it allocates ranges in the order of the sizes": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.0/16"), sizes(24, 20, 28)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListValueMust(subnetType, []attr.Value{
+					subnet("10.0.16.0/24", 24, "10.0.16.1", 252),
+					subnet("10.0.0.0/20", 20, "10.0.0.1", 4092),
+					subnet("10.0.17.0/28", 28, "10.0.17.1", 12),
+				})),
+			},
+		},
+		"it allocates the whole parent range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("192.168.0.0/23"), sizes(24, 24)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListValueMust(subnetType, []attr.Value{
+					subnet("192.168.0.0/24", 24, "192.168.0.1", 252),
+					subnet("192.168.1.0/24", 24, "192.168.1.1", 252),
+				})),
+			},
+		},
+		"it returns an error when the ranges do not fit": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("192.168.0.0/23"), sizes(24, 24, 29)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(subnetType)),
+				Error:  function.NewArgumentFuncError(1, "The ranges do not fit in the parent range \"192.168.0.0/23\"."),
+			},
+		},
+		"it returns an error when a size is smaller than GCP allows": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.0/16"), sizes(30)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(subnetType)),
+				Error:  function.NewArgumentFuncError(1, "The prefix length 30 is not between the prefix length of the parent range, 16, and 29, the smallest range GCP allows for a subnetwork."),
+			},
+		},
+		"it returns an error when a size is larger than the parent range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.0/16"), sizes(8)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(subnetType)),
+				Error:  function.NewArgumentFuncError(1, "The prefix length 8 is not between the prefix length of the parent range, 16, and 29, the smallest range GCP allows for a subnetwork."),
+			},
+		},
+		"it returns an error when the parent is not a network address": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("10.0.0.1/16"), sizes(24)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(subnetType)),
+				Error:  function.NewArgumentFuncError(0, "The input string \"10.0.0.1/16\" is not the network address of its range. Expected \"10.0.0.0/16\"."),
+			},
+		},
+		"it returns an error when the parent is an IPv6 range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("2600:1900::/48"), sizes(64)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(subnetType)),
+				Error:  function.NewArgumentFuncError(0, "The input string \"2600:1900::/48\" is not an IPv4 range."),
+			},
+		},
+		"it returns an error when the parent is not a range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("/16"), sizes(24)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(subnetType)),
+				Error:  function.NewArgumentFuncError(0, "The input string \"/16\" is not a valid IP CIDR range."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(subnetType)),
+			}
+
+			// Act
+			NewCidrSubnetsNonOverlappingFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
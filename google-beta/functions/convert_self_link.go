@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+var _ function.Function = ConvertSelfLinkFunction{}
+
+var computeVersionRegex = regexp.MustCompile("/compute/[a-zA-Z0-9]*/$")
+
+// computeIdRegex matches the ids of global, regional and zonal Compute Engine
+// resources.
+var computeIdRegex = regexp.MustCompile("^projects/[^/]+/(global|regions/[^/]+|zones/[^/]+)/[^/]+/[^/]+")
+
+func NewConvertSelfLinkFunction() function.Function {
+	return &ConvertSelfLinkFunction{
+		name: "convert_self_link",
+	}
+}
+
+type ConvertSelfLinkFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f ConvertSelfLinkFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f ConvertSelfLinkFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a self link or resource id to another format.",
+		Description: "Takes a self link or a resource id starting with \"projects/\", and a format. The \"id\" format returns the resource id, e.g. returning \"projects/my-project/zones/us-central1-a/instances/my-instance\" for \"https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance\". The \"self_link\", \"v1\" and \"beta\" formats are only supported for Compute Engine resources, and return the self link using the provider's Compute Engine base path, or that base path with the API version set to \"v1\" or \"beta\". Resource ids given with these formats must be of global, regional or zonal Compute Engine resources. The universe domain set in GOOGLE_UNIVERSE_DOMAIN is honored.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "link",
+				Description: "A self link, or a resource id starting with \"projects/\".",
+			},
+			function.StringParameter{
+				Name:        "format",
+				Description: "The format to convert to. One of \"id\", \"self_link\", \"v1\" or \"beta\".",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f ConvertSelfLinkFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var link, format string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &link, &format))
+	if resp.Error != nil {
+		return
+	}
+
+	id := link
+	if !strings.HasPrefix(link, "projects/") {
+		var err error
+		id, err = tpgresource.GetRelativePath(link)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" is not a valid self link or resource id. Expected it to contain \"projects/\".", link)))
+			return
+		}
+	}
+
+	var version string
+	switch format {
+	case "id":
+		resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, id))
+		return
+	case "self_link":
+	case "v1", "beta":
+		version = format
+	default:
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("The format \"%s\" is not supported. Expected one of \"id\", \"self_link\", \"v1\" or \"beta\".", format)))
+		return
+	}
+
+	if id != link && !strings.Contains(link, "/compute/") {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" is not a Compute Engine self link. The \"%s\" format is only supported for Compute Engine resources.", link, format)))
+		return
+	}
+	if id == link && !computeIdRegex.MatchString(id) {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" is not a Compute Engine resource id. The \"%s\" format is only supported for Compute Engine resources.", link, format)))
+		return
+	}
+
+	basePath, _ := basePathForService(transport_tpg.ComputeBasePathKey)
+	if version != "" {
+		basePath = computeVersionRegex.ReplaceAllString(basePath, "/compute/"+version+"/")
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, basePath+id))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestFunctionRun_convert_self_link(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it returns the id of a self link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance"), types.StringValue("id")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("projects/my-project/zones/us-central1-a/instances/my-instance")),
+			},
+		},
+		"it returns the self link of an id": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("projects/my-project/global/networks/my-network"), types.StringValue("self_link")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://compute.googleapis.com/compute/beta/projects/my-project/global/networks/my-network")),
+			},
+		},
+		"it returns the v1 self link of a self link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("https://compute.googleapis.com/compute/beta/projects/my-project/global/networks/my-network"), types.StringValue("v1")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://compute.googleapis.com/compute/v1/projects/my-project/global/networks/my-network")),
+			},
+		},
+		"it returns the beta self link of a self link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance"), types.StringValue("beta")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://compute.googleapis.com/compute/beta/projects/my-project/zones/us-central1-a/instances/my-instance")),
+			},
+		},
+		"it returns the id of a non compute self link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic"), types.StringValue("id")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("projects/my-project/topics/my-topic")),
+			},
+		},
+		"it returns an error for a non compute self link in the v1 format": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic"), types.StringValue("v1")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(0, "The input string \"https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic\" is not a Compute Engine self link. The \"v1\" format is only supported for Compute Engine resources."),
+			},
+		},
+		"it returns an error for a non compute id in the self_link format": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("projects/my-project/topics/my-topic"), types.StringValue("self_link")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(0, "The input string \"projects/my-project/topics/my-topic\" is not a Compute Engine resource id. The \"self_link\" format is only supported for Compute Engine resources."),
+			},
+		},
+		"it returns an error for an invalid link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("my-network"), types.StringValue("id")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(0, "The input string \"my-network\" is not a valid self link or resource id. Expected it to contain \"projects/\"."),
+			},
+		},
+		"it returns an error for an unsupported format": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("projects/my-project/global/networks/my-network"), types.StringValue("v2")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(1, "The format \"v2\" is not supported. Expected one of \"id\", \"self_link\", \"v1\" or \"beta\"."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(basetypes.StringValue{}),
+			}
+
+			// Act
+			NewConvertSelfLinkFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = GkeMaxPodsToPodCidrSizeFunction{}
+
+// gkePodCidrSizeAttributeTypes are the attributes of the object returned by
+// gke_max_pods_to_pod_cidr_size.
+var gkePodCidrSizeAttributeTypes = map[string]attr.Type{
+	"node_prefix_length":      types.Int64Type,
+	"pod_prefix_length":       types.Int64Type,
+	"cluster_ipv4_cidr_block": types.StringType,
+	"max_nodes":               types.Int64Type,
+}
+
+const (
+	minGkeMaxPodsPerNode = 8
+	maxGkeMaxPodsPerNode = 256
+	// The largest Pod range GKE allows
+	minGkePodPrefixLength = 9
+)
+
+func NewGkeMaxPodsToPodCidrSizeFunction() function.Function {
+	return &GkeMaxPodsToPodCidrSizeFunction{
+		name: "gke_max_pods_to_pod_cidr_size",
+	}
+}
+
+type GkeMaxPodsToPodCidrSizeFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f GkeMaxPodsToPodCidrSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f GkeMaxPodsToPodCidrSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns the size of the Pod range a GKE cluster needs for a maximum number of Pods per node and a number of nodes.",
+		Description: "Takes the maximum number of Pods per node, between 8 and 256, and the number of nodes of a GKE cluster. GKE gives each node a range with at least twice as many addresses as its maximum number of Pods, e.g. a /24 for 110 Pods. Returns an object with the prefix length of each node's range, the prefix length of the smallest Pod range holding the ranges of all nodes, that prefix length as a netmask such as \"/14\", usable as the cluster_ipv4_cidr_block of a google_container_cluster, and the number of nodes that range can hold.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "max_pods_per_node",
+				Description: "The maximum number of Pods per node.",
+			},
+			function.Int64Parameter{
+				Name:        "nodes",
+				Description: "The number of nodes the Pod range must hold.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: gkePodCidrSizeAttributeTypes,
+		},
+	}
+}
+
+func (f GkeMaxPodsToPodCidrSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var maxPodsPerNode, nodes int64
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &maxPodsPerNode, &nodes))
+	if resp.Error != nil {
+		return
+	}
+
+	if maxPodsPerNode < minGkeMaxPodsPerNode || maxPodsPerNode > maxGkeMaxPodsPerNode {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The maximum number of Pods per node %d is not between %d and %d.", maxPodsPerNode, minGkeMaxPodsPerNode, maxGkeMaxPodsPerNode)))
+		return
+	}
+	if nodes < 1 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("The number of nodes %d must be at least 1.", nodes)))
+		return
+	}
+
+	nodePrefixLength := int64(32 - ceilLog2(uint64(2*maxPodsPerNode)))
+	podPrefixLength := nodePrefixLength - int64(ceilLog2(uint64(nodes)))
+	if podPrefixLength < minGkePodPrefixLength {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("The number of nodes %d needs a /%d Pod range, which is larger than the /%d GKE allows.", nodes, podPrefixLength, minGkePodPrefixLength)))
+		return
+	}
+
+	result, diags := types.ObjectValue(gkePodCidrSizeAttributeTypes, map[string]attr.Value{
+		"node_prefix_length":      types.Int64Value(nodePrefixLength),
+		"pod_prefix_length":       types.Int64Value(podPrefixLength),
+		"cluster_ipv4_cidr_block": types.StringValue(fmt.Sprintf("/%d", podPrefixLength)),
+		"max_nodes":               types.Int64Value(int64(1) << (nodePrefixLength - podPrefixLength)),
+	})
+	resp.Error = function.ConcatFuncErrors(function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// ceilLog2 returns the smallest n such that 1<<n >= v.
+func ceilLog2(v uint64) int {
+	if v <= 1 {
+		return 0
+	}
+	return bits.Len64(v - 1)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFunctionRun_gke_max_pods_to_pod_cidr_size(t *testing.T) {
+	t.Parallel()
+
+	size := func(nodePrefixLength, podPrefixLength int64, clusterIpv4CidrBlock string, maxNodes int64) function.ResultData {
+		return function.NewResultData(types.ObjectValueMust(gkePodCidrSizeAttributeTypes, map[string]attr.Value{
+			"node_prefix_length":      types.Int64Value(nodePrefixLength),
+			"pod_prefix_length":       types.Int64Value(podPrefixLength),
+			"cluster_ipv4_cidr_block": types.StringValue(clusterIpv4CidrBlock),
+			"max_nodes":               types.Int64Value(maxNodes),
+		}))
+	}
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it returns the size for the default maximum number of Pods": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(110), types.Int64Value(1000)}),
+			},
+			expected: function.RunResponse{
+				Result: size(24, 14, "/14", 1024),
+			},
+		},
+		"it returns the size for the smallest maximum number of Pods": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(8), types.Int64Value(1)}),
+			},
+			expected: function.RunResponse{
+				Result: size(28, 28, "/28", 1),
+			},
+		},
+		"it returns the size for the largest maximum number of Pods": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(256), types.Int64Value(3)}),
+			},
+			expected: function.RunResponse{
+				Result: size(23, 21, "/21", 4),
+			},
+		},
+		"it returns an error when the maximum number of Pods is out of range": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(300), types.Int64Value(3)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(gkePodCidrSizeAttributeTypes)),
+				Error:  function.NewArgumentFuncError(0, "The maximum number of Pods per node 300 is not between 8 and 256."),
+			},
+		},
+		"it returns an error when the number of nodes is not positive": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(110), types.Int64Value(0)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(gkePodCidrSizeAttributeTypes)),
+				Error:  function.NewArgumentFuncError(1, "The number of nodes 0 must be at least 1."),
+			},
+		},
+		"it returns an error when the Pod range is too large": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.Int64Value(110), types.Int64Value(40000)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(gkePodCidrSizeAttributeTypes)),
+				Error:  function.NewArgumentFuncError(1, "The number of nodes 40000 needs a /8 Pod range, which is larger than the /9 GKE allows."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(gkePodCidrSizeAttributeTypes)),
+			}
+
+			// Act
+			NewGkeMaxPodsToPodCidrSizeFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
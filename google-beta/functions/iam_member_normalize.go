@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgiamresource"
+)
+
+var _ function.Function = IamMemberNormalizeFunction{}
+
+func NewIamMemberNormalizeFunction() function.Function {
+	return &IamMemberNormalizeFunction{
+		name: "iam_member_normalize",
+	}
+}
+
+type IamMemberNormalizeFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f IamMemberNormalizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f IamMemberNormalizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns an IAM member with the casing IAM stores it with.",
+		Description: "Takes a single string argument, which should be an IAM member such as \"user:Jane@Example.com\". IAM ignores the casing of most member values, and returns them lowercased in policies, so this function lowercases the value of the member, e.g. returning \"user:jane@example.com\". The member type, and the values of case sensitive members such as allUsers, allAuthenticatedUsers, principal, principalSet and principalHierarchy members, are left unchanged. This matches how the provider compares members in IAM resources.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "member",
+				Description: "An IAM member, in the \"{type}:{value}\" format used in IAM policy bindings, optionally prefixed with \"deleted:\".",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f IamMemberNormalizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var arg0 string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.GetArgument(ctx, 0, &arg0))
+	if resp.Error != nil {
+		return
+	}
+
+	if arg0 == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "The input string cannot be empty."))
+		return
+	}
+
+	if !strings.Contains(arg0, ":") && !iamMemberValuelessTypes[arg0] {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" is not a valid IAM member. Expected the format \"{type}:{value}\".", arg0)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, tpgiamresource.NormalizeIamMemberCasing(arg0)))
+}
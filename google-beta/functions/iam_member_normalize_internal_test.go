@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestFunctionRun_iam_member_normalize(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it lowercases the value of a user member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("user:Jane@Example.com")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("user:jane@example.com")),
+			},
+		},
+		"it lowercases the value of a deleted member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("deleted:serviceAccount:SA@p.iam.gserviceaccount.com?uid=123")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("deleted:serviceAccount:sa@p.iam.gserviceaccount.com?uid=123")),
+			},
+		},
+		"it leaves case sensitive members unchanged": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("principal://iam.googleapis.com/locations/global/workforcePools/pool/subject/Jane")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("principal://iam.googleapis.com/locations/global/workforcePools/pool/subject/Jane")),
+			},
+		},
+		"it returns an error when given input is not a member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("Jane@Example.com")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(0, "The input string \"Jane@Example.com\" is not a valid IAM member. Expected the format \"{type}:{value}\"."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(basetypes.StringValue{}),
+			}
+
+			// Act
+			NewIamMemberNormalizeFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = IamMemberParseFunction{}
+
+// iamMemberParseAttributeTypes are the attributes of the object returned by
+// iam_member_parse.
+var iamMemberParseAttributeTypes = map[string]attr.Type{
+	"type":          types.StringType,
+	"value":         types.StringType,
+	"email":         types.StringType,
+	"domain":        types.StringType,
+	"uid":           types.StringType,
+	"deleted":       types.BoolType,
+	"principal_set": types.BoolType,
+}
+
+// iamMemberEmailTypes are the member types whose value is an email.
+var iamMemberEmailTypes = map[string]bool{
+	"user":           true,
+	"serviceAccount": true,
+	"group":          true,
+}
+
+// iamMemberValuelessTypes are the member types that are not followed by a
+// value.
+var iamMemberValuelessTypes = map[string]bool{
+	"allUsers":              true,
+	"allAuthenticatedUsers": true,
+}
+
+func NewIamMemberParseFunction() function.Function {
+	return &IamMemberParseFunction{
+		name: "iam_member_parse",
+	}
+}
+
+type IamMemberParseFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f IamMemberParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f IamMemberParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Parses an IAM member into its type, value and the details of the principal it identifies.",
+		Description: "Takes a single string argument, which should be an IAM member such as \"user:jane@example.com\", \"domain:example.com\", \"deleted:serviceAccount:my-sa@my-project.iam.gserviceaccount.com?uid=123\" or \"principalSet://iam.googleapis.com/locations/global/workforcePools/my-pool/*\". Returns an object with the member's type, its value, the email and domain of the principal where the member has them, the uid of a deleted member, and whether the member is deleted or a principal set.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "member",
+				Description: "An IAM member, in the \"{type}:{value}\" format used in IAM policy bindings, optionally prefixed with \"deleted:\".",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: iamMemberParseAttributeTypes,
+		},
+	}
+}
+
+func (f IamMemberParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var arg0 string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.GetArgument(ctx, 0, &arg0))
+	if resp.Error != nil {
+		return
+	}
+
+	if arg0 == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "The input string cannot be empty."))
+		return
+	}
+
+	member := arg0
+	deleted := strings.HasPrefix(member, "deleted:")
+	member = strings.TrimPrefix(member, "deleted:")
+
+	memberType, value, hasValue := strings.Cut(member, ":")
+	if memberType == "" || (!hasValue && !iamMemberValuelessTypes[memberType]) || (hasValue && value == "") {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" is not a valid IAM member. Expected the format \"{type}:{value}\".", arg0)))
+		return
+	}
+
+	uid := types.StringNull()
+	if deleted {
+		if v, u, ok := strings.Cut(value, "?uid="); ok {
+			value = v
+			uid = types.StringValue(u)
+		}
+	}
+
+	email, domain := types.StringNull(), types.StringNull()
+	if iamMemberEmailTypes[memberType] {
+		email = types.StringValue(value)
+		if _, d, ok := strings.Cut(value, "@"); ok {
+			domain = types.StringValue(d)
+		}
+	} else if memberType == "domain" {
+		domain = types.StringValue(value)
+	}
+
+	result, diags := types.ObjectValue(iamMemberParseAttributeTypes, map[string]attr.Value{
+		"type":          types.StringValue(memberType),
+		"value":         types.StringValue(value),
+		"email":         email,
+		"domain":        domain,
+		"uid":           uid,
+		"deleted":       types.BoolValue(deleted),
+		"principal_set": types.BoolValue(memberType == "principalSet"),
+	})
+	resp.Error = function.ConcatFuncErrors(function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFunctionRun_iam_member_parse(t *testing.T) {
+	t.Parallel()
+
+	parsed := func(memberType, value string, email, domain, uid attr.Value, deleted bool) function.ResultData {
+		return function.NewResultData(types.ObjectValueMust(iamMemberParseAttributeTypes, map[string]attr.Value{
+			"type":          types.StringValue(memberType),
+			"value":         types.StringValue(value),
+			"email":         email,
+			"domain":        domain,
+			"uid":           uid,
+			"deleted":       types.BoolValue(deleted),
+			"principal_set": types.BoolValue(memberType == "principalSet"),
+		}))
+	}
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it parses a user member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("user:jane@example.com")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("user", "jane@example.com", types.StringValue("jane@example.com"), types.StringValue("example.com"), types.StringNull(), false),
+			},
+		},
+		"it parses a deleted service account member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("deleted:serviceAccount:sa@p.iam.gserviceaccount.com?uid=123")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("serviceAccount", "sa@p.iam.gserviceaccount.com", types.StringValue("sa@p.iam.gserviceaccount.com"), types.StringValue("p.iam.gserviceaccount.com"), types.StringValue("123"), true),
+			},
+		},
+		"it parses a domain member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("domain:example.com")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("domain", "example.com", types.StringNull(), types.StringValue("example.com"), types.StringNull(), false),
+			},
+		},
+		"it parses a principal set member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("principalSet://iam.googleapis.com/locations/global/workforcePools/pool/*")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("principalSet", "//iam.googleapis.com/locations/global/workforcePools/pool/*", types.StringNull(), types.StringNull(), types.StringNull(), false),
+			},
+		},
+		"it parses allUsers": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("allUsers")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("allUsers", "", types.StringNull(), types.StringNull(), types.StringNull(), false),
+			},
+		},
+		"it returns an error when given input is not a member": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("jane@example.com")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(iamMemberParseAttributeTypes)),
+				Error:  function.NewArgumentFuncError(0, "The input string \"jane@example.com\" is not a valid IAM member. Expected the format \"{type}:{value}\"."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(iamMemberParseAttributeTypes)),
+			}
+
+			// Act
+			NewIamMemberParseFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = IamRoleParseFunction{}
+
+// iamRoleParseAttributeTypes are the attributes of the object returned by
+// iam_role_parse.
+var iamRoleParseAttributeTypes = map[string]attr.Type{
+	"type":         types.StringType,
+	"name":         types.StringType,
+	"project":      types.StringType,
+	"organization": types.StringType,
+}
+
+var (
+	predefinedRoleRegex   = regexp.MustCompile("^roles/([^/]+)$")
+	projectRoleRegex      = regexp.MustCompile("^projects/([^/]+)/roles/([^/]+)$")
+	organizationRoleRegex = regexp.MustCompile("^organizations/([^/]+)/roles/([^/]+)$")
+)
+
+func NewIamRoleParseFunction() function.Function {
+	return &IamRoleParseFunction{
+		name: "iam_role_parse",
+	}
+}
+
+type IamRoleParseFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f IamRoleParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f IamRoleParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Parses an IAM role into its type, name and the project or organization a custom role is defined in.",
+		Description: "Takes a single string argument, which should be an IAM role such as \"roles/viewer\", \"projects/my-project/roles/myRole\" or \"organizations/123456789/roles/myRole\". Returns an object with the type of the role, which is one of \"predefined\", \"project\" or \"organization\", its name, such as \"viewer\" or \"myRole\", and the project or organization a custom role is defined in.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "role",
+				Description: "An IAM role, as used in IAM policy bindings.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: iamRoleParseAttributeTypes,
+		},
+	}
+}
+
+func (f IamRoleParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var arg0 string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.GetArgument(ctx, 0, &arg0))
+	if resp.Error != nil {
+		return
+	}
+
+	attrs := map[string]attr.Value{
+		"project":      types.StringNull(),
+		"organization": types.StringNull(),
+	}
+	if m := predefinedRoleRegex.FindStringSubmatch(arg0); m != nil {
+		attrs["type"] = types.StringValue("predefined")
+		attrs["name"] = types.StringValue(m[1])
+	} else if m := projectRoleRegex.FindStringSubmatch(arg0); m != nil {
+		attrs["type"] = types.StringValue("project")
+		attrs["project"] = types.StringValue(m[1])
+		attrs["name"] = types.StringValue(m[2])
+	} else if m := organizationRoleRegex.FindStringSubmatch(arg0); m != nil {
+		attrs["type"] = types.StringValue("organization")
+		attrs["organization"] = types.StringValue(m[1])
+		attrs["name"] = types.StringValue(m[2])
+	} else {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" is not a valid IAM role. Expected the format \"roles/{name}\", \"projects/{project}/roles/{name}\" or \"organizations/{organization}/roles/{name}\".", arg0)))
+		return
+	}
+
+	result, diags := types.ObjectValue(iamRoleParseAttributeTypes, attrs)
+	resp.Error = function.ConcatFuncErrors(function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFunctionRun_iam_role_parse(t *testing.T) {
+	t.Parallel()
+
+	parsed := func(roleType, name string, project, organization attr.Value) function.ResultData {
+		return function.NewResultData(types.ObjectValueMust(iamRoleParseAttributeTypes, map[string]attr.Value{
+			"type":         types.StringValue(roleType),
+			"name":         types.StringValue(name),
+			"project":      project,
+			"organization": organization,
+		}))
+	}
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it parses a predefined role": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("roles/viewer")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("predefined", "viewer", types.StringNull(), types.StringNull()),
+			},
+		},
+		"it parses a project custom role": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("projects/my-project/roles/myRole")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("project", "myRole", types.StringValue("my-project"), types.StringNull()),
+			},
+		},
+		"it parses an organization custom role": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("organizations/123/roles/myRole")}),
+			},
+			expected: function.RunResponse{
+				Result: parsed("organization", "myRole", types.StringNull(), types.StringValue("123")),
+			},
+		},
+		"it returns an error when given input is not a role": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("viewer")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(iamRoleParseAttributeTypes)),
+				Error:  function.NewArgumentFuncError(0, "The input string \"viewer\" is not a valid IAM role. Expected the format \"roles/{name}\", \"projects/{project}/roles/{name}\" or \"organizations/{organization}/roles/{name}\"."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(iamRoleParseAttributeTypes)),
+			}
+
+			// Act
+			NewIamRoleParseFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
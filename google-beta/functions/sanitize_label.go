@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
+)
+
+var _ function.Function = SanitizeLabelFunction{}
+
+// maxLabelLength is the maximum number of characters in a label key or value.
+const maxLabelLength = 63
+
+func NewSanitizeLabelFunction() function.Function {
+	return &SanitizeLabelFunction{
+		name: "sanitize_label",
+	}
+}
+
+type SanitizeLabelFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f SanitizeLabelFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f SanitizeLabelFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a string into a valid label key or value.",
+		Description: "Takes a string and whether it is a label key, and converts the string into a valid label key or value. The string is lowercased, each run of characters other than letters, numbers, underscores and hyphens is replaced with a hyphen, leading and trailing hyphens are removed, and the result is truncated to 63 characters, e.g. \"My App (v1.2)\" becomes \"my-app-v1-2\". Label keys must also start with a letter, so leading characters that are not letters are removed from keys. Returns an error if the string cannot be converted, such as a key without any letters.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "label",
+				Description: "The string to convert into a label key or value.",
+			},
+			function.BoolParameter{
+				Name:        "is_key",
+				Description: "Whether to convert the string into a label key rather than a label value.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f SanitizeLabelFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var label string
+	var isKey bool
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &label, &isKey))
+	if resp.Error != nil {
+		return
+	}
+
+	sanitized := sanitizeName(label, func(r rune) bool {
+		return unicode.In(r, unicode.Ll, unicode.Lo, unicode.N) || r == '_' || r == '-'
+	})
+	validate, kind := verify.ValidateLabelValue, "label value"
+	if isKey {
+		sanitized = strings.TrimLeftFunc(sanitized, func(r rune) bool {
+			return !unicode.In(r, unicode.Ll, unicode.Lo)
+		})
+		if sanitized == "" {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" cannot be converted into a label key, as it has no letters to start the key with.", label)))
+			return
+		}
+		validate, kind = verify.ValidateLabelKey, "label key"
+	}
+	sanitized = truncateName(sanitized, maxLabelLength)
+
+	if _, errs := validate(sanitized, f.name); len(errs) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" cannot be converted into a %s: %s", label, kind, errs[0])))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, sanitized))
+}
+
+// sanitizeName lowercases s and replaces each run of characters that are not
+// valid with a hyphen, removing leading and trailing hyphens.
+func sanitizeName(s string, valid func(r rune) bool) string {
+	var b strings.Builder
+	replaced := false
+	for _, r := range strings.ToLower(s) {
+		if valid(r) {
+			b.WriteRune(r)
+			replaced = false
+		} else if !replaced {
+			b.WriteRune('-')
+			replaced = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// truncateName truncates s to max characters, removing trailing hyphens left
+// by the truncation.
+func truncateName(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return strings.TrimRight(string(runes[:max]), "-")
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestFunctionRun_sanitize_label(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it converts a string into a label value": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("My App (v1.2)"), types.BoolValue(false)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("my-app-v1-2")),
+			},
+		},
+		"it keeps a leading number in a label value": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("2024 Release"), types.BoolValue(false)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("2024-release")),
+			},
+		},
+		"it removes a leading number from a label key": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("2024 Release"), types.BoolValue(true)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("release")),
+			},
+		},
+		"it keeps international lowercase letters": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("Équipe"), types.BoolValue(true)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("équipe")),
+			},
+		},
+		"it truncates long labels": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("a" + strings.Repeat("b", 70)), types.BoolValue(false)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("a" + strings.Repeat("b", 62))),
+			},
+		},
+		"it returns an error when a key has no letters": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("123"), types.BoolValue(true)}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(0, "The input string \"123\" cannot be converted into a label key, as it has no letters to start the key with."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(basetypes.StringValue{}),
+			}
+
+			// Act
+			NewSanitizeLabelFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
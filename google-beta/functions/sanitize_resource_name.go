@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
+)
+
+var _ function.Function = SanitizeResourceNameFunction{}
+
+// maxResourceNameLength is the maximum length of an RFC1035 resource name.
+const maxResourceNameLength = 63
+
+func NewSanitizeResourceNameFunction() function.Function {
+	return &SanitizeResourceNameFunction{
+		name: "sanitize_resource_name",
+	}
+}
+
+type SanitizeResourceNameFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f SanitizeResourceNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f SanitizeResourceNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a string into a valid RFC1035 resource name.",
+		Description: "Takes a single string argument, and converts it into a valid RFC1035 resource name, as used by Compute Engine and many other resources. The string is lowercased, each run of characters other than letters, numbers and hyphens is replaced with a hyphen, leading characters that are not letters are removed, and the result is truncated to 63 characters without a trailing hyphen, e.g. \"1st Web_Server\" becomes \"st-web-server\". Returns an error if the string has no letters to start the name with.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "The string to convert into a resource name.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f SanitizeResourceNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var arg0 string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.GetArgument(ctx, 0, &arg0))
+	if resp.Error != nil {
+		return
+	}
+
+	sanitized := sanitizeName(arg0, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-'
+	})
+	sanitized = strings.TrimLeftFunc(sanitized, func(r rune) bool {
+		return r < 'a' || r > 'z'
+	})
+	if sanitized == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" cannot be converted into a resource name, as it has no letters to start the name with.", arg0)))
+		return
+	}
+	sanitized = truncateName(sanitized, maxResourceNameLength)
+
+	if _, errs := verify.ValidateGCEName(sanitized, f.name); len(errs) > 0 {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" cannot be converted into a resource name: %s", arg0, errs[0])))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, sanitized))
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/envvar"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+var _ function.Function = SelfLinkFunction{}
+
+var (
+	zoneRegex   = regexp.MustCompile("^[a-z]+-[a-z]+[0-9]+-[a-z]$")
+	regionRegex = regexp.MustCompile("^[a-z]+-[a-z]+[0-9]+$")
+)
+
+func NewSelfLinkFunction() function.Function {
+	return &SelfLinkFunction{
+		name: "self_link",
+	}
+}
+
+type SelfLinkFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f SelfLinkFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f SelfLinkFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns the self link of a resource built from its service, project, location, collection and name.",
+		Description: "Takes the service, project, location, collection and name of a resource, and returns its self link, using the provider's base path for the service. Compute Engine resources are placed under \"global/\", \"regions/{region}/\" or \"zones/{zone}/\" depending on the location, e.g. self_link(\"compute\", \"my-project\", \"us-central1-a\", \"instances\", \"my-instance\") returns \"https://compute.googleapis.com/compute/beta/projects/my-project/zones/us-central1-a/instances/my-instance\" with the google-beta provider. Resources of other services are placed under \"locations/{location}/\", or directly under the project if the location is empty. The universe domain set in GOOGLE_UNIVERSE_DOMAIN is honored. As providers are not configured before functions are called, custom endpoints and the universe domain set in the provider block are not.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "service",
+				Description: "The service of the resource, matching the name of the provider's custom endpoint field for it without \"_custom_endpoint\", such as \"compute\", \"pubsub\" or \"cloud_run_v2\".",
+			},
+			function.StringParameter{
+				Name:        "project",
+				Description: "The project of the resource.",
+			},
+			function.StringParameter{
+				Name:        "location",
+				Description: "The location of the resource, such as a zone or region. Empty or \"global\" for global resources.",
+			},
+			function.StringParameter{
+				Name:        "collection",
+				Description: "The collection of the resource, such as \"instances\" or \"topics\".",
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "The name of the resource.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f SelfLinkFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var service, project, location, collection, name string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &service, &project, &location, &collection, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	basePath, ok := basePathForService(service)
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The service \"%s\" is not known to the provider.", service)))
+		return
+	}
+	for i, arg := range []string{project, location, collection, name} {
+		if strings.Contains(arg, "/") {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i+1), fmt.Sprintf("The input string \"%s\" cannot contain \"/\".", arg)))
+			return
+		}
+	}
+	for i, arg := range []string{project, location, collection, name} {
+		// Only the location may be empty
+		if arg == "" && i != 1 {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(int64(i+1), "The input string cannot be empty."))
+			return
+		}
+	}
+
+	path := "projects/" + project + "/"
+	if normalizeServiceName(service) == normalizeServiceName(transport_tpg.ComputeBasePathKey) {
+		locationPath, err := computeLocationPath(location)
+		if err != nil {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(2, err.Error()))
+			return
+		}
+		path += locationPath
+	} else if location != "" && location != "global" {
+		path += "locations/" + location + "/"
+	}
+	path += collection + "/" + name
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, basePath+path))
+}
+
+// computeLocationPath returns the part of a Compute Engine self link for a
+// location.
+func computeLocationPath(location string) (string, error) {
+	switch {
+	case location == "" || location == "global":
+		return "global/", nil
+	case zoneRegex.MatchString(location):
+		return "zones/" + location + "/", nil
+	case regionRegex.MatchString(location):
+		return "regions/" + location + "/", nil
+	}
+	return "", fmt.Errorf("The location \"%s\" is not a zone, a region or \"global\".", location)
+}
+
+func normalizeServiceName(service string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(service))
+}
+
+// basePathForService returns the provider's base path for a service, in the
+// universe domain set in the environment.
+func basePathForService(service string) (string, bool) {
+	keys := make([]string, 0, len(transport_tpg.DefaultBasePaths))
+	for key := range transport_tpg.DefaultBasePaths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if normalizeServiceName(key) == normalizeServiceName(service) {
+			basePath := transport_tpg.DefaultBasePaths[key]
+			if universeDomain := transport_tpg.MultiEnvSearch(envvar.UniverseDomainEnvVars); universeDomain != "" && universeDomain != "googleapis.com" {
+				basePath = strings.ReplaceAll(basePath, "googleapis.com", universeDomain)
+			}
+			return basePath, true
+		}
+	}
+	return "", false
+}
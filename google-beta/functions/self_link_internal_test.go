@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestFunctionRun_self_link(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		request  function.RunRequest
+		expected function.RunResponse
+	}{
+		"it returns a global compute self link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("compute"), types.StringValue("my-project"), types.StringValue(""), types.StringValue("networks"), types.StringValue("my-network")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://compute.googleapis.com/compute/beta/projects/my-project/global/networks/my-network")),
+			},
+		},
+		"it returns a zonal compute self link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("Compute"), types.StringValue("my-project"), types.StringValue("us-central1-a"), types.StringValue("instances"), types.StringValue("my-instance")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://compute.googleapis.com/compute/beta/projects/my-project/zones/us-central1-a/instances/my-instance")),
+			},
+		},
+		"it returns a regional compute self link": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("compute"), types.StringValue("my-project"), types.StringValue("us-central1"), types.StringValue("subnetworks"), types.StringValue("my-subnet")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://compute.googleapis.com/compute/beta/projects/my-project/regions/us-central1/subnetworks/my-subnet")),
+			},
+		},
+		"it returns a located self link for other services": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("cloud_run_v2"), types.StringValue("my-project"), types.StringValue("us-central1"), types.StringValue("services"), types.StringValue("my-service")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://run.googleapis.com/v2/projects/my-project/locations/us-central1/services/my-service")),
+			},
+		},
+		"it returns a project level self link for other services": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("pubsub"), types.StringValue("my-project"), types.StringValue(""), types.StringValue("topics"), types.StringValue("my-topic")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringValue("https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic")),
+			},
+		},
+		"it returns an error for an unknown service": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("foobar"), types.StringValue("my-project"), types.StringValue(""), types.StringValue("topics"), types.StringValue("my-topic")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(0, "The service \"foobar\" is not known to the provider."),
+			},
+		},
+		"it returns an error for an invalid compute location": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("compute"), types.StringValue("my-project"), types.StringValue("us"), types.StringValue("instances"), types.StringValue("my-instance")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(2, "The location \"us\" is not a zone, a region or \"global\"."),
+			},
+		},
+		"it returns an error when the name contains a slash": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("compute"), types.StringValue("my-project"), types.StringValue(""), types.StringValue("networks"), types.StringValue("a/b")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(4, "The input string \"a/b\" cannot contain \"/\"."),
+			},
+		},
+		"it returns an error when the project is empty": {
+			request: function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("compute"), types.StringValue(""), types.StringValue(""), types.StringValue("networks"), types.StringValue("my-network")}),
+			},
+			expected: function.RunResponse{
+				Result: function.NewResultData(types.StringNull()),
+				Error:  function.NewArgumentFuncError(1, "The input string cannot be empty."),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		tn, tc := name, testCase
+
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			got := function.RunResponse{
+				Result: function.NewResultData(basetypes.StringValue{}),
+			}
+
+			// Act
+			NewSelfLinkFunction().Run(context.Background(), tc.request, &got)
+
+			// Assert
+			if diff := cmp.Diff(got.Result, tc.expected.Result); diff != "" {
+				t.Errorf("unexpected diff between expected and received result: %s", diff)
+			}
+			if diff := cmp.Diff(got.Error, tc.expected.Error); diff != "" {
+				t.Errorf("unexpected diff between expected and received errors: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+)
+
+var _ function.Function = ServiceAccountEmailFunction{}
+
+func NewServiceAccountEmailFunction() function.Function {
+	return &ServiceAccountEmailFunction{
+		name: "service_account_email",
+	}
+}
+
+type ServiceAccountEmailFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f ServiceAccountEmailFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f ServiceAccountEmailFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns the email of a service account from its project and account id.",
+		Description: "Takes a project id and a service account id, and returns the email of the service account, e.g. returning \"my-sa@my-project.iam.gserviceaccount.com\" for the project \"my-project\" and the account id \"my-sa\". The account id may also be the email or the fully qualified name of a service account, such as \"projects/my-project/serviceAccounts/my-sa@my-project.iam.gserviceaccount.com\", in which case its email is returned and the project is ignored. This matches how the provider resolves service accounts in resources that accept any of these forms.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "project",
+				Description: "The id of the project the service account is in.",
+			},
+			function.StringParameter{
+				Name:        "account_id",
+				Description: "The account id of the service account, such as \"my-sa\", or its email or fully qualified name.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f ServiceAccountEmailFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var project, accountId string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &project, &accountId))
+	if resp.Error != nil {
+		return
+	}
+
+	if accountId == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, "The account id cannot be empty."))
+		return
+	}
+
+	// If the account id is already the fully qualified name
+	if strings.HasPrefix(accountId, "projects/") {
+		_, email, ok := strings.Cut(accountId, "/serviceAccounts/")
+		if !ok || email == "" {
+			resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(1, fmt.Sprintf("The account id \"%s\" is not a valid service account name. Expected the format \"projects/{project}/serviceAccounts/{email}\".", accountId)))
+			return
+		}
+		resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, email))
+		return
+	}
+
+	// If the account id is an email
+	if strings.Contains(accountId, "@") {
+		resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, accountId))
+		return
+	}
+
+	if project == "" {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, "The project cannot be empty when the account id is not an email."))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, tpgresource.ServiceAccountEmail(accountId, project)))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+)
+
+var _ function.Function = ValidateBucketNameFunction{}
+
+func NewValidateBucketNameFunction() function.Function {
+	return &ValidateBucketNameFunction{
+		name: "validate_bucket_name",
+	}
+}
+
+type ValidateBucketNameFunction struct {
+	name string // Makes function name available in Run logic for logging purposes
+}
+
+func (f ValidateBucketNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f ValidateBucketNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns a Cloud Storage bucket name if it is valid, or raises an error explaining why it is not.",
+		Description: "Takes a single string argument, which should be a Cloud Storage bucket name. Returns the name unchanged if it is valid, so that the function can be used inline in a google_storage_bucket resource, and otherwise raises an error with the reason the name is invalid. Bucket names must be 3 to 222 characters of lowercase letters, numbers, underscores, hyphens and dots, start and end with a letter or number, have no dot-separated part longer than 63 characters, and must not start with \"goog\" or contain \"google\". See https://cloud.google.com/storage/docs/buckets#naming.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "The bucket name to validate.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f ValidateBucketNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	// Load arguments from function call
+	var arg0 string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.GetArgument(ctx, 0, &arg0))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := tpgresource.CheckGCSName(arg0); err != nil {
+		resp.Error = function.ConcatFuncErrors(function.NewArgumentFuncError(0, fmt.Sprintf("The input string \"%s\" is not a valid bucket name: %s", arg0, strings.TrimPrefix(err.Error(), "error: "))))
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, arg0))
+}
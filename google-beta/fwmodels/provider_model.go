@@ -11,6 +11,7 @@ import (
 type ProviderModel struct {
 	Credentials                               types.String `tfsdk:"credentials"`
 	AccessToken                               types.String `tfsdk:"access_token"`
+	ExternalCredentials                       types.List   `tfsdk:"external_credentials"`
 	ImpersonateServiceAccount                 types.String `tfsdk:"impersonate_service_account"`
 	ImpersonateServiceAccountDelegates        types.List   `tfsdk:"impersonate_service_account_delegates"`
 	Project                                   types.String `tfsdk:"project"`
@@ -19,9 +20,17 @@ type ProviderModel struct {
 	Zone                                      types.String `tfsdk:"zone"`
 	Scopes                                    types.List   `tfsdk:"scopes"`
 	Batching                                  types.List   `tfsdk:"batching"`
+	RetryPolicy                               types.List   `tfsdk:"retry_policy"`
+	OperationPolling                          types.List   `tfsdk:"operation_polling"`
+	RateLimit                                 types.List   `tfsdk:"rate_limit"`
+	ResponseCache                             types.List   `tfsdk:"response_cache"`
+	UsageMetrics                              types.List   `tfsdk:"usage_metrics"`
+	DryRun                                    types.List   `tfsdk:"dry_run"`
 	UserProjectOverride                       types.Bool   `tfsdk:"user_project_override"`
 	RequestTimeout                            types.String `tfsdk:"request_timeout"`
 	RequestReason                             types.String `tfsdk:"request_reason"`
+	LogFormat                                 types.String `tfsdk:"log_format"`
+	FirewallAnalysis                          types.String `tfsdk:"firewall_analysis"`
 	UniverseDomain                            types.String `tfsdk:"universe_domain"`
 	DefaultLabels                             types.Map    `tfsdk:"default_labels"`
 	AddTerraformAttributionLabel              types.Bool   `tfsdk:"add_terraform_attribution_label"`
@@ -185,17 +194,99 @@ type ProviderModel struct {
 	GkehubFeatureCustomEndpoint types.String `tfsdk:"gkehub_feature_custom_endpoint"`
 }
 
+type ProviderExternalCredentials struct {
+	Audience                       types.String `tfsdk:"audience"`
+	SubjectTokenType               types.String `tfsdk:"subject_token_type"`
+	SubjectTokenFile               types.String `tfsdk:"subject_token_file"`
+	SubjectTokenUrl                types.String `tfsdk:"subject_token_url"`
+	SubjectTokenExecutable         types.String `tfsdk:"subject_token_executable"`
+	SubjectTokenHeaders            types.Map    `tfsdk:"subject_token_headers"`
+	SubjectTokenFieldName          types.String `tfsdk:"subject_token_field_name"`
+	ExecutableTimeout              types.String `tfsdk:"executable_timeout"`
+	ServiceAccountImpersonationUrl types.String `tfsdk:"service_account_impersonation_url"`
+	TokenLifetime                  types.String `tfsdk:"token_lifetime"`
+}
+
 type ProviderBatching struct {
 	SendAfter      types.String `tfsdk:"send_after"`
 	EnableBatching types.Bool   `tfsdk:"enable_batching"`
+	MaxBatchSize   types.Int64  `tfsdk:"max_batch_size"`
+	MaxWait        types.String `tfsdk:"max_wait"`
+	BatchType      types.List   `tfsdk:"batch_type"`
 }
 
 var ProviderBatchingAttributes = map[string]attr.Type{
 	"send_after":      types.StringType,
 	"enable_batching": types.BoolType,
+	"max_batch_size":  types.Int64Type,
+	"max_wait":        types.StringType,
+	"batch_type":      types.ListType{ElemType: types.ObjectType{AttrTypes: ProviderBatchTypeAttributes}},
+}
+
+type ProviderBatchType struct {
+	Name           types.String `tfsdk:"name"`
+	SendAfter      types.String `tfsdk:"send_after"`
+	EnableBatching types.Bool   `tfsdk:"enable_batching"`
+	MaxBatchSize   types.Int64  `tfsdk:"max_batch_size"`
+	MaxWait        types.String `tfsdk:"max_wait"`
+}
+
+var ProviderBatchTypeAttributes = map[string]attr.Type{
+	"name":            types.StringType,
+	"send_after":      types.StringType,
+	"enable_batching": types.BoolType,
+	"max_batch_size":  types.Int64Type,
+	"max_wait":        types.StringType,
+}
+
+type ProviderRetryPolicy struct {
+	InitialBackoff     types.String  `tfsdk:"initial_backoff"`
+	MaxBackoff         types.String  `tfsdk:"max_backoff"`
+	Multiplier         types.Float64 `tfsdk:"multiplier"`
+	Jitter             types.Float64 `tfsdk:"jitter"`
+	MaxAttempts        types.Int64   `tfsdk:"max_attempts"`
+	Timeout            types.String  `tfsdk:"timeout"`
+	StatusCodeOverride types.List    `tfsdk:"status_code_override"`
+}
+
+type ProviderRetryPolicyStatusCodeOverride struct {
+	Code  types.Int64 `tfsdk:"code"`
+	Retry types.Bool  `tfsdk:"retry"`
+}
+
+type ProviderOperationPolling struct {
+	MaxInterval types.String `tfsdk:"max_interval"`
+	Service     types.List   `tfsdk:"service"`
+}
+
+type ProviderOperationPollingService struct {
+	Name            types.String `tfsdk:"name"`
+	InitialInterval types.String `tfsdk:"initial_interval"`
+	MaxInterval     types.String `tfsdk:"max_interval"`
+}
+
+type ProviderRateLimit struct {
+	Service           types.String  `tfsdk:"service"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	Burst             types.Int64   `tfsdk:"burst"`
+}
+
+type ProviderResponseCache struct {
+	MaxEntries       types.Int64 `tfsdk:"max_entries"`
+	ETagRevalidation types.Bool  `tfsdk:"etag_revalidation"`
+}
+
+type ProviderUsageMetrics struct {
+	SummaryFile       types.String `tfsdk:"summary_file"`
+	PrometheusAddress types.String `tfsdk:"prometheus_address"`
+}
+
+type ProviderDryRun struct {
+	RecordFile types.String `tfsdk:"record_file"`
 }
 
 // ProviderMetaModel describes the provider meta model
 type ProviderMetaModel struct {
-	ModuleName types.String `tfsdk:"module_name"`
+	ModuleName                types.String `tfsdk:"module_name"`
+	ImpersonateServiceAccount types.String `tfsdk:"impersonate_service_account"`
 }
@@ -5,6 +5,8 @@ package fwprovider
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
@@ -32,6 +34,14 @@ var (
 	_ provider.ProviderWithFunctions  = &FrameworkProvider{}
 )
 
+// externalCredentialsSubjectTokenSources are the attributes of the
+// external_credentials block exactly one of which must be set.
+var externalCredentialsSubjectTokenSources = path.Expressions{
+	path.MatchRelative().AtParent().AtName("subject_token_file"),
+	path.MatchRelative().AtParent().AtName("subject_token_url"),
+	path.MatchRelative().AtParent().AtName("subject_token_executable"),
+}
+
 // New is a helper function to simplify provider server and testing implementation.
 func New(version string) provider.ProviderWithMetaSchema {
 	return &FrameworkProvider{
@@ -58,6 +68,9 @@ func (p *FrameworkProvider) MetaSchema(_ context.Context, _ provider.MetaSchemaR
 			"module_name": metaschema.StringAttribute{
 				Optional: true,
 			},
+			"impersonate_service_account": metaschema.StringAttribute{
+				Optional: true,
+			},
 		},
 	}
 }
@@ -71,6 +84,7 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				Validators: []validator.String{
 					stringvalidator.ConflictsWith(path.Expressions{
 						path.MatchRoot("access_token"),
+						path.MatchRoot("external_credentials"),
 					}...),
 					CredentialsValidator(),
 					NonEmptyStringValidator(),
@@ -81,6 +95,7 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				Validators: []validator.String{
 					stringvalidator.ConflictsWith(path.Expressions{
 						path.MatchRoot("credentials"),
+						path.MatchRoot("external_credentials"),
 					}...),
 					NonEmptyStringValidator(),
 				},
@@ -132,6 +147,18 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 			"request_reason": schema.StringAttribute{
 				Optional: true,
 			},
+			"log_format": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(transport_tpg.LogFormats...),
+				},
+			},
+			"firewall_analysis": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(transport_tpg.FirewallAnalysisModes...),
+				},
+			},
 			"universe_domain": schema.StringAttribute{
 				Optional: true,
 			},
@@ -992,6 +1019,86 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"external_credentials": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+					listvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("credentials"),
+						path.MatchRoot("access_token"),
+					}...),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"audience": schema.StringAttribute{
+							Required: true,
+							Validators: []validator.String{
+								StringFuncValidator("value must be the full resource name of a workload identity pool provider or a workforce pool provider", transport_tpg.ValidateExternalCredentialsAudience),
+							},
+						},
+						"subject_token_type": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonEmptyStringValidator(),
+							},
+						},
+						"subject_token_file": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonEmptyStringValidator(),
+								stringvalidator.ExactlyOneOf(externalCredentialsSubjectTokenSources...),
+							},
+						},
+						"subject_token_url": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonEmptyStringValidator(),
+								stringvalidator.ExactlyOneOf(externalCredentialsSubjectTokenSources...),
+							},
+						},
+						"subject_token_executable": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonEmptyStringValidator(),
+								stringvalidator.ExactlyOneOf(externalCredentialsSubjectTokenSources...),
+							},
+						},
+						"subject_token_headers": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Validators: []validator.Map{
+								mapvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("subject_token_url")),
+							},
+						},
+						"subject_token_field_name": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonEmptyStringValidator(),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("subject_token_executable")),
+							},
+						},
+						"executable_timeout": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								StringFuncValidator("value must be a duration between 5s and 2m", transport_tpg.ValidateExternalCredentialsExecutableTimeout),
+								stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("subject_token_executable")),
+							},
+						},
+						"service_account_impersonation_url": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								StringFuncValidator("value must be the generateAccessToken URL of a service account", transport_tpg.ValidateExternalCredentialsServiceAccountImpersonationUrl),
+							},
+						},
+						"token_lifetime": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								StringFuncValidator("value must be a duration between 10m and 12h", transport_tpg.ValidateExternalCredentialsTokenLifetime),
+								stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("service_account_impersonation_url")),
+							},
+						},
+					},
+				},
+			},
 			"batching": schema.ListNestedBlock{
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
@@ -1004,6 +1111,192 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 						"enable_batching": schema.BoolAttribute{
 							Optional: true,
 						},
+						"max_batch_size": schema.Int64Attribute{
+							Optional: true,
+						},
+						"max_wait": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonNegativeDurationValidator(),
+							},
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"batch_type": schema.ListNestedBlock{
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Required: true,
+									},
+									"send_after": schema.StringAttribute{
+										Optional: true,
+										Validators: []validator.String{
+											NonNegativeDurationValidator(),
+										},
+									},
+									"enable_batching": schema.BoolAttribute{
+										Optional: true,
+									},
+									"max_batch_size": schema.Int64Attribute{
+										Optional: true,
+									},
+									"max_wait": schema.StringAttribute{
+										Optional: true,
+										Validators: []validator.String{
+											NonNegativeDurationValidator(),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"rate_limit": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"service": schema.StringAttribute{
+							Required: true,
+						},
+						"requests_per_second": schema.Float64Attribute{
+							Required: true,
+						},
+						"burst": schema.Int64Attribute{
+							Optional: true,
+						},
+					},
+				},
+			},
+			"response_cache": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"max_entries": schema.Int64Attribute{
+							Optional: true,
+						},
+						"etag_revalidation": schema.BoolAttribute{
+							Optional: true,
+						},
+					},
+				},
+			},
+			"usage_metrics": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"summary_file": schema.StringAttribute{
+							Optional: true,
+						},
+						"prometheus_address": schema.StringAttribute{
+							Optional: true,
+						},
+					},
+				},
+			},
+			"dry_run": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"record_file": schema.StringAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+			"retry_policy": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"initial_backoff": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonNegativeDurationValidator(),
+							},
+						},
+						"max_backoff": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonNegativeDurationValidator(),
+							},
+						},
+						"multiplier": schema.Float64Attribute{
+							Optional: true,
+						},
+						"jitter": schema.Float64Attribute{
+							Optional: true,
+						},
+						"max_attempts": schema.Int64Attribute{
+							Optional: true,
+						},
+						"timeout": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonNegativeDurationValidator(),
+							},
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"status_code_override": schema.ListNestedBlock{
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"code": schema.Int64Attribute{
+										Required: true,
+									},
+									"retry": schema.BoolAttribute{
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"operation_polling": schema.ListNestedBlock{
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"max_interval": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								NonNegativeDurationValidator(),
+							},
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"service": schema.ListNestedBlock{
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Required: true,
+										Validators: []validator.String{
+											stringvalidator.OneOf(transport_tpg.OperationPollingServices()...),
+										},
+									},
+									"initial_interval": schema.StringAttribute{
+										Optional: true,
+										Validators: []validator.String{
+											NonNegativeDurationValidator(),
+										},
+									},
+									"max_interval": schema.StringAttribute{
+										Optional: true,
+										Validators: []validator.String{
+											NonNegativeDurationValidator(),
+										},
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -1057,11 +1350,23 @@ func (p *FrameworkProvider) Resources(_ context.Context) []func() resource.Resou
 // Functions defines the provider functions implemented in the provider.
 func (p *FrameworkProvider) Functions(_ context.Context) []func() function.Function {
 	return []func() function.Function{
+		functions.NewCidrOverlapsFunction,
+		functions.NewCidrSubnetsNonOverlappingFunction,
+		functions.NewConvertSelfLinkFunction,
+		functions.NewGkeMaxPodsToPodCidrSizeFunction,
+		functions.NewIamMemberNormalizeFunction,
+		functions.NewIamMemberParseFunction,
+		functions.NewIamRoleParseFunction,
 		functions.NewLocationFromIdFunction,
 		functions.NewNameFromIdFunction,
 		functions.NewProjectFromIdFunction,
 		functions.NewRegionFromIdFunction,
 		functions.NewRegionFromZoneFunction,
+		functions.NewSanitizeLabelFunction,
+		functions.NewSanitizeResourceNameFunction,
+		functions.NewSelfLinkFunction,
+		functions.NewServiceAccountEmailFunction,
+		functions.NewValidateBucketNameFunction,
 		functions.NewZoneFromIdFunction,
 	}
 }
@@ -117,3 +117,39 @@ func (v nonEmptyStringValidator) ValidateString(ctx context.Context, request val
 func NonEmptyStringValidator() validator.String {
 	return nonEmptyStringValidator{}
 }
+
+// String Func Validator
+type stringFuncValidator struct {
+	description string
+	validate    func(string) error
+}
+
+// Description describes the validation in plain text formatting.
+func (v stringFuncValidator) Description(_ context.Context) string {
+	return v.description
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v stringFuncValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v stringFuncValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := v.validate(request.ConfigValue.ValueString()); err != nil {
+		response.Diagnostics.AddAttributeError(request.Path, fmt.Sprintf("invalid value for %s", request.Path), err.Error())
+	}
+}
+
+// StringFuncValidator validates a string Attribute with a validation function
+// shared with the SDK provider.
+func StringFuncValidator(description string, validate func(string) error) validator.String {
+	return stringFuncValidator{
+		description: description,
+		validate:    validate,
+	}
+}
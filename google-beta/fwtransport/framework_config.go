@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"reflect"
 	"regexp"
 	"strconv"
 	"time"
@@ -23,7 +24,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 
 	"github.com/hashicorp/terraform-provider-google-beta/google-beta/fwmodels"
 	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
@@ -38,12 +38,17 @@ type FrameworkProviderConfig struct {
 	Client                     *http.Client
 	Context                    context.Context
 	gRPCLoggingOptions         []option.ClientOption
+	LogFormat                  string
 	PollInterval               time.Duration
+	OperationPolling           map[string]transport_tpg.OperationPollingOverride
 	Project                    types.String
 	Region                     types.String
 	Zone                       types.String
+	RequestBatchers            *transport_tpg.BatcherRegistry
+	UsageMetrics               *transport_tpg.UsageMetrics
 	RequestBatcherIam          *transport_tpg.RequestBatcher
 	RequestBatcherServiceUsage *transport_tpg.RequestBatcher
+	RetryPolicy                *transport_tpg.RetryPolicy
 	Scopes                     types.List
 	TokenSource                oauth2.TokenSource
 	UniverseDomain             types.String
@@ -354,16 +359,21 @@ func (p *FrameworkProviderConfig) LoadAndValidateFramework(ctx context.Context,
 	p.Scopes = data.Scopes
 	p.Zone = data.Zone
 	p.UserProjectOverride = data.UserProjectOverride
-	p.PollInterval = 10 * time.Second
+	p.PollInterval = transport_tpg.DefaultOperationPollMaxInterval
+	if operationPolling := GetOperationPollingConfig(ctx, data.OperationPolling, diags); operationPolling != nil {
+		p.PollInterval = operationPolling.MaxInterval
+		p.OperationPolling = operationPolling.Services
+	}
 	p.Project = data.Project
 	p.UniverseDomain = data.UniverseDomain
-	p.RequestBatcherServiceUsage = transport_tpg.NewRequestBatcher("Service Usage", ctx, batchingConfig)
-	p.RequestBatcherIam = transport_tpg.NewRequestBatcher("IAM", ctx, batchingConfig)
+	p.RequestBatchers = transport_tpg.NewBatcherRegistry(ctx, batchingConfig)
+	p.RequestBatcherServiceUsage = p.RequestBatchers.Get(transport_tpg.BatchTypeServiceUsage)
+	p.RequestBatcherIam = p.RequestBatchers.Get(transport_tpg.BatchTypeIam)
 }
 
 // HandleDefaults will handle all the defaults necessary in the provider
 func (p *FrameworkProviderConfig) HandleDefaults(ctx context.Context, data *fwmodels.ProviderModel, diags *diag.Diagnostics) {
-	if (data.AccessToken.IsNull() || data.AccessToken.IsUnknown()) && (data.Credentials.IsNull() || data.Credentials.IsUnknown()) {
+	if (data.AccessToken.IsNull() || data.AccessToken.IsUnknown()) && (data.Credentials.IsNull() || data.Credentials.IsUnknown()) && (data.ExternalCredentials.IsNull() || len(data.ExternalCredentials.Elements()) == 0) {
 		credentials := transport_tpg.MultiEnvDefault([]string{
 			"GOOGLE_CREDENTIALS",
 			"GOOGLE_CLOUD_KEYFILE_JSON",
@@ -468,6 +478,10 @@ func (p *FrameworkProviderConfig) HandleDefaults(ctx context.Context, data *fwmo
 		data.RequestReason = types.StringValue(os.Getenv("CLOUDSDK_CORE_REQUEST_REASON"))
 	}
 
+	if (data.LogFormat.IsNull() || data.LogFormat.IsUnknown()) && os.Getenv("GOOGLE_LOG_FORMAT") != "" {
+		data.LogFormat = types.StringValue(os.Getenv("GOOGLE_LOG_FORMAT"))
+	}
+
 	if data.RequestTimeout.IsNull() || data.RequestTimeout.IsUnknown() {
 		data.RequestTimeout = types.StringValue("120s")
 	}
@@ -1697,6 +1711,31 @@ func (p *FrameworkProviderConfig) SetupClient(ctx context.Context, data fwmodels
 		return
 	}
 
+	retryPolicy := GetRetryPolicy(ctx, data.RetryPolicy, diags)
+	if diags.HasError() {
+		return
+	}
+
+	rateLimits := GetRateLimits(ctx, data, diags)
+	if diags.HasError() {
+		return
+	}
+
+	responseCache := GetResponseCacheConfig(ctx, data.ResponseCache, diags)
+	if diags.HasError() {
+		return
+	}
+
+	usageMetricsConfig := GetUsageMetricsConfig(ctx, data.UsageMetrics, diags)
+	if diags.HasError() {
+		return
+	}
+
+	dryRun := GetDryRunConfig(ctx, data.DryRun, diags)
+	if diags.HasError() {
+		return
+	}
+
 	cleanCtx := context.WithValue(ctx, oauth2.HTTPClient, cleanhttp.DefaultClient())
 
 	// 1. MTLS TRANSPORT/CLIENT - sets up proper auth headers
@@ -1712,18 +1751,40 @@ func (p *FrameworkProviderConfig) SetupClient(ctx context.Context, data fwmodels
 		return
 	}
 
-	// 2. Logging Transport - ensure we log HTTP requests to GCP APIs.
-	loggingTransport := logging.NewTransport("Google", client.Transport)
+	p.UsageMetrics, err = transport_tpg.NewUsageMetrics(usageMetricsConfig)
+	if err != nil {
+		diags.AddError("error setting up usage metrics", err.Error())
+		return
+	}
+
+	// 2. Dry Run Transport - records mutating requests instead of sending them if enabled
+	// Keep order for wrapping the client so that every other transport sees the synthetic responses.
+	dryRunTransport := transport_tpg.NewTransportWithDryRun(client.Transport, dryRun)
 
-	// 3. Retry Transport - retries common temporary errors
+	// 3. Logging Transport - ensure we log HTTP requests to GCP APIs.
+	loggingTransport := transport_tpg.NewLoggingTransport(dryRunTransport, data.LogFormat.ValueString())
+
+	// 4. Rate Limit Transport - throttles requests to services with a configured rate limit
+	// Keep order for wrapping retries so each retried request is throttled as well.
+	rateLimitTransport := transport_tpg.NewTransportWithRateLimits(loggingTransport, rateLimits)
+
+	// 5. Retry Transport - retries common temporary errors
 	// Keep order for wrapping logging so we log each retried request as well.
 	// This value should be used if needed to create shallow copies with additional retry predicates.
 	// See ClientWithAdditionalRetries
-	retryTransport := transport_tpg.NewTransportWithDefaultRetries(loggingTransport)
+	retryTransport := transport_tpg.NewTransportWithRetryPolicy(rateLimitTransport, retryPolicy)
+
+	// 6. Usage Metrics Transport - counts requests to each API if enabled
+	// Keep order for wrapping retries so retries are counted against the request they retry.
+	usageMetricsTransport := transport_tpg.NewTransportWithUsageMetrics(retryTransport, p.UsageMetrics)
 
-	// 4. Header Transport - outer wrapper to inject additional headers we want to apply
+	// 7. Response Cache Transport - serves repeated GET requests from memory if enabled
+	// Keep order for wrapping retries so a cache hit skips retrying and throttling entirely.
+	responseCacheTransport := transport_tpg.NewTransportWithResponseCache(usageMetricsTransport, responseCache)
+
+	// 8. Header Transport - outer wrapper to inject additional headers we want to apply
 	// before making requests
-	headerTransport := transport_tpg.NewTransportWithHeaders(retryTransport)
+	headerTransport := transport_tpg.NewTransportWithHeaders(responseCacheTransport)
 	if !data.RequestReason.IsNull() {
 		headerTransport.Set("X-Goog-Request-Reason", data.RequestReason.ValueString())
 	}
@@ -1745,6 +1806,8 @@ func (p *FrameworkProviderConfig) SetupClient(ctx context.Context, data fwmodels
 	client.Timeout = timeout
 
 	p.TokenSource = tokenSource
+	p.RetryPolicy = retryPolicy
+	p.LogFormat = data.LogFormat.ValueString()
 	p.Client = client
 }
 
@@ -1752,10 +1815,7 @@ func (p *FrameworkProviderConfig) SetupGrpcLogging() {
 	logger := logrus.StandardLogger()
 
 	logrus.SetLevel(logrus.DebugLevel)
-	logrus.SetFormatter(&transport_tpg.Formatter{
-		TimestampFormat: "2006/01/02 15:04:05",
-		LogFormat:       "%time% [%lvl%] %msg% \n",
-	})
+	logrus.SetFormatter(transport_tpg.NewLogFormatter(p.LogFormat))
 
 	alwaysLoggingDeciderClient := func(ctx context.Context, fullMethodName string) bool { return true }
 	grpc_logrus.ReplaceGrpcLogger(logrus.NewEntry(logger))
@@ -1820,10 +1880,65 @@ func (p *FrameworkProviderConfig) logGoogleIdentities(ctx context.Context, data
 
 // GetTokenSource gets token source based on the Google Credentials configured.
 // If initialCredentialsOnly is true, don't follow the impersonation settings and return the initial set of creds.
+// Token sources are shared with the SDK provider through the token source
+// cache of the transport package.
 func GetTokenSource(ctx context.Context, data fwmodels.ProviderModel, initialCredentialsOnly bool, diags *diag.Diagnostics) oauth2.TokenSource {
+	key, ok := getTokenSourceCacheKey(ctx, data, initialCredentialsOnly)
+	if ok {
+		if tokenSource := transport_tpg.CachedTokenSource(key); tokenSource != nil {
+			return tokenSource
+		}
+	}
+
 	creds := GetCredentials(ctx, data, initialCredentialsOnly, diags)
+	if diags.HasError() || !ok {
+		return creds.TokenSource
+	}
+
+	// Token sources are rebuilt after the provider is configured, so they
+	// can't be cancelled with its context
+	rebuildCtx := context.WithoutCancel(ctx)
+	rebuild := func() (oauth2.TokenSource, error) {
+		var d diag.Diagnostics
+		creds := GetCredentials(rebuildCtx, data, initialCredentialsOnly, &d)
+		if d.HasError() {
+			return nil, fmt.Errorf("%s: %s", d.Errors()[0].Summary(), d.Errors()[0].Detail())
+		}
+		return creds.TokenSource, nil
+	}
+	return transport_tpg.CacheTokenSource(key, creds.TokenSource, rebuild)
+}
+
+// getTokenSourceCacheKey returns the token source cache key for the
+// credentials configured, or false if they can't be read. GetCredentials
+// reports why.
+func getTokenSourceCacheKey(ctx context.Context, data fwmodels.ProviderModel, initialCredentialsOnly bool) (transport_tpg.TokenSourceCacheKey, bool) {
+	// Empty credentials are an error here, rather than a fallback to the
+	// application default credentials they would share a key with
+	for _, v := range []types.String{data.Credentials, data.AccessToken} {
+		if !v.IsNull() && v.ValueString() == "" {
+			return transport_tpg.TokenSourceCacheKey{}, false
+		}
+	}
+
+	var d diag.Diagnostics
+	var scopes, delegates []string
+	if !data.Scopes.IsNull() && !data.Scopes.IsUnknown() {
+		d.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+	}
+	if !data.ImpersonateServiceAccountDelegates.IsNull() && !data.ImpersonateServiceAccountDelegates.IsUnknown() {
+		d.Append(data.ImpersonateServiceAccountDelegates.ElementsAs(ctx, &delegates, false)...)
+	}
+	externalCredentials := GetExternalCredentialsConfig(ctx, data.ExternalCredentials, &d)
+	if d.HasError() {
+		return transport_tpg.TokenSourceCacheKey{}, false
+	}
 
-	return creds.TokenSource
+	key, err := transport_tpg.NewTokenSourceCacheKey(data.AccessToken.ValueString(), data.Credentials.ValueString(), externalCredentials, data.ImpersonateServiceAccount.ValueString(), delegates, scopes, data.UniverseDomain.ValueString(), initialCredentialsOnly)
+	if err != nil {
+		return transport_tpg.TokenSourceCacheKey{}, false
+	}
+	return key, true
 }
 
 // GetCredentials gets credentials with a given scope (clientScopes).
@@ -1874,6 +1989,31 @@ func GetCredentials(ctx context.Context, data fwmodels.ProviderModel, initialCre
 		}
 	}
 
+	if externalCredentials := GetExternalCredentialsConfig(ctx, data.ExternalCredentials, diags); externalCredentials != nil {
+		contents, err := externalCredentials.CredentialsJSON()
+		if err != nil {
+			diags.AddError("error building external_credentials", err.Error())
+			return googleoauth.Credentials{}
+		}
+
+		opts := []option.ClientOption{option.WithCredentialsJSON(contents), option.WithScopes(clientScopes...)}
+		if !data.ImpersonateServiceAccount.IsNull() && !initialCredentialsOnly {
+			opts = append(opts, option.ImpersonateCredentials(data.ImpersonateServiceAccount.ValueString(), delegates...))
+		}
+		creds, err := transport.Creds(context.TODO(), opts...)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("unable to load credentials from the external_credentials block for audience %q", externalCredentials.Audience), err.Error())
+			return googleoauth.Credentials{}
+		}
+
+		tflog.Info(ctx, "Authenticating using configured 'external_credentials'...")
+		tflog.Info(ctx, fmt.Sprintf("  -- Scopes: %s", clientScopes))
+		return *creds
+	}
+	if diags.HasError() {
+		return googleoauth.Credentials{}
+	}
+
 	if !data.Credentials.IsNull() && !data.Credentials.IsUnknown() {
 		contents, _, err := verify.PathOrContents(data.Credentials.ValueString())
 		if err != nil {
@@ -1945,19 +2085,344 @@ func GetBatchingConfig(ctx context.Context, data types.List, diags *diag.Diagnos
 		return bc
 	}
 
-	sendAfter, err := time.ParseDuration(pbConfigs[0].SendAfter.ValueString())
-	if err != nil {
+	// Unlike in the SDK provider, an empty send_after is an error.
+	if _, err := time.ParseDuration(pbConfigs[0].SendAfter.ValueString()); err != nil {
 		diags.AddError("error parsing send after time duration", err.Error())
 		return bc
 	}
 
-	bc.SendAfter = sendAfter
+	cfgV := batchingSettingsToMap(pbConfigs[0].SendAfter, pbConfigs[0].EnableBatching, pbConfigs[0].MaxBatchSize, pbConfigs[0].MaxWait)
+	if !pbConfigs[0].BatchType.IsNull() && !pbConfigs[0].BatchType.IsUnknown() {
+		var btConfigs []fwmodels.ProviderBatchType
+		d := pbConfigs[0].BatchType.ElementsAs(ctx, &btConfigs, true)
+		diags.Append(d...)
+		if diags.HasError() {
+			return bc
+		}
+
+		batchTypes := make([]interface{}, 0, len(btConfigs))
+		for _, btConfig := range btConfigs {
+			btV := batchingSettingsToMap(btConfig.SendAfter, btConfig.EnableBatching, btConfig.MaxBatchSize, btConfig.MaxWait)
+			btV["name"] = btConfig.Name.ValueString()
+			batchTypes = append(batchTypes, btV)
+		}
+		cfgV["batch_type"] = batchTypes
+	}
+
+	config, err := transport_tpg.ExpandProviderBatchingConfig([]interface{}{cfgV})
+	if err != nil {
+		diags.AddError("invalid batching", err.Error())
+		return bc
+	}
+
+	return config
+}
+
+// batchingSettingsToMap returns the set settings of a `batching` or
+// `batch_type` block in the form expected by ExpandProviderBatchingConfig.
+func batchingSettingsToMap(sendAfter types.String, enableBatching types.Bool, maxBatchSize types.Int64, maxWait types.String) map[string]interface{} {
+	cfgV := map[string]interface{}{}
+	if !sendAfter.IsNull() {
+		cfgV["send_after"] = sendAfter.ValueString()
+	}
+	if !enableBatching.IsNull() {
+		cfgV["enable_batching"] = enableBatching.ValueBool()
+	}
+	if !maxBatchSize.IsNull() {
+		cfgV["max_batch_size"] = int(maxBatchSize.ValueInt64())
+	}
+	if !maxWait.IsNull() {
+		cfgV["max_wait"] = maxWait.ValueString()
+	}
+	return cfgV
+}
+
+// GetResponseCacheConfig returns the response cache configuration given the
+// provider configuration, or nil if responses should not be cached.
+func GetResponseCacheConfig(ctx context.Context, data types.List, diags *diag.Diagnostics) *transport_tpg.ResponseCacheConfig {
+	// Handle if entire response_cache block is null/unknown
+	if data.IsNull() || data.IsUnknown() || len(data.Elements()) == 0 {
+		return nil
+	}
 
-	if !pbConfigs[0].EnableBatching.IsNull() {
-		bc.EnableBatching = pbConfigs[0].EnableBatching.ValueBool()
+	var rcConfigs []fwmodels.ProviderResponseCache
+	d := data.ElementsAs(ctx, &rcConfigs, true)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
 	}
 
-	return bc
+	cfg := map[string]interface{}{}
+	if !rcConfigs[0].MaxEntries.IsNull() && !rcConfigs[0].MaxEntries.IsUnknown() {
+		cfg["max_entries"] = int(rcConfigs[0].MaxEntries.ValueInt64())
+	}
+	if !rcConfigs[0].ETagRevalidation.IsNull() && !rcConfigs[0].ETagRevalidation.IsUnknown() {
+		cfg["etag_revalidation"] = rcConfigs[0].ETagRevalidation.ValueBool()
+	}
+
+	rc, err := transport_tpg.ExpandProviderResponseCacheConfig([]interface{}{cfg})
+	if err != nil {
+		diags.AddError("error expanding response_cache block", err.Error())
+		return nil
+	}
+	return rc
+}
+
+// GetUsageMetricsConfig returns the usage metrics configuration given the
+// provider configuration, or nil if API usage should not be recorded.
+func GetUsageMetricsConfig(ctx context.Context, data types.List, diags *diag.Diagnostics) *transport_tpg.UsageMetricsConfig {
+	// Handle if entire usage_metrics block is null/unknown
+	if data.IsNull() || data.IsUnknown() || len(data.Elements()) == 0 {
+		return nil
+	}
+
+	var umConfigs []fwmodels.ProviderUsageMetrics
+	d := data.ElementsAs(ctx, &umConfigs, true)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
+	}
+
+	um, err := transport_tpg.ExpandProviderUsageMetricsConfig([]interface{}{map[string]interface{}{
+		"summary_file":       umConfigs[0].SummaryFile.ValueString(),
+		"prometheus_address": umConfigs[0].PrometheusAddress.ValueString(),
+	}})
+	if err != nil {
+		diags.AddError("error expanding usage_metrics block", err.Error())
+		return nil
+	}
+	return um
+}
+
+// GetExternalCredentialsConfig returns the external credentials configuration
+// given the provider configuration, or nil if the block is not set.
+func GetExternalCredentialsConfig(ctx context.Context, data types.List, diags *diag.Diagnostics) *transport_tpg.ExternalCredentialsConfig {
+	// Handle if entire external_credentials block is null/unknown
+	if data.IsNull() || data.IsUnknown() || len(data.Elements()) == 0 {
+		return nil
+	}
+
+	var ecConfigs []fwmodels.ProviderExternalCredentials
+	d := data.ElementsAs(ctx, &ecConfigs, true)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
+	}
+
+	headers := map[string]interface{}{}
+	for k, v := range ecConfigs[0].SubjectTokenHeaders.Elements() {
+		headers[k] = v.(types.String).ValueString()
+	}
+	ec, err := transport_tpg.ExpandProviderExternalCredentialsConfig([]interface{}{map[string]interface{}{
+		"audience":                          ecConfigs[0].Audience.ValueString(),
+		"subject_token_type":                ecConfigs[0].SubjectTokenType.ValueString(),
+		"subject_token_file":                ecConfigs[0].SubjectTokenFile.ValueString(),
+		"subject_token_url":                 ecConfigs[0].SubjectTokenUrl.ValueString(),
+		"subject_token_executable":          ecConfigs[0].SubjectTokenExecutable.ValueString(),
+		"subject_token_headers":             headers,
+		"subject_token_field_name":          ecConfigs[0].SubjectTokenFieldName.ValueString(),
+		"executable_timeout":                ecConfigs[0].ExecutableTimeout.ValueString(),
+		"service_account_impersonation_url": ecConfigs[0].ServiceAccountImpersonationUrl.ValueString(),
+		"token_lifetime":                    ecConfigs[0].TokenLifetime.ValueString(),
+	}})
+	if err != nil {
+		diags.AddError("error expanding external_credentials block", err.Error())
+		return nil
+	}
+	return ec
+}
+
+// GetDryRunConfig returns the dry run configuration given the provider
+// configuration, or nil if requests should be sent as normal.
+func GetDryRunConfig(ctx context.Context, data types.List, diags *diag.Diagnostics) *transport_tpg.DryRunConfig {
+	// Handle if entire dry_run block is null/unknown
+	if data.IsNull() || data.IsUnknown() || len(data.Elements()) == 0 {
+		return nil
+	}
+
+	var drConfigs []fwmodels.ProviderDryRun
+	d := data.ElementsAs(ctx, &drConfigs, true)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
+	}
+
+	dr, err := transport_tpg.ExpandProviderDryRunConfig([]interface{}{map[string]interface{}{
+		"record_file": drConfigs[0].RecordFile.ValueString(),
+	}})
+	if err != nil {
+		diags.AddError("error expanding dry_run block", err.Error())
+		return nil
+	}
+	return dr
+}
+
+// GetRetryPolicy returns the retry policy given the provider configuration
+// set for retry_policy, or nil if the block is not set.
+func GetRetryPolicy(ctx context.Context, data types.List, diags *diag.Diagnostics) *transport_tpg.RetryPolicy {
+	// Handle if entire retry_policy block is null/unknown
+	if data.IsNull() || data.IsUnknown() || len(data.Elements()) == 0 {
+		return nil
+	}
+
+	var rpConfigs []fwmodels.ProviderRetryPolicy
+	d := data.ElementsAs(ctx, &rpConfigs, true)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
+	}
+	rpConfig := rpConfigs[0]
+
+	rp := transport_tpg.DefaultRetryPolicy()
+	durations := map[string]struct {
+		value types.String
+		dst   *time.Duration
+	}{
+		"initial_backoff": {rpConfig.InitialBackoff, &rp.InitialBackoff},
+		"max_backoff":     {rpConfig.MaxBackoff, &rp.MaxBackoff},
+		"timeout":         {rpConfig.Timeout, &rp.Timeout},
+	}
+	for k, v := range durations {
+		if v.value.IsNull() || v.value.IsUnknown() || v.value.ValueString() == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(v.value.ValueString())
+		if err != nil {
+			diags.AddError(fmt.Sprintf("error parsing retry_policy %s time duration", k), err.Error())
+			return nil
+		}
+		*v.dst = dur
+	}
+
+	if !rpConfig.Multiplier.IsNull() {
+		rp.Multiplier = rpConfig.Multiplier.ValueFloat64()
+	}
+	if !rpConfig.Jitter.IsNull() {
+		rp.Jitter = rpConfig.Jitter.ValueFloat64()
+	}
+	if !rpConfig.MaxAttempts.IsNull() {
+		rp.MaxAttempts = int(rpConfig.MaxAttempts.ValueInt64())
+	}
+
+	if !rpConfig.StatusCodeOverride.IsNull() && !rpConfig.StatusCodeOverride.IsUnknown() {
+		var overrides []fwmodels.ProviderRetryPolicyStatusCodeOverride
+		d := rpConfig.StatusCodeOverride.ElementsAs(ctx, &overrides, true)
+		diags.Append(d...)
+		if diags.HasError() {
+			return nil
+		}
+		for _, o := range overrides {
+			if rp.StatusCodeOverrides == nil {
+				rp.StatusCodeOverrides = make(map[int]bool)
+			}
+			rp.StatusCodeOverrides[int(o.Code.ValueInt64())] = o.Retry.ValueBool()
+		}
+	}
+
+	if err := rp.Validate(); err != nil {
+		diags.AddError("invalid retry_policy", err.Error())
+		return nil
+	}
+
+	return rp
+}
+
+// GetOperationPollingConfig returns the operation polling configuration
+// given the provider configuration set for operation_polling, or nil if the
+// block is not set.
+func GetOperationPollingConfig(ctx context.Context, data types.List, diags *diag.Diagnostics) *transport_tpg.OperationPollingConfig {
+	// Handle if entire operation_polling block is null/unknown
+	if data.IsNull() || data.IsUnknown() || len(data.Elements()) == 0 {
+		return nil
+	}
+
+	var opConfigs []fwmodels.ProviderOperationPolling
+	d := data.ElementsAs(ctx, &opConfigs, true)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
+	}
+
+	services := []interface{}{}
+	if !opConfigs[0].Service.IsNull() && !opConfigs[0].Service.IsUnknown() {
+		var serviceConfigs []fwmodels.ProviderOperationPollingService
+		d := opConfigs[0].Service.ElementsAs(ctx, &serviceConfigs, true)
+		diags.Append(d...)
+		if diags.HasError() {
+			return nil
+		}
+		for _, s := range serviceConfigs {
+			services = append(services, map[string]interface{}{
+				"name":             s.Name.ValueString(),
+				"initial_interval": s.InitialInterval.ValueString(),
+				"max_interval":     s.MaxInterval.ValueString(),
+			})
+		}
+	}
+
+	cfg, err := transport_tpg.ExpandProviderOperationPolling([]interface{}{map[string]interface{}{
+		"max_interval": opConfigs[0].MaxInterval.ValueString(),
+		"service":      services,
+	}})
+	if err != nil {
+		diags.AddError("error expanding operation_polling block", err.Error())
+		return nil
+	}
+	return cfg
+}
+
+// GetRateLimits returns the rate limits given the provider configuration set
+// for rate_limit, resolving each service's base path from its custom endpoint.
+func GetRateLimits(ctx context.Context, data fwmodels.ProviderModel, diags *diag.Diagnostics) []transport_tpg.RateLimit {
+	if data.RateLimit.IsNull() || data.RateLimit.IsUnknown() {
+		return nil
+	}
+
+	var rlConfigs []fwmodels.ProviderRateLimit
+	d := data.RateLimit.ElementsAs(ctx, &rlConfigs, true)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil
+	}
+
+	raw := make([]interface{}, 0, len(rlConfigs))
+	for _, rlConfig := range rlConfigs {
+		cfgV := map[string]interface{}{
+			"service":             rlConfig.Service.ValueString(),
+			"requests_per_second": rlConfig.RequestsPerSecond.ValueFloat64(),
+		}
+		if !rlConfig.Burst.IsNull() {
+			cfgV["burst"] = int(rlConfig.Burst.ValueInt64())
+		}
+		raw = append(raw, cfgV)
+	}
+
+	limits, err := transport_tpg.ExpandProviderRateLimits(raw, func(service string) (string, bool) {
+		return customEndpointFromModel(data, service)
+	})
+	if err != nil {
+		diags.AddError("invalid rate_limit", err.Error())
+		return nil
+	}
+	return limits
+}
+
+// customEndpointFromModel returns the value of the `<service>_custom_endpoint`
+// field of the provider configuration, and whether that field exists.
+func customEndpointFromModel(data fwmodels.ProviderModel, service string) (string, bool) {
+	tag := service + "_custom_endpoint"
+	v := reflect.ValueOf(data)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("tfsdk") != tag {
+			continue
+		}
+		endpoint, ok := v.Field(i).Interface().(types.String)
+		if !ok {
+			return "", false
+		}
+		return endpoint.ValueString(), true
+	}
+	return "", false
 }
 
 func GetRegionFromRegionSelfLink(selfLink basetypes.StringValue) basetypes.StringValue {
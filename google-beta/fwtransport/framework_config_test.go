@@ -1638,13 +1638,13 @@ func TestFrameworkProvider_LoadAndValidateFramework_batching(t *testing.T) {
 			// See https://github.com/GoogleCloudPlatform/magic-modules/pull/7668
 			if !tc.SetBatchingAsNull && !tc.SetBatchingAsUnknown {
 				b, _ := types.ObjectValue(
-					map[string]attr.Type{
-						"enable_batching": types.BoolType,
-						"send_after":      types.StringType,
-					},
+					fwmodels.ProviderBatchingAttributes,
 					map[string]attr.Value{
 						"enable_batching": tc.EnableBatchingValue,
 						"send_after":      tc.SendAfterValue,
+						"max_batch_size":  types.Int64Null(),
+						"max_wait":        types.StringNull(),
+						"batch_type":      types.ListNull(types.ObjectType{}.WithAttributeTypes(fwmodels.ProviderBatchTypeAttributes)),
 					},
 				)
 				batching, _ := types.ListValue(types.ObjectType{}.WithAttributeTypes(fwmodels.ProviderBatchingAttributes), []attr.Value{b})
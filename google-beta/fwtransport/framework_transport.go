@@ -76,6 +76,8 @@ func SendFrameworkRequestWithTimeout(p *FrameworkProviderConfig, method, project
 		},
 		Timeout:              timeout,
 		ErrorRetryPredicates: errorRetryPredicates,
+		Policy:               p.RetryPolicy,
+		Metrics:              p.UsageMetrics,
 	})
 	if err != nil {
 		diags.AddError("error sending request", err.Error())
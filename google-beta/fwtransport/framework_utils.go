@@ -62,6 +62,17 @@ func GenerateFrameworkUserAgentString(metaData *fwmodels.ProviderMetaModel, curr
 	return currUserAgent
 }
 
+// CheckProviderMeta adds an error to diags if a module's provider_meta sets
+// impersonate_service_account, which data sources implemented with the plugin
+// framework do not support.
+func CheckProviderMeta(metaData *fwmodels.ProviderMetaModel, diags *diag.Diagnostics) {
+	if metaData == nil || metaData.ImpersonateServiceAccount.IsNull() || metaData.ImpersonateServiceAccount.ValueString() == "" {
+		return
+	}
+	diags.AddError("impersonate_service_account is not supported in provider_meta for this data source",
+		fmt.Sprintf("Data sources implemented with the plugin framework cannot impersonate the service account %s set in the module's provider_meta. Set impersonate_service_account in the provider block instead.", metaData.ImpersonateServiceAccount.ValueString()))
+}
+
 func HandleDatasourceNotFoundError(ctx context.Context, err error, state *tfsdk.State, resource string, diags *diag.Diagnostics) {
 	if transport_tpg.IsGoogleApiErrorWithCode(err, 404) {
 		tflog.Warn(ctx, fmt.Sprintf("Removing %s because it's gone", resource))
@@ -5,6 +5,11 @@ package fwtransport
 import (
 	"context"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/fwmodels"
 )
 
 func TestCompileUserAgentString(t *testing.T) {
@@ -55,3 +60,35 @@ func TestCompileUserAgentString(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckProviderMeta(t *testing.T) {
+	cases := map[string]struct {
+		MetaData    *fwmodels.ProviderMetaModel
+		ExpectError bool
+	}{
+		"no provider_meta": {},
+		"module name only": {
+			MetaData: &fwmodels.ProviderMetaModel{
+				ModuleName:                types.StringValue("my-module"),
+				ImpersonateServiceAccount: types.StringNull(),
+			},
+		},
+		"impersonated service account": {
+			MetaData: &fwmodels.ProviderMetaModel{
+				ModuleName:                types.StringNull(),
+				ImpersonateServiceAccount: types.StringValue("sa@my-project.iam.gserviceaccount.com"),
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var diags diag.Diagnostics
+			CheckProviderMeta(tc.MetaData, &diags)
+			if diags.HasError() != tc.ExpectError {
+				t.Fatalf("expected error %t, got %v", tc.ExpectError, diags)
+			}
+		})
+	}
+}
@@ -17,6 +17,14 @@ import (
 	"github.com/hashicorp/terraform-provider-google-beta/version"
 )
 
+// externalCredentialsSubjectTokenSources are the fields of the
+// external_credentials block exactly one of which must be set.
+var externalCredentialsSubjectTokenSources = []string{
+	"external_credentials.0.subject_token_file",
+	"external_credentials.0.subject_token_url",
+	"external_credentials.0.subject_token_executable",
+}
+
 // Provider returns a *schema.Provider.
 func Provider() *schema.Provider {
 
@@ -37,14 +45,82 @@ func Provider() *schema.Provider {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ValidateFunc:  ValidateCredentials,
-				ConflictsWith: []string{"access_token"},
+				ConflictsWith: []string{"access_token", "external_credentials"},
 			},
 
 			"access_token": {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ValidateFunc:  ValidateEmptyStrings,
-				ConflictsWith: []string{"credentials"},
+				ConflictsWith: []string{"credentials", "external_credentials"},
+			},
+
+			"external_credentials": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"credentials", "access_token"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"audience": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateStringWith(transport_tpg.ValidateExternalCredentialsAudience),
+						},
+						"subject_token_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: ValidateEmptyStrings,
+						},
+						"subject_token_file": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: ValidateEmptyStrings,
+							ExactlyOneOf: externalCredentialsSubjectTokenSources,
+						},
+						"subject_token_url": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: ValidateEmptyStrings,
+							ExactlyOneOf: externalCredentialsSubjectTokenSources,
+						},
+						"subject_token_executable": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: ValidateEmptyStrings,
+							ExactlyOneOf: externalCredentialsSubjectTokenSources,
+						},
+						"subject_token_headers": {
+							Type:         schema.TypeMap,
+							Optional:     true,
+							Elem:         &schema.Schema{Type: schema.TypeString},
+							RequiredWith: []string{"external_credentials.0.subject_token_url"},
+						},
+						"subject_token_field_name": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  ValidateEmptyStrings,
+							ConflictsWith: []string{"external_credentials.0.subject_token_executable"},
+						},
+						"executable_timeout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateStringWith(transport_tpg.ValidateExternalCredentialsExecutableTimeout),
+							RequiredWith: []string{"external_credentials.0.subject_token_executable"},
+						},
+						"service_account_impersonation_url": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateStringWith(transport_tpg.ValidateExternalCredentialsServiceAccountImpersonationUrl),
+						},
+						"token_lifetime": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateStringWith(transport_tpg.ValidateExternalCredentialsTokenLifetime),
+							RequiredWith: []string{"external_credentials.0.service_account_impersonation_url"},
+						},
+					},
+				},
 			},
 
 			"impersonate_service_account": {
@@ -109,6 +185,208 @@ func Provider() *schema.Provider {
 							Type:     schema.TypeBool,
 							Optional: true,
 						},
+						"max_batch_size": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"max_wait": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: verify.ValidateNonNegativeDuration(),
+						},
+						"batch_type": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"send_after": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: verify.ValidateNonNegativeDuration(),
+									},
+									"enable_batching": {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  true,
+									},
+									"max_batch_size": {
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+									"max_wait": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: verify.ValidateNonNegativeDuration(),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"rate_limit": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"requests_per_second": {
+							Type:     schema.TypeFloat,
+							Required: true,
+						},
+						"burst": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"response_cache": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_entries": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"etag_revalidation": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"usage_metrics": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"summary_file": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"prometheus_address": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"dry_run": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"record_file": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+
+			"retry_policy": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"initial_backoff": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: verify.ValidateNonNegativeDuration(),
+						},
+						"max_backoff": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: verify.ValidateNonNegativeDuration(),
+						},
+						"multiplier": {
+							Type:     schema.TypeFloat,
+							Optional: true,
+						},
+						"jitter": {
+							Type:     schema.TypeFloat,
+							Optional: true,
+						},
+						"max_attempts": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"timeout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: verify.ValidateNonNegativeDuration(),
+						},
+						"status_code_override": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"code": {
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+									"retry": {
+										Type:     schema.TypeBool,
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"operation_polling": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_interval": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: verify.ValidateNonNegativeDuration(),
+						},
+						"service": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: verify.ValidateEnum(transport_tpg.OperationPollingServices()),
+									},
+									"initial_interval": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: verify.ValidateNonNegativeDuration(),
+									},
+									"max_interval": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: verify.ValidateNonNegativeDuration(),
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -128,6 +406,18 @@ func Provider() *schema.Provider {
 				Optional: true,
 			},
 
+			"log_format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: verify.ValidateEnum(transport_tpg.LogFormats),
+			},
+
+			"firewall_analysis": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: verify.ValidateEnum(transport_tpg.FirewallAnalysisModes),
+			},
+
 			"default_labels": {
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -808,10 +1098,14 @@ func Provider() *schema.Provider {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"impersonate_service_account": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 		},
 
 		DataSourcesMap: DatasourceMap(),
-		ResourcesMap:   ResourceMap(),
+		ResourcesMap:   withProviderMetaImpersonation(withResourceContext(withPendingOperations(withResourceOverrides(ResourceMap())))),
 	}
 
 	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -883,6 +1177,14 @@ func ProviderConfigure(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 		config.RequestReason = v.(string)
 	}
 
+	if v, ok := d.GetOk("log_format"); ok {
+		config.LogFormat = v.(string)
+	}
+
+	if v, ok := d.GetOk("firewall_analysis"); ok {
+		config.FirewallAnalysis = v.(string)
+	}
+
 	// Check for primary credentials in config. Note that if neither is set, ADCs
 	// will be used if available.
 	if v, ok := d.GetOk("access_token"); ok {
@@ -893,9 +1195,15 @@ func ProviderConfigure(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 		config.Credentials = v.(string)
 	}
 
-	// only check environment variables if neither value was set in config- this
+	externalCredentials, err := transport_tpg.ExpandProviderExternalCredentialsConfig(d.Get("external_credentials"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.ExternalCredentials = externalCredentials
+
+	// only check environment variables if no credentials were set in config- this
 	// means config beats env var in all cases.
-	if config.AccessToken == "" && config.Credentials == "" {
+	if config.AccessToken == "" && config.Credentials == "" && config.ExternalCredentials == nil {
 		config.Credentials = transport_tpg.MultiEnvSearch([]string{
 			"GOOGLE_CREDENTIALS",
 			"GOOGLE_CLOUD_KEYFILE_JSON",
@@ -999,6 +1307,51 @@ func ProviderConfigure(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 	}
 	config.BatchingConfig = batchCfg
 
+	retryPolicy, err := transport_tpg.ExpandProviderRetryPolicy(d.Get("retry_policy"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.RetryPolicy = retryPolicy
+
+	operationPolling, err := transport_tpg.ExpandProviderOperationPolling(d.Get("operation_polling"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if operationPolling != nil {
+		config.PollInterval = operationPolling.MaxInterval
+		config.OperationPolling = operationPolling.Services
+	}
+
+	rateLimits, err := transport_tpg.ExpandProviderRateLimits(d.Get("rate_limit"), func(service string) (string, bool) {
+		endpointKey := service + "_custom_endpoint"
+		if _, ok := p.Schema[endpointKey]; !ok {
+			return "", false
+		}
+		return d.Get(endpointKey).(string), true
+	})
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.RateLimits = rateLimits
+
+	responseCache, err := transport_tpg.ExpandProviderResponseCacheConfig(d.Get("response_cache"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.ResponseCache = responseCache
+
+	usageMetrics, err := transport_tpg.ExpandProviderUsageMetricsConfig(d.Get("usage_metrics"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.UsageMetricsConfig = usageMetrics
+
+	dryRun, err := transport_tpg.ExpandProviderDryRunConfig(d.Get("dry_run"))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.DryRun = dryRun
+
 	// Generated products
 	config.AccessApprovalBasePath = d.Get("access_approval_custom_endpoint").(string)
 	config.AccessContextManagerBasePath = d.Get("access_context_manager_custom_endpoint").(string)
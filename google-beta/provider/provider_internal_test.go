@@ -1765,3 +1765,98 @@ func TestProvider_ProviderConfigure_batching(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_ProviderConfigure_externalCredentials(t *testing.T) {
+	const audience = "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/my-pool/providers/my-provider"
+
+	cases := map[string]struct {
+		ConfigValues           map[string]interface{}
+		EnvVariables           map[string]string
+		ExpectedConfigAudience string
+		ExpectError            bool
+	}{
+		"external_credentials can be configured with a subject token file": {
+			ConfigValues: map[string]interface{}{
+				"external_credentials": []interface{}{
+					map[string]interface{}{
+						"audience":           audience,
+						"subject_token_file": "/var/run/secrets/token",
+					},
+				},
+			},
+			ExpectedConfigAudience: audience,
+		},
+		"when external_credentials is set, credentials environment variables are not used": {
+			ConfigValues: map[string]interface{}{
+				"external_credentials": []interface{}{
+					map[string]interface{}{
+						"audience":           audience,
+						"subject_token_file": "/var/run/secrets/token",
+					},
+				},
+			},
+			EnvVariables: map[string]string{
+				"GOOGLE_CREDENTIALS":        transport_tpg.TestFakeCredentialsPath,
+				"GOOGLE_OAUTH_ACCESS_TOKEN": "value-from-GOOGLE_OAUTH_ACCESS_TOKEN",
+			},
+			ExpectedConfigAudience: audience,
+		},
+		// Error states
+		"when external_credentials has no subject token source, there's an error": {
+			ConfigValues: map[string]interface{}{
+				"external_credentials": []interface{}{
+					map[string]interface{}{
+						"audience": audience,
+					},
+				},
+			},
+			ExpectError: true,
+		},
+		"when external_credentials uses an executable that isn't allowed, there's an error": {
+			ConfigValues: map[string]interface{}{
+				"external_credentials": []interface{}{
+					map[string]interface{}{
+						"audience":                 audience,
+						"subject_token_executable": "/usr/bin/get-token",
+					},
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+
+			// Arrange
+			ctx := context.Background()
+			acctest.UnsetTestProviderConfigEnvs(t)
+			acctest.SetupTestEnvs(t, tc.EnvVariables)
+			p := provider.Provider()
+			d := tpgresource.SetupTestResourceDataFromConfigMap(t, p.Schema, tc.ConfigValues)
+
+			// Act
+			c, diags := provider.ProviderConfigure(ctx, d, p)
+
+			// Assert
+			if diags.HasError() && !tc.ExpectError {
+				t.Fatalf("unexpected error(s): %#v", diags)
+			}
+			if !diags.HasError() && tc.ExpectError {
+				t.Fatal("expected error(s) but got none")
+			}
+			if tc.ExpectError {
+				return
+			}
+
+			config := c.(*transport_tpg.Config) // Should be non-nil value, as test cases reaching this point experienced no errors
+
+			if config.ExternalCredentials == nil || config.ExternalCredentials.Audience != tc.ExpectedConfigAudience {
+				t.Fatalf("expected external_credentials audience set in Config struct to be %s, got %v", tc.ExpectedConfigAudience, config.ExternalCredentials)
+			}
+			if config.Credentials != "" || config.AccessToken != "" {
+				t.Fatalf("expected credentials and access_token to be unset in Config struct, got %q and %q", config.Credentials, config.AccessToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+// withProviderMetaImpersonation returns copies of resources whose CRUD
+// functions are called with a config impersonating the service account set
+// in the `impersonate_service_account` field of the module's provider_meta.
+//
+// Terraform only sends provider_meta to resources, so data sources are not
+// wrapped. Importers and CustomizeDiff functions use the provider's identity.
+func withProviderMetaImpersonation(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	wrapped := make(map[string]*schema.Resource, len(resources))
+	for name, r := range resources {
+		resource := *r
+
+		resource.Create = impersonatedFunc(resource.Create)
+		resource.Read = impersonatedFunc(resource.Read)
+		resource.Update = impersonatedFunc(resource.Update)
+		resource.Delete = impersonatedFunc(resource.Delete)
+
+		resource.CreateContext = impersonatedContextFunc(resource.CreateContext)
+		resource.ReadContext = impersonatedContextFunc(resource.ReadContext)
+		resource.UpdateContext = impersonatedContextFunc(resource.UpdateContext)
+		resource.DeleteContext = impersonatedContextFunc(resource.DeleteContext)
+
+		resource.CreateWithoutTimeout = impersonatedContextFunc(resource.CreateWithoutTimeout)
+		resource.ReadWithoutTimeout = impersonatedContextFunc(resource.ReadWithoutTimeout)
+		resource.UpdateWithoutTimeout = impersonatedContextFunc(resource.UpdateWithoutTimeout)
+		resource.DeleteWithoutTimeout = impersonatedContextFunc(resource.DeleteWithoutTimeout)
+
+		wrapped[name] = &resource
+	}
+	return wrapped
+}
+
+func impersonatedFunc(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		config, err := providerMetaImpersonatedConfig(d, meta)
+		if err != nil {
+			return err
+		}
+		return f(d, config)
+	}
+}
+
+func impersonatedContextFunc(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		config, err := providerMetaImpersonatedConfig(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		return f(ctx, d, config)
+	}
+}
+
+// providerMetaImpersonatedConfig returns the config to call a resource's CRUD
+// function with: meta itself, unless the module's provider_meta sets
+// `impersonate_service_account`.
+func providerMetaImpersonatedConfig(d *schema.ResourceData, meta interface{}) (interface{}, error) {
+	config, ok := meta.(*transport_tpg.Config)
+	if !ok {
+		return meta, nil
+	}
+
+	var m transport_tpg.ProviderMeta
+	if err := d.GetProviderMeta(&m); err != nil {
+		return nil, err
+	}
+	if m.ImpersonateServiceAccount == "" {
+		return meta, nil
+	}
+	return config.ForImpersonatedServiceAccount(m.ImpersonateServiceAccount)
+}
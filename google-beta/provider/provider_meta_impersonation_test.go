@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+func TestWithProviderMetaImpersonation(t *testing.T) {
+	var got interface{}
+	read := func(_ context.Context, _ *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		got = meta
+		return nil
+	}
+	original := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+		ReadContext: read,
+	}
+
+	wrapped := withProviderMetaImpersonation(map[string]*schema.Resource{"google_test": original})["google_test"]
+	if wrapped == original {
+		t.Fatalf("expected the resource to be copied")
+	}
+	if original.ReadContext == nil || original.CreateContext != nil {
+		t.Errorf("expected the original resource to be unchanged")
+	}
+	if wrapped.CreateContext != nil || wrapped.Read != nil {
+		t.Errorf("expected unset functions to stay unset")
+	}
+
+	// Without provider_meta, resources are called with the provider's config
+	config := &transport_tpg.Config{}
+	d := schema.TestResourceDataRaw(t, wrapped.Schema, map[string]interface{}{})
+	if diags := wrapped.ReadContext(context.Background(), d, config); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got != config {
+		t.Errorf("expected the provider's config, got %v", got)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+// pendingOperationField holds the URL of an operation that was still running
+// when creating a resource failed, such as when waiting on it timed out.
+const pendingOperationField = "pending_operation"
+
+// pendingOperationResourcePrefixes are the prefixes of the names of the
+// resources whose operation waiters record the operations they wait on.
+var pendingOperationResourcePrefixes = []string{
+	"google_compute_",
+	"google_container_",
+	"google_service_networking_",
+	"google_sql_",
+}
+
+func hasPendingOperations(name string) bool {
+	for _, prefix := range pendingOperationResourcePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// withPendingOperations returns copies of resources that keep track of the
+// operation creating them if it is still running when their creation fails.
+// Creating the resource then succeeds with a warning, so Terraform keeps it
+// in state without tainting it, and the operation is recorded in its state.
+//
+// Reading the resource checks on the operation once, and leaves the resource
+// as it is while the operation runs, so refreshing doesn't block. Once the
+// operation finished the resource is read, and is created again on the next
+// apply only if the operation didn't create it. Updating or deleting the
+// resource waits on the operation first. Terraform only saves state once a
+// resource is created, so operations are not recorded if the provider is
+// killed while waiting on them.
+func withPendingOperations(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	wrapped := make(map[string]*schema.Resource, len(resources))
+	for name, r := range resources {
+		if !hasPendingOperations(name) || r.Schema == nil || r.Schema[pendingOperationField] != nil {
+			wrapped[name] = r
+			continue
+		}
+		resource := *r
+
+		resource.Schema = make(map[string]*schema.Schema, len(r.Schema)+1)
+		for k, v := range r.Schema {
+			resource.Schema[k] = v
+		}
+		resource.Schema[pendingOperationField] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: `The URL of the operation creating the resource, if it was still running when creating the resource failed. The resource is read once the operation finishes, and the operation is waited on before the resource is updated or deleted.`,
+		}
+
+		// Warnings can only be returned by the context functions.
+		if resource.Create != nil {
+			resource.CreateContext = contextFunc(resource.Create)
+			resource.Create = nil
+		}
+		if resource.Read != nil {
+			resource.ReadContext = contextFunc(resource.Read)
+			resource.Read = nil
+		}
+		resource.CreateContext = pendingOperationCreateFunc(resource.CreateContext)
+		resource.CreateWithoutTimeout = pendingOperationCreateFunc(resource.CreateWithoutTimeout)
+		resource.ReadContext = pendingOperationReadFunc(resource.ReadContext)
+		resource.ReadWithoutTimeout = pendingOperationReadFunc(resource.ReadWithoutTimeout)
+
+		resource.Update = pendingOperationResumeFunc(resource.Update)
+		resource.Delete = pendingOperationResumeFunc(resource.Delete)
+		resource.UpdateContext = pendingOperationResumeContextFunc(resource.UpdateContext)
+		resource.DeleteContext = pendingOperationResumeContextFunc(resource.DeleteContext)
+		resource.UpdateWithoutTimeout = pendingOperationResumeContextFunc(resource.UpdateWithoutTimeout)
+		resource.DeleteWithoutTimeout = pendingOperationResumeContextFunc(resource.DeleteWithoutTimeout)
+
+		wrapped[name] = &resource
+	}
+	return wrapped
+}
+
+func contextFunc(f func(*schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		return diag.FromErr(f(d, meta))
+	}
+}
+
+func pendingOperationCreateFunc(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		meta = trackPendingOperations(d, meta)
+		diags := f(ctx, d, meta)
+		if !diags.HasError() || !recordPendingOperation(d, meta) {
+			return diags
+		}
+
+		var warnings diag.Diagnostics
+		var errs []string
+		for _, diagnostic := range diags {
+			if diagnostic.Severity == diag.Error {
+				errs = append(errs, diagnostic.Summary)
+				continue
+			}
+			warnings = append(warnings, diagnostic)
+		}
+		operationUrl := d.Get(pendingOperationField).(string)
+		return append(warnings, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Operation creating %s is still running", d.Id()),
+			Detail: fmt.Sprintf("Creating the resource failed while operation %s was still running: %s\n\n"+
+				"The resource is kept in state, and is read once the operation finishes. If the operation didn't create it, it is created again on the next apply.",
+				operationUrl, strings.Join(errs, "\n")),
+		})
+	}
+}
+
+func pendingOperationReadFunc(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		running, err := pendingOperationRunning(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if running {
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Operation creating %s is still running", d.Id()),
+				Detail:   fmt.Sprintf("The resource is read once operation %s finishes.", d.Get(pendingOperationField)),
+			}}
+		}
+		return f(ctx, d, meta)
+	}
+}
+
+func pendingOperationResumeFunc(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		if err := resumePendingOperation(d, meta); err != nil {
+			return err
+		}
+		return f(d, meta)
+	}
+}
+
+func pendingOperationResumeContextFunc(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if err := resumePendingOperation(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+		return f(ctx, d, meta)
+	}
+}
+
+// trackPendingOperations returns a copy of the provider's config recording
+// the operations started while creating the resource.
+func trackPendingOperations(d *schema.ResourceData, meta interface{}) interface{} {
+	config, ok := meta.(*transport_tpg.Config)
+	if !ok {
+		return meta
+	}
+	tracked := *config
+	tracked.PendingOperations = transport_tpg.NewPendingOperationTracker(d)
+	return &tracked
+}
+
+// recordPendingOperation keeps the resource in state with the operation that
+// was creating it, if that operation is still running, and reports whether
+// it did.
+func recordPendingOperation(d *schema.ResourceData, meta interface{}) bool {
+	config, ok := meta.(*transport_tpg.Config)
+	if !ok {
+		return false
+	}
+	operationUrl, id := config.PendingOperations.Pending()
+	if operationUrl == "" || (id == "" && d.Id() == "") {
+		return false
+	}
+
+	userAgent, err := tpgresource.GenerateUserAgentString(d, config.UserAgent)
+	if err != nil {
+		return false
+	}
+	w := &tpgresource.PendingOperationWaiter{
+		Config:    config,
+		UserAgent: userAgent,
+		Url:       operationUrl,
+	}
+	// If the operation's status can't be read, assume it's still running, as
+	// the resource may exist once it finishes.
+	if op, err := w.QueryOp(); err == nil {
+		if err := w.SetOp(op); err == nil && tpgresource.OperationDone(w) {
+			return false
+		}
+	}
+
+	if d.Id() == "" {
+		d.SetId(id)
+	}
+	if err := d.Set(pendingOperationField, operationUrl); err != nil {
+		log.Printf("[WARN] Error recording pending operation %s: %s", operationUrl, err)
+		return false
+	}
+	log.Printf("[WARN] Operation %s creating %s is still running, and will be checked on before the resource is next read", operationUrl, d.Id())
+	return true
+}
+
+// pendingOperationRunning checks once on the operation recorded when creating
+// the resource failed, if any, and reports whether it is still running. The
+// operation is cleared from state once it finished.
+func pendingOperationRunning(d *schema.ResourceData, meta interface{}) (bool, error) {
+	operationUrl, _ := d.Get(pendingOperationField).(string)
+	if operationUrl == "" {
+		return false, nil
+	}
+	config, ok := meta.(*transport_tpg.Config)
+	if !ok {
+		return false, nil
+	}
+	userAgent, err := tpgresource.GenerateUserAgentString(d, config.UserAgent)
+	if err != nil {
+		return false, err
+	}
+
+	w := &tpgresource.PendingOperationWaiter{
+		Config:    config,
+		UserAgent: userAgent,
+		Url:       operationUrl,
+	}
+	op, err := w.QueryOp()
+	if err != nil {
+		return false, fmt.Errorf("Error checking on pending operation %s creating %s: %s", operationUrl, d.Id(), err)
+	}
+	if err := w.SetOp(op); err != nil {
+		return false, err
+	}
+	if !tpgresource.OperationDone(w) {
+		return true, nil
+	}
+	if err := w.Error(); err != nil {
+		// Reading the resource tells whether it exists.
+		log.Printf("[WARN] Pending operation %s failed: %s", operationUrl, err)
+	}
+	return false, d.Set(pendingOperationField, "")
+}
+
+// resumePendingOperation waits on the operation recorded when creating the
+// resource failed, if any.
+func resumePendingOperation(d *schema.ResourceData, meta interface{}) error {
+	operationUrl, _ := d.Get(pendingOperationField).(string)
+	if operationUrl == "" {
+		return nil
+	}
+	config, ok := meta.(*transport_tpg.Config)
+	if !ok {
+		return nil
+	}
+	userAgent, err := tpgresource.GenerateUserAgentString(d, config.UserAgent)
+	if err != nil {
+		return err
+	}
+
+	if err := tpgresource.WaitForPendingOperation(config, operationUrl, userAgent, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return fmt.Errorf("Error waiting on pending operation %s creating %s: %s", operationUrl, d.Id(), err)
+	}
+	return d.Set(pendingOperationField, "")
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+func TestWithPendingOperations(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects/p/topics":
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "projects/p/operations/op"})
+		case "/v1/projects/p/operations/op":
+			// The operation finishes on the second read of the resource
+			done := atomic.AddInt32(&polls, 1) > 3
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "projects/p/operations/op", "done": done})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var reads int
+	original := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			config := meta.(*transport_tpg.Config)
+			if _, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+				Config: config,
+				Method: "POST",
+				RawURL: server.URL + "/v1/projects/p/topics",
+			}); err != nil {
+				return err
+			}
+			d.SetId("projects/p/topics/t")
+			// Polling the operation times out
+			if _, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+				Config: config,
+				Method: "GET",
+				RawURL: server.URL + "/v1/projects/p/operations/op",
+			}); err != nil {
+				return err
+			}
+			d.SetId("")
+			return fmt.Errorf("Error waiting to create Topic: timeout")
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			reads++
+			return nil
+		},
+	}
+
+	resources := withPendingOperations(map[string]*schema.Resource{
+		"google_compute_test": original,
+		"google_pubsub_test":  original,
+	})
+	if resources["google_pubsub_test"] != original {
+		t.Errorf("expected resources whose waiters don't record operations to be left unchanged")
+	}
+	wrapped := resources["google_compute_test"]
+	if original.Schema[pendingOperationField] != nil || wrapped.Schema[pendingOperationField] == nil {
+		t.Fatalf("expected the field to be added to a copy of the resource")
+	}
+
+	config := &transport_tpg.Config{
+		Client:       server.Client(),
+		PollInterval: time.Millisecond,
+	}
+	ctx := context.Background()
+	d := schema.TestResourceDataRaw(t, wrapped.Schema, map[string]interface{}{})
+	diags := wrapped.CreateContext(ctx, d, config)
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected creating the resource to succeed with a warning, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "Error waiting to create Topic: timeout") {
+		t.Errorf("expected the warning to include the error, got %q", diags[0].Detail)
+	}
+	if config.PendingOperations != nil {
+		t.Errorf("expected the provider's config to be left unchanged")
+	}
+	if d.Id() != "projects/p/topics/t" {
+		t.Errorf("expected the resource to be kept in state, got ID %q", d.Id())
+	}
+	if got := d.Get(pendingOperationField).(string); got != server.URL+"/v1/projects/p/operations/op" {
+		t.Fatalf("expected the pending operation to be recorded, got %q", got)
+	}
+
+	// The operation is still running, so the resource is left as it is.
+	diags = wrapped.ReadContext(ctx, d, config)
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning || reads != 0 {
+		t.Fatalf("expected reading to return a warning without reading the resource, got %v after %d reads", diags, reads)
+	}
+
+	if diags := wrapped.ReadContext(ctx, d, config); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if reads != 1 || atomic.LoadInt32(&polls) != 4 {
+		t.Errorf("expected the resource to be read once the operation finished, got %d reads after %d polls", reads, polls)
+	}
+	if got := d.Get(pendingOperationField).(string); got != "" {
+		t.Errorf("expected the pending operation to be cleared, got %q", got)
+	}
+}
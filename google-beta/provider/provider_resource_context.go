@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+// withResourceContext returns copies of resources whose CRUD functions are
+// called with a config making requests with a context that records the
+// resource's type and ID, so that they are included in structured logs. Each
+// call is traced by a span, which the spans of its requests, operation waits,
+// mutex waits and batches are children of.
+func withResourceContext(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	wrapped := make(map[string]*schema.Resource, len(resources))
+	for name, r := range resources {
+		resource := *r
+
+		resource.Create = resourceContextFunc(name, "Create", resource.Create)
+		resource.Read = resourceContextFunc(name, "Read", resource.Read)
+		resource.Update = resourceContextFunc(name, "Update", resource.Update)
+		resource.Delete = resourceContextFunc(name, "Delete", resource.Delete)
+
+		resource.CreateContext = resourceContextContextFunc(name, "Create", resource.CreateContext)
+		resource.ReadContext = resourceContextContextFunc(name, "Read", resource.ReadContext)
+		resource.UpdateContext = resourceContextContextFunc(name, "Update", resource.UpdateContext)
+		resource.DeleteContext = resourceContextContextFunc(name, "Delete", resource.DeleteContext)
+
+		resource.CreateWithoutTimeout = resourceContextContextFunc(name, "Create", resource.CreateWithoutTimeout)
+		resource.ReadWithoutTimeout = resourceContextContextFunc(name, "Read", resource.ReadWithoutTimeout)
+		resource.UpdateWithoutTimeout = resourceContextContextFunc(name, "Update", resource.UpdateWithoutTimeout)
+		resource.DeleteWithoutTimeout = resourceContextContextFunc(name, "Delete", resource.DeleteWithoutTimeout)
+
+		wrapped[name] = &resource
+	}
+	return wrapped
+}
+
+func resourceContextFunc(name, op string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		ctx, span := startResourceSpan(context.Background(), name, op, d)
+		err := f(d, resourceContextConfig(ctx, name, d, meta))
+		endResourceSpan(span, d, err)
+		return err
+	}
+}
+
+func resourceContextContextFunc(name, op string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		ctx, span := startResourceSpan(ctx, name, op, d)
+		diags := f(ctx, d, resourceContextConfig(ctx, name, d, meta))
+		endResourceSpan(span, d, diagsError(diags))
+		return diags
+	}
+}
+
+func startResourceSpan(ctx context.Context, name, op string, d *schema.ResourceData) (context.Context, trace.Span) {
+	return transport_tpg.StartSpan(ctx, name+" "+op,
+		attribute.String("terraform.resource.type", name),
+		attribute.String("terraform.resource.id", d.Id()),
+	)
+}
+
+// endResourceSpan ends span, recording the resource's ID, which is only known
+// once it is created.
+func endResourceSpan(span trace.Span, d *schema.ResourceData, err error) {
+	span.SetAttributes(attribute.String("terraform.resource.id", d.Id()))
+	transport_tpg.EndSpan(span, err)
+}
+
+// diagsError returns an error for the first error in diags, or nil if there
+// is none.
+func diagsError(diags diag.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			return errors.New(d.Summary)
+		}
+	}
+	return nil
+}
+
+// resourceContextConfig returns the config to call a resource's CRUD function
+// with: a copy of meta making requests with the values of ctx, recording the
+// resource. Requests are not cancelled with ctx, as the resource's timeouts
+// are enforced by its operation waiters and retries.
+func resourceContextConfig(ctx context.Context, name string, d *schema.ResourceData, meta interface{}) interface{} {
+	config, ok := meta.(*transport_tpg.Config)
+	if !ok {
+		return meta
+	}
+	return config.ForRequestContext(transport_tpg.WithResource(context.WithoutCancel(ctx), name, d.Id()))
+}
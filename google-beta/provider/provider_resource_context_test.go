@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+type resourceRecordingTransport struct {
+	resourceTypes, ids []string
+}
+
+func (t *resourceRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resourceType, id := transport_tpg.ResourceFromContext(req.Context())
+	t.resourceTypes = append(t.resourceTypes, resourceType)
+	t.ids = append(t.ids, id)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithResourceContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	read := func(d *schema.ResourceData, meta interface{}) error {
+		config := meta.(*transport_tpg.Config)
+		// Requests made by API clients built from the config
+		resp, err := config.Client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		_, err = transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+			Config: config,
+			Method: "GET",
+			RawURL: server.URL,
+		})
+		return err
+	}
+	resources := withResourceContext(map[string]*schema.Resource{
+		"google_test": {
+			Schema: map[string]*schema.Schema{},
+			Read:   read,
+			ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				return diag.FromErr(read(d, meta))
+			},
+		},
+	})
+
+	recording := &resourceRecordingTransport{}
+	config := &transport_tpg.Config{Client: &http.Client{Transport: recording}}
+	d := schema.TestResourceDataRaw(t, resources["google_test"].Schema, map[string]interface{}{})
+	d.SetId("projects/p/tests/t")
+
+	if err := resources["google_test"].Read(d, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diags := resources["google_test"].ReadContext(context.Background(), d, config); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if config.RequestContext != nil {
+		t.Errorf("expected the provider's config to be left unchanged")
+	}
+	if len(recording.resourceTypes) != 4 {
+		t.Fatalf("expected 4 requests, got %d", len(recording.resourceTypes))
+	}
+	for i := range recording.resourceTypes {
+		if recording.resourceTypes[i] != "google_test" || recording.ids[i] != "projects/p/tests/t" {
+			t.Errorf("expected request %d to be made for the resource, got %q %q", i, recording.resourceTypes[i], recording.ids[i])
+		}
+	}
+}
+
+func TestWithResourceContext_traces(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	orig := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(orig) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	resources := withResourceContext(map[string]*schema.Resource{
+		"google_test": {
+			Schema: map[string]*schema.Schema{},
+			CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				config := meta.(*transport_tpg.Config)
+				transport_tpg.MutexStore.LockInContext(config.RequestContext, "test")
+				defer transport_tpg.MutexStore.Unlock("test")
+				if _, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+					Config: config,
+					Method: "POST",
+					RawURL: server.URL,
+				}); err != nil {
+					return diag.FromErr(err)
+				}
+				d.SetId("projects/p/tests/t")
+				return nil
+			},
+		},
+	})
+
+	config := &transport_tpg.Config{Client: server.Client()}
+	d := schema.TestResourceDataRaw(t, resources["google_test"].Schema, map[string]interface{}{})
+	if diags := resources["google_test"].CreateContext(context.Background(), d, config); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	root, ok := spans["google_test Create"]
+	if !ok {
+		t.Fatalf("expected a span for the Create call, got %v", spans)
+	}
+	for _, attr := range root.Attributes() {
+		if attr.Key == "terraform.resource.id" && attr.Value.AsString() != "projects/p/tests/t" {
+			t.Errorf("expected the span to record the created resource's ID, got %q", attr.Value.AsString())
+		}
+	}
+	for _, name := range []string{"MutexKV.Lock", "SendRequest"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Fatalf("expected a %s span, got %v", name, spans)
+		}
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("expected the %s span to be a child of the Create span", name)
+		}
+	}
+}
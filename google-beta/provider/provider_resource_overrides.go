@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/services/compute"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/services/storage"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgiamresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
+)
+
+// resourceOverrides add handwritten behaviour to generated resources, keyed
+// by resource name. The resources are shared between calls to ResourceMap,
+// so overrides must be safe to apply more than once.
+var resourceOverrides = map[string]func(*schema.Resource){
+	// Planned firewall rules are analyzed when firewall_analysis is set.
+	"google_compute_firewall":                     withCustomizeDiff(compute.ResourceComputeFirewall, compute.ResourceComputeFirewallAnalysisCustomizeDiff),
+	"google_compute_firewall_policy_rule":         withCustomizeDiff(compute.ResourceComputeFirewallPolicyRule, compute.ResourceComputeFirewallPolicyRuleAnalysisCustomizeDiff),
+	"google_compute_network_firewall_policy_rule": withCustomizeDiff(compute.ResourceComputeNetworkFirewallPolicyRule, compute.ResourceComputeNetworkFirewallPolicyRuleAnalysisCustomizeDiff),
+	"google_iam_deny_policy": func(r *schema.Resource) {
+		expression := nestedSchema(r, "rules", "deny_rule", "denial_condition", "expression")
+		expression.ValidateFunc = verify.ValidateCELExpression(verify.IAMDenyConditionCELEnvironment)
+	},
+	// Members of the same bucket are batched into a single read-modify-write
+	// of its policy.
+	"google_storage_bucket_iam_member": func(r *schema.Resource) {
+		*r = *tpgiamresource.ResourceIamMember(storage.StorageBucketIamSchema, storage.StorageBucketIamUpdaterProducer, storage.StorageBucketIdParseFunc, tpgiamresource.IamWithBatchType(transport_tpg.BatchTypeStorageBucketIam))
+	},
+}
+
+// withResourceOverrides applies resourceOverrides to resources in place.
+func withResourceOverrides(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for name, override := range resourceOverrides {
+		if r, ok := resources[name]; ok {
+			override(r)
+		}
+	}
+	return resources
+}
+
+// withCustomizeDiff returns an override that rebuilds a resource with
+// newResource, and runs customizeDiff after the resource's own CustomizeDiff.
+// Rebuilding the resource keeps the override safe to apply more than once.
+func withCustomizeDiff(newResource func() *schema.Resource, customizeDiff schema.CustomizeDiffFunc) func(*schema.Resource) {
+	return func(r *schema.Resource) {
+		*r = *newResource()
+		if r.CustomizeDiff == nil {
+			r.CustomizeDiff = customizeDiff
+			return
+		}
+		r.CustomizeDiff = customdiff.All(r.CustomizeDiff, customizeDiff)
+	}
+}
+
+// nestedSchema returns the schema of a field nested in blocks of r, given
+// the names of the blocks and the field.
+func nestedSchema(r *schema.Resource, path ...string) *schema.Schema {
+	s := r.Schema[path[0]]
+	for _, name := range path[1:] {
+		s = s.Elem.(*schema.Resource).Schema[name]
+	}
+	return s
+}
@@ -0,0 +1,22 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package provider
+
+import (
+	"testing"
+)
+
+func TestResourceOverrides(t *testing.T) {
+	resources := Provider().ResourcesMap
+	for name := range resourceOverrides {
+		if _, ok := resources[name]; !ok {
+			t.Errorf("resource %q is overridden, but not in the provider", name)
+		}
+	}
+
+	expression := nestedSchema(resources["google_iam_deny_policy"], "rules", "deny_rule", "denial_condition", "expression")
+	ws, errs := expression.ValidateFunc("resource.matchTag('123/env', 'prod') &&", "expression")
+	if len(ws) != 0 || len(errs) != 1 {
+		t.Errorf("expected the deny condition to fail validation, got %q, %v", ws, errs)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
 	googleoauth "golang.org/x/oauth2/google"
 )
 
@@ -47,3 +49,14 @@ func ValidateEmptyStrings(v interface{}, k string) (warnings []string, errors []
 
 	return
 }
+
+// validateStringWith adapts a string validation function shared with the
+// plugin framework provider to a schema.SchemaValidateFunc.
+func validateStringWith(f func(string) error) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (warnings []string, errors []error) {
+		if err := f(v.(string)); err != nil {
+			errors = append(errors, err)
+		}
+		return
+	}
+}
@@ -65,7 +65,7 @@ func AccessContextManagerOperationWaitTimeWithResponse(config *transport_tpg.Con
 	if err != nil {
 		return err
 	}
-	if err := tpgresource.OperationWait(w, activity, timeout, config.PollInterval); err != nil {
+	if err := tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy("")); err != nil {
 		return err
 	}
 	rawResponse := []byte(w.CommonOperationWaiter.Op.Response)
@@ -85,5 +85,5 @@ func AccessContextManagerOperationWaitTime(config *transport_tpg.Config, op map[
 		// If w is nil, the op was synchronous.
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy(""))
 }
@@ -291,7 +291,7 @@ func resourceAccessContextManagerAccessLevelConditionCreate(d *schema.ResourceDa
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{access_level}}")
@@ -471,7 +471,7 @@ func resourceAccessContextManagerAccessLevelConditionDelete(d *schema.ResourceDa
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{access_level}}")
@@ -925,7 +925,7 @@ func resourceAccessContextManagerServicePerimeterCreate(d *schema.ResourceData,
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{parent}}/servicePerimeters")
@@ -1101,7 +1101,7 @@ func resourceAccessContextManagerServicePerimeterUpdate(d *schema.ResourceData,
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{name}}")
@@ -1186,7 +1186,7 @@ func resourceAccessContextManagerServicePerimeterDelete(d *schema.ResourceData,
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{name}}")
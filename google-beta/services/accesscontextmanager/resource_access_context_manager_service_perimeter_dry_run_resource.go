@@ -84,7 +84,7 @@ func resourceAccessContextManagerServicePerimeterDryRunResourceCreate(d *schema.
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{perimeter_name}}")
@@ -231,7 +231,7 @@ func resourceAccessContextManagerServicePerimeterDryRunResourceDelete(d *schema.
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{perimeter_name}}")
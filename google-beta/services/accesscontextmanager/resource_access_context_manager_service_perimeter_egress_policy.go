@@ -220,7 +220,7 @@ func resourceAccessContextManagerServicePerimeterEgressPolicyCreate(d *schema.Re
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{perimeter}}")
@@ -372,7 +372,7 @@ func resourceAccessContextManagerServicePerimeterEgressPolicyDelete(d *schema.Re
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{perimeter}}")
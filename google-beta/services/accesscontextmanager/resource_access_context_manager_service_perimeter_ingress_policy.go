@@ -224,7 +224,7 @@ func resourceAccessContextManagerServicePerimeterIngressPolicyCreate(d *schema.R
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{perimeter}}")
@@ -376,7 +376,7 @@ func resourceAccessContextManagerServicePerimeterIngressPolicyDelete(d *schema.R
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{AccessContextManagerBasePath}}{{perimeter}}")
@@ -92,7 +92,7 @@ func testSweepApigeeKeystoresAliasesKeyCertFile(region string) error {
 			return nil
 		}
 		// Skip resources that shouldn't be sweeped
-		if !sweeper.IsSweepableTestResource(name) {
+		if !sweeper.IsSweepableTestResourceObject(name, obj) {
 			nonPrefixCount++
 			continue
 		}
@@ -113,6 +113,7 @@ func testSweepApigeeKeystoresAliasesKeyCertFile(region string) error {
 			RawURL:    deleteUrl,
 			UserAgent: config.UserAgent,
 		})
+		sweeper.RecordSweepResult(name, err)
 		if err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 		} else {
@@ -65,7 +65,7 @@ func testSweepAppEngineAppVersion(region string) error {
 
 		id := obj["id"].(string)
 		// Increment count and skip if resource is not sweepable.
-		if !sweeper.IsSweepableTestResource(id) {
+		if !sweeper.IsSweepableTestResourceObject(id, obj) {
 			nonPrefixCount++
 			continue
 		}
@@ -79,6 +79,7 @@ func testSweepAppEngineAppVersion(region string) error {
 			RawURL:    deleteUrl,
 			UserAgent: config.UserAgent,
 		})
+		sweeper.RecordSweepResult(id, err)
 		if err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 		} else {
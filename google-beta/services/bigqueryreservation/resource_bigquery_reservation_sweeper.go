@@ -67,7 +67,7 @@ func testSweepBigqueryReservation(region string) error {
 		reservationNameParts := strings.Split(reservationName, "/")
 		reservationShortName := reservationNameParts[len(reservationNameParts)-1]
 		// Increment count and skip if resource is not sweepable.
-		if !sweeper.IsSweepableTestResource(reservationShortName) {
+		if !sweeper.IsSweepableTestResourceObject(reservationShortName, obj) {
 			nonPrefixCount++
 			continue
 		}
@@ -83,6 +83,7 @@ func testSweepBigqueryReservation(region string) error {
 			RawURL:    deleteUrl,
 			UserAgent: config.UserAgent,
 		})
+		sweeper.RecordSweepResult(reservationShortName, err)
 		if err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 		} else {
@@ -31,5 +31,5 @@ func ComposerOperationWaitTime(config *transport_tpg.Config, op *composer.Operat
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy(""))
 }
@@ -95,10 +95,12 @@ func testSweepComposerEnvironments(config *transport_tpg.Config, region string)
 		default:
 			op, deleteErr := config.NewComposerClient(config.UserAgent).Projects.Locations.Environments.Delete(e.Name).Do()
 			if deleteErr != nil {
+				sweeper.RecordSweepResult(e.Name, deleteErr)
 				allErrors = multierror.Append(allErrors, fmt.Errorf("composer: unable to delete environment %q: %s", e.Name, deleteErr))
 				continue
 			}
 			waitErr := ComposerOperationWaitTime(config, op, config.Project, "Sweeping old test environments", config.UserAgent, 10*time.Minute)
+			sweeper.RecordSweepResult(e.Name, waitErr)
 			if waitErr != nil {
 				allErrors = multierror.Append(allErrors, fmt.Errorf("composer: unable to delete environment %q: %s", e.Name, waitErr))
 			}
@@ -0,0 +1,478 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package compute
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+
+	compute "google.golang.org/api/compute/v0.beta"
+)
+
+// Kinds of findings reported by the firewall rule analysis.
+const (
+	FirewallAnalysisShadowed          = "shadowed"
+	FirewallAnalysisDuplicatePriority = "duplicate_priority"
+	FirewallAnalysisConflict          = "conflict"
+)
+
+// firewallPolicyDefaultRulePriority is the priority of the first of the
+// catch-all rules that firewall policies are created with. Being matched by
+// every rule before them is their purpose, so they are never reported as
+// shadowed.
+const firewallPolicyDefaultRulePriority = 2147483644
+
+// FirewallAnalysisFinding is an issue found between two firewall rules.
+type FirewallAnalysisFinding struct {
+	Kind        string
+	Rule        string
+	RelatedRule string
+	Message     string
+}
+
+// firewallAnalysisRule is a VPC firewall rule or a firewall policy rule,
+// reduced to the traffic it matches.
+type firewallAnalysisRule struct {
+	// Name identifies the rule in findings.
+	Name      string
+	Priority  int64
+	Direction string
+	// Action is "allow" or "deny" for rules that end evaluation, or any
+	// other action, such as "goto_next", for rules that don't.
+	Action   string
+	Disabled bool
+
+	Source      firewallAnalysisEndpoint
+	Destination firewallAnalysisEndpoint
+
+	// Targets are the instances the rule applies to, as "kind:value"
+	// strings. Empty means all instances in the network.
+	Targets []string
+
+	// Layer4 lists the protocols and ports the rule matches. Empty means all
+	// protocols.
+	Layer4 []firewallAnalysisLayer4
+}
+
+// firewallAnalysisEndpoint is one side of the connections matched by a rule.
+// It matches an address in any of Ranges, or any peer such as an instance
+// with a given tag, given as "kind:value". Empty matches everything.
+type firewallAnalysisEndpoint struct {
+	Ranges []*net.IPNet
+	Peers  []string
+}
+
+type firewallAnalysisLayer4 struct {
+	Protocol string
+	// Ports are inclusive ranges of ports. Empty means all ports.
+	Ports [][2]int
+}
+
+func (r *firewallAnalysisRule) terminal() bool {
+	return r.Action == "allow" || r.Action == "deny"
+}
+
+// precedes returns whether r is evaluated before other. Within a firewall
+// policy, priorities are unique. Between VPC firewall rules of the same
+// priority, deny rules take precedence.
+func (r *firewallAnalysisRule) precedes(other *firewallAnalysisRule, policy bool) bool {
+	if r.Priority != other.Priority {
+		return r.Priority < other.Priority
+	}
+	return !policy && r.Action == "deny" && other.Action == "allow"
+}
+
+// covers returns whether r matches all traffic that other matches.
+func (r *firewallAnalysisRule) covers(other *firewallAnalysisRule) bool {
+	return r.Source.covers(other.Source) &&
+		r.Destination.covers(other.Destination) &&
+		stringSetCovers(r.Targets, other.Targets) &&
+		layer4Covers(r.Layer4, other.Layer4)
+}
+
+// overlaps returns whether some traffic may be matched by both r and other.
+func (r *firewallAnalysisRule) overlaps(other *firewallAnalysisRule) bool {
+	return r.Source.overlaps(other.Source) &&
+		r.Destination.overlaps(other.Destination) &&
+		stringSetsOverlap(r.Targets, other.Targets) &&
+		layer4Overlaps(r.Layer4, other.Layer4)
+}
+
+func (e firewallAnalysisEndpoint) any() bool {
+	return len(e.Ranges) == 0 && len(e.Peers) == 0
+}
+
+func (e firewallAnalysisEndpoint) covers(other firewallAnalysisEndpoint) bool {
+	if e.any() {
+		return true
+	}
+	if other.any() {
+		return false
+	}
+	for _, o := range other.Ranges {
+		contained := false
+		for _, r := range e.Ranges {
+			if tpgresource.CidrContains(r, o) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+	for _, o := range other.Peers {
+		if !tpgresource.StringInSlice(e.Peers, o) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e firewallAnalysisEndpoint) overlaps(other firewallAnalysisEndpoint) bool {
+	if e.any() || other.any() {
+		return true
+	}
+	for _, r := range e.Ranges {
+		for _, o := range other.Ranges {
+			if tpgresource.CidrsOverlap(r, o) {
+				return true
+			}
+		}
+	}
+	// Peers such as tags and ranges can't be compared, so only identical
+	// peers are known to overlap.
+	return len(e.Peers) > 0 && len(other.Peers) > 0 && stringSetsOverlap(e.Peers, other.Peers)
+}
+
+// stringSetCovers returns whether a, where empty means everything, includes
+// all of b.
+func stringSetCovers(a, b []string) bool {
+	if len(a) == 0 {
+		return true
+	}
+	if len(b) == 0 {
+		return false
+	}
+	for _, s := range b {
+		if !tpgresource.StringInSlice(a, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// stringSetsOverlap returns whether a and b, where empty means everything,
+// have an element in common.
+func stringSetsOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, s := range b {
+		if tpgresource.StringInSlice(a, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func layer4Covers(a, b []firewallAnalysisLayer4) bool {
+	if len(a) == 0 {
+		return true
+	}
+	if len(b) == 0 {
+		b = []firewallAnalysisLayer4{{Protocol: "all"}}
+	}
+	for _, bl := range b {
+		var ports [][2]int
+		covered := false
+		for _, al := range a {
+			if al.Protocol != "all" && al.Protocol != bl.Protocol {
+				continue
+			}
+			if al.Protocol == "all" || len(al.Ports) == 0 {
+				covered = true
+				break
+			}
+			ports = append(ports, al.Ports...)
+		}
+		if !covered && (len(bl.Ports) == 0 || !portRangesCover(ports, bl.Ports)) {
+			return false
+		}
+	}
+	return true
+}
+
+func layer4Overlaps(a, b []firewallAnalysisLayer4) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, al := range a {
+		for _, bl := range b {
+			if al.Protocol != "all" && bl.Protocol != "all" && al.Protocol != bl.Protocol {
+				continue
+			}
+			if len(al.Ports) == 0 || len(bl.Ports) == 0 {
+				return true
+			}
+			for _, ap := range al.Ports {
+				for _, bp := range bl.Ports {
+					if ap[0] <= bp[1] && bp[0] <= ap[1] {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// portRangesCover returns whether every port in b is in one of the ranges
+// in a.
+func portRangesCover(a, b [][2]int) bool {
+	merged := append([][2]int{}, a...)
+	sort.Slice(merged, func(i, j int) bool { return merged[i][0] < merged[j][0] })
+	for i := 1; i < len(merged); i++ {
+		if merged[i][0] <= merged[i-1][1]+1 {
+			if merged[i][1] > merged[i-1][1] {
+				merged[i-1][1] = merged[i][1]
+			}
+			merged = append(merged[:i], merged[i+1:]...)
+			i--
+		}
+	}
+	for _, p := range b {
+		covered := false
+		for _, m := range merged {
+			if m[0] <= p[0] && p[1] <= m[1] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// analyzeFirewallRulePair returns the findings between rule a, which
+// precedes rule b, and rule b.
+func analyzeFirewallRulePair(a, b *firewallAnalysisRule, policy bool) []FirewallAnalysisFinding {
+	var findings []FirewallAnalysisFinding
+	if policy && a.Priority == b.Priority {
+		findings = append(findings, FirewallAnalysisFinding{
+			Kind:        FirewallAnalysisDuplicatePriority,
+			Rule:        b.Name,
+			RelatedRule: a.Name,
+			Message:     fmt.Sprintf("rule %s has the same priority, %d, as rule %s in the firewall policy", b.Name, b.Priority, a.Name),
+		})
+		return findings
+	}
+	// Disabled rules don't match any traffic, but still take up their
+	// priority.
+	if a.Disabled || b.Disabled || a.Direction != b.Direction {
+		return findings
+	}
+
+	switch {
+	case a.terminal() && b.Priority < firewallPolicyDefaultRulePriority && a.covers(b):
+		findings = append(findings, FirewallAnalysisFinding{
+			Kind:        FirewallAnalysisShadowed,
+			Rule:        b.Name,
+			RelatedRule: a.Name,
+			Message:     fmt.Sprintf("rule %s (%s, priority %d) never matches any traffic, as all of it is matched first by rule %s (%s, priority %d)", b.Name, b.Action, b.Priority, a.Name, a.Action, a.Priority),
+		})
+	case a.terminal() && b.terminal() && a.Action != b.Action && a.overlaps(b):
+		findings = append(findings, FirewallAnalysisFinding{
+			Kind:        FirewallAnalysisConflict,
+			Rule:        b.Name,
+			RelatedRule: a.Name,
+			Message:     fmt.Sprintf("rule %s (%s, priority %d) matches some of the same traffic as rule %s (%s, priority %d), which takes precedence for that traffic", b.Name, b.Action, b.Priority, a.Name, a.Action, a.Priority),
+		})
+	}
+	return findings
+}
+
+// analyzeFirewallRules returns the findings between every pair of rules.
+// policy is true for the rules of a firewall policy, and false for the VPC
+// firewall rules of a network.
+func analyzeFirewallRules(rules []*firewallAnalysisRule, policy bool) []FirewallAnalysisFinding {
+	var findings []FirewallAnalysisFinding
+	for i, a := range rules {
+		for _, b := range rules[i+1:] {
+			findings = append(findings, analyzeFirewallRuleAgainst(a, b, policy)...)
+		}
+	}
+	return findings
+}
+
+// analyzeFirewallRuleAgainst returns the findings between rule and other,
+// whichever of them is evaluated first.
+func analyzeFirewallRuleAgainst(rule, other *firewallAnalysisRule, policy bool) []FirewallAnalysisFinding {
+	if other.precedes(rule, policy) || (policy && other.Priority == rule.Priority) {
+		return analyzeFirewallRulePair(other, rule, policy)
+	}
+	if rule.precedes(other, policy) {
+		return analyzeFirewallRulePair(rule, other, policy)
+	}
+	return nil
+}
+
+// parseFirewallAnalysisLayer4 reads a protocol and its ports as given to a
+// firewall rule.
+func parseFirewallAnalysisLayer4(protocol string, ports []string) (firewallAnalysisLayer4, error) {
+	l := firewallAnalysisLayer4{Protocol: normalizeFirewallProtocol(protocol)}
+	for _, p := range ports {
+		lo, hi, found := strings.Cut(p, "-")
+		if !found {
+			hi = lo
+		}
+		loPort, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return l, fmt.Errorf("invalid port %q", p)
+		}
+		hiPort, err := strconv.Atoi(strings.TrimSpace(hi))
+		if err != nil {
+			return l, fmt.Errorf("invalid port %q", p)
+		}
+		l.Ports = append(l.Ports, [2]int{loPort, hiPort})
+	}
+	return l, nil
+}
+
+// normalizeFirewallProtocol maps well known protocol numbers to the names
+// the API also accepts for them.
+func normalizeFirewallProtocol(protocol string) string {
+	protocol = strings.ToLower(protocol)
+	names := map[string]string{
+		"1":   "icmp",
+		"4":   "ipip",
+		"6":   "tcp",
+		"17":  "udp",
+		"50":  "esp",
+		"51":  "ah",
+		"132": "sctp",
+	}
+	if name, ok := names[protocol]; ok {
+		return name
+	}
+	return protocol
+}
+
+func prefixedStrings(prefix string, values []string) []string {
+	var prefixed []string
+	for _, v := range values {
+		prefixed = append(prefixed, prefix+v)
+	}
+	return prefixed
+}
+
+// newFirewallAnalysisRuleFromFirewall converts a VPC firewall rule.
+func newFirewallAnalysisRuleFromFirewall(fw *compute.Firewall) (*firewallAnalysisRule, error) {
+	r := &firewallAnalysisRule{
+		Name:      fw.Name,
+		Priority:  fw.Priority,
+		Direction: fw.Direction,
+		Disabled:  fw.Disabled,
+		Targets:   append(prefixedStrings("tag:", fw.TargetTags), prefixedStrings("serviceAccount:", fw.TargetServiceAccounts)...),
+	}
+	if r.Direction == "" {
+		r.Direction = "INGRESS"
+	}
+
+	var err error
+	if r.Source.Ranges, err = tpgresource.ParseCidrs(fw.SourceRanges); err != nil {
+		return nil, err
+	}
+	r.Source.Peers = append(prefixedStrings("tag:", fw.SourceTags), prefixedStrings("serviceAccount:", fw.SourceServiceAccounts)...)
+	destinationRanges := fw.DestinationRanges
+	if r.Direction == "EGRESS" && len(destinationRanges) == 0 {
+		destinationRanges = []string{"0.0.0.0/0"}
+	}
+	if r.Destination.Ranges, err = tpgresource.ParseCidrs(destinationRanges); err != nil {
+		return nil, err
+	}
+
+	r.Action = "allow"
+	for _, a := range fw.Allowed {
+		l, err := parseFirewallAnalysisLayer4(a.IPProtocol, a.Ports)
+		if err != nil {
+			return nil, err
+		}
+		r.Layer4 = append(r.Layer4, l)
+	}
+	if len(fw.Denied) > 0 {
+		r.Action = "deny"
+	}
+	for _, d := range fw.Denied {
+		l, err := parseFirewallAnalysisLayer4(d.IPProtocol, d.Ports)
+		if err != nil {
+			return nil, err
+		}
+		r.Layer4 = append(r.Layer4, l)
+	}
+	return r, nil
+}
+
+// newFirewallAnalysisRuleFromPolicyRule converts a firewall policy rule.
+func newFirewallAnalysisRuleFromPolicyRule(pr *compute.FirewallPolicyRule) (*firewallAnalysisRule, error) {
+	r := &firewallAnalysisRule{
+		Name:      firewallPolicyRuleAnalysisName(pr.Priority, pr.RuleName),
+		Priority:  pr.Priority,
+		Direction: pr.Direction,
+		Action:    pr.Action,
+		Disabled:  pr.Disabled,
+	}
+	r.Targets = append(r.Targets, prefixedStrings("serviceAccount:", pr.TargetServiceAccounts)...)
+	r.Targets = append(r.Targets, prefixedStrings("resource:", pr.TargetResources)...)
+	for _, t := range pr.TargetSecureTags {
+		r.Targets = append(r.Targets, "secureTag:"+t.Name)
+	}
+	if pr.Match == nil {
+		return r, nil
+	}
+
+	var err error
+	if r.Source.Ranges, err = tpgresource.ParseCidrs(pr.Match.SrcIpRanges); err != nil {
+		return nil, err
+	}
+	if r.Destination.Ranges, err = tpgresource.ParseCidrs(pr.Match.DestIpRanges); err != nil {
+		return nil, err
+	}
+	r.Source.Peers = firewallPolicyRuleAnalysisPeers(pr.Match.SrcFqdns, pr.Match.SrcRegionCodes, pr.Match.SrcThreatIntelligences, pr.Match.SrcAddressGroups)
+	for _, t := range pr.Match.SrcSecureTags {
+		r.Source.Peers = append(r.Source.Peers, "secureTag:"+t.Name)
+	}
+	r.Destination.Peers = firewallPolicyRuleAnalysisPeers(pr.Match.DestFqdns, pr.Match.DestRegionCodes, pr.Match.DestThreatIntelligences, pr.Match.DestAddressGroups)
+
+	for _, l4 := range pr.Match.Layer4Configs {
+		l, err := parseFirewallAnalysisLayer4(l4.IpProtocol, l4.Ports)
+		if err != nil {
+			return nil, err
+		}
+		r.Layer4 = append(r.Layer4, l)
+	}
+	return r, nil
+}
+
+func firewallPolicyRuleAnalysisName(priority int64, ruleName string) string {
+	if ruleName != "" {
+		return fmt.Sprintf("%d (%s)", priority, ruleName)
+	}
+	return strconv.FormatInt(priority, 10)
+}
+
+func firewallPolicyRuleAnalysisPeers(fqdns, regionCodes, threatIntelligences, addressGroups []string) []string {
+	var peers []string
+	peers = append(peers, prefixedStrings("fqdn:", fqdns)...)
+	peers = append(peers, prefixedStrings("regionCode:", regionCodes)...)
+	peers = append(peers, prefixedStrings("threatIntelligence:", threatIntelligences)...)
+	peers = append(peers, prefixedStrings("addressGroup:", addressGroups)...)
+	return peers
+}
@@ -0,0 +1,346 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+
+	compute "google.golang.org/api/compute/v0.beta"
+)
+
+// loadFirewallAnalysisRules returns the VPC firewall rules of the network in
+// the project.
+func loadFirewallAnalysisRules(ctx context.Context, config *transport_tpg.Config, userAgent, project, network string) ([]*firewallAnalysisRule, error) {
+	networkName := tpgresource.GetResourceNameFromSelfLink(network)
+
+	var rules []*firewallAnalysisRule
+	err := config.NewComputeClient(userAgent).Firewalls.List(project).Pages(ctx, func(page *compute.FirewallList) error {
+		for _, fw := range page.Items {
+			if tpgresource.GetResourceNameFromSelfLink(fw.Network) != networkName {
+				continue
+			}
+			r, err := newFirewallAnalysisRuleFromFirewall(fw)
+			if err != nil {
+				return fmt.Errorf("unable to analyze firewall rule %s: %s", fw.Name, err)
+			}
+			rules = append(rules, r)
+		}
+		return nil
+	})
+	return rules, err
+}
+
+// loadFirewallPolicyAnalysisRules returns the rules of a global network
+// firewall policy in the project or, if project is empty, of a hierarchical
+// firewall policy.
+func loadFirewallPolicyAnalysisRules(config *transport_tpg.Config, userAgent, project, policy string) ([]*firewallAnalysisRule, error) {
+	client := config.NewComputeClient(userAgent)
+	policyName := tpgresource.GetResourceNameFromSelfLink(policy)
+
+	var fp *compute.FirewallPolicy
+	var err error
+	if project != "" {
+		fp, err = client.NetworkFirewallPolicies.Get(project, policyName).Do()
+	} else {
+		fp, err = client.FirewallPolicies.Get(policyName).Do()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*firewallAnalysisRule
+	for _, pr := range fp.Rules {
+		r, err := newFirewallAnalysisRuleFromPolicyRule(pr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to analyze firewall policy rule %d: %s", pr.Priority, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// firewallAnalysisCache holds the rules of the networks and firewall policies
+// that planned rules are compared to, so that planning many rules of one
+// network or policy lists its rules once per run rather than once per rule.
+// Entries are keyed by the provider's config, as each run configures it anew.
+var firewallAnalysisCache sync.Map
+
+type firewallAnalysisCacheKey struct {
+	config *transport_tpg.Config
+	// parent is the network or firewall policy of the rules, with its
+	// project if any.
+	parent string
+}
+
+type firewallAnalysisCacheEntry struct {
+	once  sync.Once
+	rules []*firewallAnalysisRule
+	err   error
+}
+
+// cachedFirewallAnalysisRules returns the rules cached for key, calling load
+// for the first caller only.
+func cachedFirewallAnalysisRules(key firewallAnalysisCacheKey, load func() ([]*firewallAnalysisRule, error)) ([]*firewallAnalysisRule, error) {
+	v, _ := firewallAnalysisCache.LoadOrStore(key, &firewallAnalysisCacheEntry{})
+	e := v.(*firewallAnalysisCacheEntry)
+	e.once.Do(func() {
+		e.rules, e.err = load()
+	})
+	return e.rules, e.err
+}
+
+// otherFirewallAnalysisRules returns the rules for which exclude is false.
+func otherFirewallAnalysisRules(rules []*firewallAnalysisRule, exclude func(*firewallAnalysisRule) bool) []*firewallAnalysisRule {
+	var others []*firewallAnalysisRule
+	for _, r := range rules {
+		if !exclude(r) {
+			others = append(others, r)
+		}
+	}
+	return others
+}
+
+// firewallAnalysisNeeded returns whether a planned rule must be analyzed,
+// which is when it is created or any of keys changes.
+func firewallAnalysisNeeded(diff *schema.ResourceDiff, keys ...string) bool {
+	return diff.Id() == "" || diff.HasChanges(keys...)
+}
+
+// firewallAnalysisKnown returns whether the planned values of all keys are
+// known, so that the rule can be analyzed.
+func firewallAnalysisKnown(diff *schema.ResourceDiff, keys ...string) bool {
+	for _, k := range keys {
+		if !diff.NewValueKnown(k) {
+			log.Printf("[DEBUG] Skipping firewall rule analysis, %s is not known until apply", k)
+			return false
+		}
+	}
+	return true
+}
+
+// reportFirewallAnalysisFindings logs the findings for the rule planned in a
+// resource, and fails the plan if the provider's firewall_analysis is
+// "error". CustomizeDiff can't return warnings, so with "log" the findings
+// are only visible in the provider's logs.
+func reportFirewallAnalysisFindings(config *transport_tpg.Config, resource string, findings []FirewallAnalysisFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, f := range findings {
+		log.Printf("[WARN] Firewall rule analysis of %s: %s", resource, f.Message)
+		messages = append(messages, "- "+f.Message)
+	}
+	if config.FirewallAnalysis == transport_tpg.FirewallAnalysisError {
+		return fmt.Errorf("firewall rule analysis of %s found %d issue(s):\n%s", resource, len(findings), strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+func analyzeFirewallRuleFindings(rule *firewallAnalysisRule, existing []*firewallAnalysisRule, policy bool) []FirewallAnalysisFinding {
+	var findings []FirewallAnalysisFinding
+	for _, other := range existing {
+		findings = append(findings, analyzeFirewallRuleAgainst(rule, other, policy)...)
+	}
+	return findings
+}
+
+// ResourceComputeFirewallAnalysisCustomizeDiff compares the planned VPC
+// firewall rule to the other rules of its network when the provider's
+// firewall_analysis is set.
+func ResourceComputeFirewallAnalysisCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	config := meta.(*transport_tpg.Config)
+	if config.FirewallAnalysis == "" {
+		return nil
+	}
+	// destination_ranges is checked below, as it is unknown when unset.
+	keys := []string{"name", "network", "priority", "direction", "disabled", "allow", "deny", "source_ranges", "source_tags", "source_service_accounts", "target_tags", "target_service_accounts"}
+	if !firewallAnalysisNeeded(diff, append(keys, "destination_ranges")...) || !firewallAnalysisKnown(diff, keys...) {
+		return nil
+	}
+
+	fw := &compute.Firewall{
+		Name:                  diff.Get("name").(string),
+		Priority:              int64(diff.Get("priority").(int)),
+		Direction:             diff.Get("direction").(string),
+		Disabled:              diff.Get("disabled").(bool),
+		SourceRanges:          tpgresource.ConvertStringSet(diff.Get("source_ranges").(*schema.Set)),
+		SourceTags:            tpgresource.ConvertStringSet(diff.Get("source_tags").(*schema.Set)),
+		SourceServiceAccounts: tpgresource.ConvertStringSet(diff.Get("source_service_accounts").(*schema.Set)),
+		TargetTags:            tpgresource.ConvertStringSet(diff.Get("target_tags").(*schema.Set)),
+		TargetServiceAccounts: tpgresource.ConvertStringSet(diff.Get("target_service_accounts").(*schema.Set)),
+	}
+	// destination_ranges is computed, so an unset value is unknown until
+	// apply, where the API defaults it for egress rules.
+	if raw := diff.GetRawConfig(); !raw.IsNull() && raw.IsKnown() {
+		v := raw.GetAttr("destination_ranges")
+		if !v.IsWhollyKnown() {
+			return nil
+		}
+		if !v.IsNull() {
+			fw.DestinationRanges = tpgresource.ConvertStringSet(diff.Get("destination_ranges").(*schema.Set))
+		}
+	}
+	for _, raw := range diff.Get("allow").(*schema.Set).List() {
+		m := raw.(map[string]interface{})
+		fw.Allowed = append(fw.Allowed, &compute.FirewallAllowed{
+			IPProtocol: m["protocol"].(string),
+			Ports:      tpgresource.ConvertStringArr(m["ports"].([]interface{})),
+		})
+	}
+	for _, raw := range diff.Get("deny").(*schema.Set).List() {
+		m := raw.(map[string]interface{})
+		fw.Denied = append(fw.Denied, &compute.FirewallDenied{
+			IPProtocol: m["protocol"].(string),
+			Ports:      tpgresource.ConvertStringArr(m["ports"].([]interface{})),
+		})
+	}
+
+	rule, err := newFirewallAnalysisRuleFromFirewall(fw)
+	if err != nil {
+		log.Printf("[DEBUG] Skipping firewall rule analysis of %s: %s", fw.Name, err)
+		return nil
+	}
+
+	project, err := tpgresource.GetProjectFromDiff(diff, config)
+	if err != nil {
+		return nil
+	}
+	network := diff.Get("network").(string)
+	key := firewallAnalysisCacheKey{config: config, parent: project + "/" + tpgresource.GetResourceNameFromSelfLink(network)}
+	all, err := cachedFirewallAnalysisRules(key, func() ([]*firewallAnalysisRule, error) {
+		return loadFirewallAnalysisRules(ctx, config, config.UserAgent, project, network)
+	})
+	if err != nil {
+		log.Printf("[WARN] Skipping firewall rule analysis of %s, unable to list the firewall rules of its network: %s", fw.Name, err)
+		return nil
+	}
+	existing := otherFirewallAnalysisRules(all, func(r *firewallAnalysisRule) bool {
+		return r.Name == fw.Name
+	})
+
+	return reportFirewallAnalysisFindings(config, fmt.Sprintf("firewall rule %s", fw.Name), analyzeFirewallRuleFindings(rule, existing, false))
+}
+
+// firewallPolicyRuleFromDiff builds the firewall policy rule planned in a
+// google_compute_network_firewall_policy_rule if network is true, or in a
+// google_compute_firewall_policy_rule otherwise.
+func firewallPolicyRuleFromDiff(diff *schema.ResourceDiff, network bool) *compute.FirewallPolicyRule {
+	pr := &compute.FirewallPolicyRule{
+		Action:                diff.Get("action").(string),
+		Direction:             diff.Get("direction").(string),
+		Priority:              int64(diff.Get("priority").(int)),
+		Disabled:              diff.Get("disabled").(bool),
+		TargetServiceAccounts: tpgresource.ConvertStringArr(diff.Get("target_service_accounts").([]interface{})),
+		Match:                 &compute.FirewallPolicyRuleMatcher{},
+	}
+	if network {
+		pr.RuleName = diff.Get("rule_name").(string)
+		for _, raw := range diff.Get("target_secure_tags").([]interface{}) {
+			pr.TargetSecureTags = append(pr.TargetSecureTags, &compute.FirewallPolicyRuleSecureTag{
+				Name: raw.(map[string]interface{})["name"].(string),
+			})
+		}
+		for _, raw := range diff.Get("match.0.src_secure_tags").([]interface{}) {
+			pr.Match.SrcSecureTags = append(pr.Match.SrcSecureTags, &compute.FirewallPolicyRuleSecureTag{
+				Name: raw.(map[string]interface{})["name"].(string),
+			})
+		}
+	} else {
+		pr.TargetResources = tpgresource.ConvertStringArr(diff.Get("target_resources").([]interface{}))
+	}
+
+	list := func(k string) []string {
+		return tpgresource.ConvertStringArr(diff.Get("match.0." + k).([]interface{}))
+	}
+	pr.Match.SrcIpRanges = list("src_ip_ranges")
+	pr.Match.DestIpRanges = list("dest_ip_ranges")
+	pr.Match.SrcFqdns = list("src_fqdns")
+	pr.Match.DestFqdns = list("dest_fqdns")
+	pr.Match.SrcRegionCodes = list("src_region_codes")
+	pr.Match.DestRegionCodes = list("dest_region_codes")
+	pr.Match.SrcThreatIntelligences = list("src_threat_intelligences")
+	pr.Match.DestThreatIntelligences = list("dest_threat_intelligences")
+	pr.Match.SrcAddressGroups = list("src_address_groups")
+	pr.Match.DestAddressGroups = list("dest_address_groups")
+	for _, raw := range diff.Get("match.0.layer4_configs").([]interface{}) {
+		m := raw.(map[string]interface{})
+		pr.Match.Layer4Configs = append(pr.Match.Layer4Configs, &compute.FirewallPolicyRuleMatcherLayer4Config{
+			IpProtocol: m["ip_protocol"].(string),
+			Ports:      tpgresource.ConvertStringArr(m["ports"].([]interface{})),
+		})
+	}
+	return pr
+}
+
+// firewallPolicyRuleAnalysisCustomizeDiff compares the planned firewall
+// policy rule to the other rules of its policy when the provider's
+// firewall_analysis is set. network is true for rules of global network
+// firewall policies, and false for rules of hierarchical firewall policies.
+func firewallPolicyRuleAnalysisCustomizeDiff(diff *schema.ResourceDiff, meta interface{}, network bool, keys ...string) error {
+	config := meta.(*transport_tpg.Config)
+	if config.FirewallAnalysis == "" {
+		return nil
+	}
+	keys = append([]string{"firewall_policy", "priority", "action", "direction", "disabled", "match"}, keys...)
+	if !firewallAnalysisNeeded(diff, keys...) || !firewallAnalysisKnown(diff, keys...) {
+		return nil
+	}
+
+	pr := firewallPolicyRuleFromDiff(diff, network)
+	rule, err := newFirewallAnalysisRuleFromPolicyRule(pr)
+	if err != nil {
+		log.Printf("[DEBUG] Skipping firewall rule analysis of firewall policy rule %d: %s", pr.Priority, err)
+		return nil
+	}
+
+	// An existing rule is stored at its priority, which can't be updated, so
+	// it is excluded until it is replaced.
+	exclude := int64(-1)
+	if diff.Id() != "" {
+		old, _ := diff.GetChange("priority")
+		exclude = int64(old.(int))
+	}
+
+	project := ""
+	if network {
+		if project, err = tpgresource.GetProjectFromDiff(diff, config); err != nil {
+			return nil
+		}
+	}
+	policy := diff.Get("firewall_policy").(string)
+	key := firewallAnalysisCacheKey{config: config, parent: project + "/" + tpgresource.GetResourceNameFromSelfLink(policy)}
+	all, err := cachedFirewallAnalysisRules(key, func() ([]*firewallAnalysisRule, error) {
+		return loadFirewallPolicyAnalysisRules(config, config.UserAgent, project, policy)
+	})
+	if err != nil {
+		log.Printf("[WARN] Skipping firewall rule analysis of firewall policy rule %d, unable to read firewall policy %s: %s", pr.Priority, policy, err)
+		return nil
+	}
+	existing := otherFirewallAnalysisRules(all, func(r *firewallAnalysisRule) bool {
+		return r.Priority == exclude
+	})
+
+	resource := fmt.Sprintf("rule %s of firewall policy %s", rule.Name, tpgresource.GetResourceNameFromSelfLink(policy))
+	return reportFirewallAnalysisFindings(config, resource, analyzeFirewallRuleFindings(rule, existing, true))
+}
+
+// ResourceComputeNetworkFirewallPolicyRuleAnalysisCustomizeDiff analyzes a
+// planned google_compute_network_firewall_policy_rule.
+func ResourceComputeNetworkFirewallPolicyRuleAnalysisCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	return firewallPolicyRuleAnalysisCustomizeDiff(diff, meta, true, "rule_name", "target_secure_tags", "target_service_accounts")
+}
+
+// ResourceComputeFirewallPolicyRuleAnalysisCustomizeDiff analyzes a planned
+// google_compute_firewall_policy_rule.
+func ResourceComputeFirewallPolicyRuleAnalysisCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	return firewallPolicyRuleAnalysisCustomizeDiff(diff, meta, false, "target_resources", "target_service_accounts")
+}
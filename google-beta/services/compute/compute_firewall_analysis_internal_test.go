@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package compute
+
+import (
+	"reflect"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+
+	compute "google.golang.org/api/compute/v0.beta"
+)
+
+func TestAnalyzeFirewallRules_vpc(t *testing.T) {
+	cases := map[string]struct {
+		Firewalls []*compute.Firewall
+		Expected  []FirewallAnalysisFinding
+	}{
+		"shadowed by a broader rule": {
+			Firewalls: []*compute.Firewall{
+				{
+					Name: "allow-all-tcp", Priority: 100, Direction: "INGRESS",
+					SourceRanges: []string{"10.0.0.0/8"},
+					Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp"}},
+				},
+				{
+					Name: "allow-ssh", Priority: 1000, Direction: "INGRESS",
+					SourceRanges: []string{"10.1.0.0/16"},
+					TargetTags:   []string{"bastion"},
+					Allowed:      []*compute.FirewallAllowed{{IPProtocol: "6", Ports: []string{"22"}}},
+				},
+			},
+			Expected: []FirewallAnalysisFinding{{Kind: FirewallAnalysisShadowed, Rule: "allow-ssh", RelatedRule: "allow-all-tcp"}},
+		},
+		"deny takes precedence at the same priority": {
+			Firewalls: []*compute.Firewall{
+				{
+					Name: "allow-web", Priority: 1000, Direction: "INGRESS",
+					SourceRanges: []string{"0.0.0.0/0"},
+					Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80", "443"}}},
+				},
+				{
+					Name: "deny-web", Priority: 1000, Direction: "INGRESS",
+					SourceRanges: []string{"0.0.0.0/0"},
+					Denied:       []*compute.FirewallDenied{{IPProtocol: "tcp", Ports: []string{"1-1000"}}},
+				},
+			},
+			Expected: []FirewallAnalysisFinding{{Kind: FirewallAnalysisShadowed, Rule: "allow-web", RelatedRule: "deny-web"}},
+		},
+		"partial overlap between allow and deny": {
+			Firewalls: []*compute.Firewall{
+				{
+					Name: "deny-internal", Priority: 500, Direction: "INGRESS",
+					SourceRanges: []string{"10.0.0.0/16"},
+					Denied:       []*compute.FirewallDenied{{IPProtocol: "tcp", Ports: []string{"8000-9000"}}},
+				},
+				{
+					Name: "allow-app", Priority: 1000, Direction: "INGRESS",
+					SourceRanges: []string{"10.0.0.0/8"},
+					Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"8080"}}},
+				},
+			},
+			Expected: []FirewallAnalysisFinding{{Kind: FirewallAnalysisConflict, Rule: "allow-app", RelatedRule: "deny-internal"}},
+		},
+		"no overlap": {
+			Firewalls: []*compute.Firewall{
+				{
+					Name: "deny-egress", Priority: 100, Direction: "EGRESS",
+					Denied: []*compute.FirewallDenied{{IPProtocol: "all"}},
+				},
+				{
+					Name: "allow-ssh", Priority: 1000, Direction: "INGRESS",
+					SourceRanges: []string{"35.235.240.0/20"},
+					Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}},
+				},
+				{
+					Name: "allow-dns", Priority: 1000, Direction: "INGRESS",
+					SourceRanges: []string{"35.235.240.0/20"},
+					Allowed:      []*compute.FirewallAllowed{{IPProtocol: "udp", Ports: []string{"53"}}},
+				},
+				{
+					Name: "deny-disabled", Priority: 10, Direction: "INGRESS", Disabled: true,
+					SourceRanges: []string{"0.0.0.0/0"},
+					Denied:       []*compute.FirewallDenied{{IPProtocol: "all"}},
+				},
+			},
+		},
+		"tags are not compared to ranges": {
+			Firewalls: []*compute.Firewall{
+				{
+					Name: "deny-from-tag", Priority: 100, Direction: "INGRESS",
+					SourceTags: []string{"untrusted"},
+					Denied:     []*compute.FirewallDenied{{IPProtocol: "all"}},
+				},
+				{
+					Name: "allow-internal", Priority: 1000, Direction: "INGRESS",
+					SourceRanges: []string{"10.0.0.0/8"},
+					Allowed:      []*compute.FirewallAllowed{{IPProtocol: "all"}},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var rules []*firewallAnalysisRule
+			for _, fw := range tc.Firewalls {
+				r, err := newFirewallAnalysisRuleFromFirewall(fw)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				rules = append(rules, r)
+			}
+			assertFirewallAnalysisFindings(t, analyzeFirewallRules(rules, false), tc.Expected)
+		})
+	}
+}
+
+func TestAnalyzeFirewallRules_policy(t *testing.T) {
+	cases := map[string]struct {
+		Rules    []*compute.FirewallPolicyRule
+		Expected []FirewallAnalysisFinding
+	}{
+		"duplicate priority": {
+			Rules: []*compute.FirewallPolicyRule{
+				{
+					Priority: 1000, Direction: "INGRESS", Action: "allow",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						SrcIpRanges:   []string{"10.0.0.0/8"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "tcp", Ports: []string{"22"}}},
+					},
+				},
+				{
+					Priority: 1000, Direction: "EGRESS", Action: "deny", RuleName: "block-egress",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						DestIpRanges:  []string{"0.0.0.0/0"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "all"}},
+					},
+				},
+			},
+			Expected: []FirewallAnalysisFinding{{Kind: FirewallAnalysisDuplicatePriority, Rule: "1000", RelatedRule: "1000 (block-egress)"}},
+		},
+		"goto_next does not shadow": {
+			Rules: []*compute.FirewallPolicyRule{
+				{
+					Priority: 100, Direction: "INGRESS", Action: "goto_next",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						SrcIpRanges:   []string{"0.0.0.0/0"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "all"}},
+					},
+				},
+				{
+					Priority: 200, Direction: "INGRESS", Action: "deny",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						SrcIpRanges:   []string{"192.168.0.0/16"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "all"}},
+					},
+				},
+			},
+		},
+		"shadowed by ports and fqdns": {
+			Rules: []*compute.FirewallPolicyRule{
+				{
+					Priority: 100, Direction: "EGRESS", Action: "allow",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						DestFqdns:     []string{"example.com", "example.org"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "tcp", Ports: []string{"80", "443", "8000-8999"}}},
+					},
+				},
+				{
+					Priority: 200, Direction: "EGRESS", Action: "deny",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						DestFqdns:     []string{"example.com"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "tcp", Ports: []string{"443", "8080-8443"}}},
+					},
+				},
+			},
+			Expected: []FirewallAnalysisFinding{{Kind: FirewallAnalysisShadowed, Rule: "200", RelatedRule: "100"}},
+		},
+		"default rules are not shadowed": {
+			Rules: []*compute.FirewallPolicyRule{
+				{
+					Priority: 1000, Direction: "EGRESS", Action: "allow",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						DestIpRanges:  []string{"0.0.0.0/0"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "all"}},
+					},
+				},
+				{
+					Priority: 2147483645, Direction: "EGRESS", Action: "goto_next",
+					Match: &compute.FirewallPolicyRuleMatcher{
+						DestIpRanges:  []string{"0.0.0.0/0"},
+						Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "all"}},
+					},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var rules []*firewallAnalysisRule
+			for _, pr := range tc.Rules {
+				r, err := newFirewallAnalysisRuleFromPolicyRule(pr)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				rules = append(rules, r)
+			}
+			assertFirewallAnalysisFindings(t, analyzeFirewallRules(rules, true), tc.Expected)
+		})
+	}
+}
+
+func TestPortRangesCover(t *testing.T) {
+	if !portRangesCover([][2]int{{80, 80}, {81, 100}, {443, 443}}, [][2]int{{80, 90}, {443, 443}}) {
+		t.Errorf("expected adjacent ranges to be merged")
+	}
+	if portRangesCover([][2]int{{80, 80}, {82, 100}}, [][2]int{{80, 90}}) {
+		t.Errorf("expected a gap between ranges not to be covered")
+	}
+}
+
+func assertFirewallAnalysisFindings(t *testing.T, got, expected []FirewallAnalysisFinding) {
+	t.Helper()
+	for i := range got {
+		if got[i].Message == "" {
+			t.Errorf("expected finding %d to have a message", i)
+		}
+		got[i].Message = ""
+	}
+	if len(got) == 0 && len(expected) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected findings %+v, got %+v", expected, got)
+	}
+}
+
+func TestCachedFirewallAnalysisRules(t *testing.T) {
+	config := &transport_tpg.Config{}
+	key := firewallAnalysisCacheKey{config: config, parent: "p/default"}
+	var loads int
+	load := func() ([]*firewallAnalysisRule, error) {
+		loads++
+		return []*firewallAnalysisRule{{Name: "a", Priority: 1000}, {Name: "b", Priority: 1001}}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		rules, err := cachedFirewallAnalysisRules(key, load)
+		if err != nil || len(rules) != 2 {
+			t.Fatalf("unexpected rules %v, %v", rules, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected the rules to be listed once, got %d", loads)
+	}
+
+	// Another config starts a new run.
+	if _, err := cachedFirewallAnalysisRules(firewallAnalysisCacheKey{config: &transport_tpg.Config{}, parent: "p/default"}, load); err != nil || loads != 2 {
+		t.Errorf("expected the rules to be listed again for another config, got %d loads, %v", loads, err)
+	}
+
+	rules, _ := cachedFirewallAnalysisRules(key, load)
+	others := otherFirewallAnalysisRules(rules, func(r *firewallAnalysisRule) bool { return r.Name == "a" })
+	if len(others) != 1 || others[0].Name != "b" {
+		t.Errorf("expected only rule b, got %v", others)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+
+	compute "google.golang.org/api/compute/v0.beta"
+)
+
+// igmRolloutGating configures how a version change of a managed instance
+// group is watched, from the rollout_gating block.
+type igmRolloutGating struct {
+	UnhealthyThreshold float64
+	Window             time.Duration
+	Rollback           bool
+}
+
+func expandIGMRolloutGating(configured []interface{}) (*igmRolloutGating, error) {
+	if len(configured) == 0 || configured[0] == nil {
+		return nil, nil
+	}
+	data := configured[0].(map[string]interface{})
+	window, err := time.ParseDuration(data["window"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rollout_gating.window: %s", err)
+	}
+	return &igmRolloutGating{
+		UnhealthyThreshold: data["unhealthy_threshold"].(float64),
+		Window:             window,
+		Rollback:           data["rollback"].(bool),
+	}, nil
+}
+
+// igmRolloutTracker remembers which instances reported an unhealthy state
+// recently, so the unhealthy fraction covers the whole window rather than a
+// single poll.
+type igmRolloutTracker struct {
+	gating *igmRolloutGating
+	// newTemplates are the instance templates of the versions rolled out.
+	newTemplates  []string
+	lastUnhealthy map[string]time.Time
+}
+
+type igmRolloutProgress struct {
+	Total     int
+	Updated   int
+	Unhealthy int
+	// Actions counts the instances by their current action, other than NONE.
+	Actions map[string]int
+}
+
+func (p igmRolloutProgress) UnhealthyFraction() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(p.Unhealthy) / float64(p.Total)
+}
+
+func (p igmRolloutProgress) String() string {
+	var actions []string
+	for action, n := range p.Actions {
+		actions = append(actions, fmt.Sprintf("%d %s", n, strings.ToLower(action)))
+	}
+	sort.Strings(actions)
+	if len(actions) == 0 {
+		actions = append(actions, "no pending actions")
+	}
+	return fmt.Sprintf("%d/%d instances on the new version (%s), %d unhealthy", p.Updated, p.Total, strings.Join(actions, ", "), p.Unhealthy)
+}
+
+func newIGMRolloutTracker(gating *igmRolloutGating, versions []*compute.InstanceGroupManagerVersion) *igmRolloutTracker {
+	t := &igmRolloutTracker{
+		gating:        gating,
+		lastUnhealthy: make(map[string]time.Time),
+	}
+	for _, v := range versions {
+		t.newTemplates = append(t.newTemplates, v.InstanceTemplate)
+	}
+	return t
+}
+
+// observe records the state of the instances at the given time and
+// summarizes the rollout. An instance counts as unhealthy if any of its
+// health checks reported it unhealthy or timing out within the window.
+func (t *igmRolloutTracker) observe(now time.Time, instances []*compute.ManagedInstance) igmRolloutProgress {
+	p := igmRolloutProgress{
+		Total:   len(instances),
+		Actions: make(map[string]int),
+	}
+	for _, i := range instances {
+		if i.CurrentAction != "" && i.CurrentAction != "NONE" {
+			p.Actions[i.CurrentAction]++
+		}
+		if i.Version != nil && t.isNewTemplate(i.Version.InstanceTemplate) {
+			p.Updated++
+		}
+		for _, h := range i.InstanceHealth {
+			if h.DetailedHealthState == "UNHEALTHY" || h.DetailedHealthState == "TIMEOUT" {
+				t.lastUnhealthy[i.Instance] = now
+				break
+			}
+		}
+	}
+
+	for instance, seen := range t.lastUnhealthy {
+		if now.Sub(seen) > t.gating.Window {
+			delete(t.lastUnhealthy, instance)
+		}
+	}
+	p.Unhealthy = len(t.lastUnhealthy)
+	if p.Unhealthy > p.Total {
+		// Instances deleted during the window still count as unhealthy.
+		p.Total = p.Unhealthy
+	}
+	return p
+}
+
+func (t *igmRolloutTracker) isNewTemplate(template string) bool {
+	for _, n := range t.newTemplates {
+		if tpgresource.CompareSelfLinkRelativePaths("", n, ConvertToUniqueIdWhenPresent(template), nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// exceeded reports whether the rollout should be aborted.
+func (t *igmRolloutTracker) exceeded(p igmRolloutProgress) bool {
+	return p.Unhealthy > 0 && p.UnhealthyFraction() > t.gating.UnhealthyThreshold
+}
+
+type igmRolloutAbortedError struct {
+	progress igmRolloutProgress
+	gating   *igmRolloutGating
+}
+
+func (e *igmRolloutAbortedError) Error() string {
+	return fmt.Sprintf("%d of %d instances (%.0f%%) were unhealthy within %s, exceeding the threshold of %.0f%%",
+		e.progress.Unhealthy, e.progress.Total, 100*e.progress.UnhealthyFraction(), e.gating.Window, 100*e.gating.UnhealthyThreshold)
+}
+
+// computeIGMWaitForRollout waits for a version change to reach all instances
+// of the group while tracking their health. If too many instances become
+// unhealthy the wait is aborted and, if configured, the previous versions
+// are restored.
+func computeIGMWaitForRollout(d *schema.ResourceData, meta interface{}, gating *igmRolloutGating, previous []*compute.InstanceGroupManagerVersion) error {
+	config := meta.(*transport_tpg.Config)
+	userAgent, err := tpgresource.GenerateUserAgentString(d, config.UserAgent)
+	if err != nil {
+		return err
+	}
+	project, err := tpgresource.GetProject(d, config)
+	if err != nil {
+		return err
+	}
+	zone, err := tpgresource.GetZone(d, config)
+	if err != nil {
+		return err
+	}
+	name := d.Get("name").(string)
+
+	tracker := newIGMRolloutTracker(gating, expandVersions(d.Get("version").([]interface{})))
+	conf := resource.StateChangeConf{
+		Pending: []string{"rolling out"},
+		Target:  []string{"rolled out"},
+		Refresh: func() (interface{}, string, error) {
+			m, err := getManager(d, meta)
+			if err != nil {
+				return nil, "", err
+			}
+			if m == nil {
+				return nil, "", fmt.Errorf("instance manager not found")
+			}
+
+			var instances []*compute.ManagedInstance
+			err = config.NewComputeClient(userAgent).InstanceGroupManagers.ListManagedInstances(project, zone, name).Pages(context.Background(), func(page *compute.InstanceGroupManagersListManagedInstancesResponse) error {
+				instances = append(instances, page.ManagedInstances...)
+				return nil
+			})
+			if err != nil {
+				return nil, "", fmt.Errorf("Error listing managed instances: %s", err)
+			}
+
+			progress := tracker.observe(time.Now(), instances)
+			log.Printf("[INFO] Rolling out Instance Group Manager %q: %s", name, progress)
+			if tracker.exceeded(progress) {
+				return nil, "", &igmRolloutAbortedError{progress: progress, gating: gating}
+			}
+			if m.Status != nil && m.Status.IsStable && m.Status.VersionTarget != nil && m.Status.VersionTarget.IsReached {
+				return m, "rolled out", nil
+			}
+			return m, "rolling out", nil
+		},
+		Timeout:      d.Timeout(schema.TimeoutUpdate),
+		PollInterval: config.PollInterval,
+	}
+	_, err = conf.WaitForState()
+
+	var aborted *igmRolloutAbortedError
+	if !errors.As(err, &aborted) {
+		return err
+	}
+	// Keep the previous versions in state, so the next plan proposes the
+	// rollout again unless it is rolled back and read below.
+	if err := d.Set("version", flattenVersions(previous)); err != nil {
+		return err
+	}
+	if !gating.Rollback || len(previous) == 0 {
+		return fmt.Errorf("Aborted the rollout of Instance Group Manager %q: %s", name, aborted)
+	}
+
+	log.Printf("[WARN] Rolling back Instance Group Manager %q to its previous versions: %s", name, aborted)
+	// The group changed since it was read, so the fingerprint of the
+	// rollback must be current.
+	m, err := getManager(d, meta)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return fmt.Errorf("instance manager not found")
+	}
+	op, err := config.NewComputeClient(userAgent).InstanceGroupManagers.Patch(project, zone, name, &compute.InstanceGroupManager{
+		Fingerprint: m.Fingerprint,
+		Versions:    previous,
+	}).Do()
+	if err != nil {
+		return fmt.Errorf("Aborted the rollout of Instance Group Manager %q (%s), but rolling back failed: %s", name, aborted, err)
+	}
+	err = ComputeOperationWaitTime(config, op, project, "Rolling back InstanceGroupManager", userAgent, d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return fmt.Errorf("Aborted the rollout of Instance Group Manager %q (%s), but rolling back failed: %s", name, aborted, err)
+	}
+
+	if err := resourceComputeInstanceGroupManagerRead(d, meta); err != nil {
+		return err
+	}
+	return fmt.Errorf("Aborted the rollout of Instance Group Manager %q and rolled back to the previous versions: %s", name, aborted)
+}
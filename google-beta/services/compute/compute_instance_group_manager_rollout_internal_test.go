@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package compute
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+
+	compute "google.golang.org/api/compute/v0.beta"
+)
+
+func testManagedInstance(name, template, action, health string) *compute.ManagedInstance {
+	i := &compute.ManagedInstance{
+		Instance:      "https://www.googleapis.com/compute/beta/projects/p/zones/us-central1-a/instances/" + name,
+		CurrentAction: action,
+		Version: &compute.ManagedInstanceVersion{
+			InstanceTemplate: "https://www.googleapis.com/compute/beta/projects/p/global/instanceTemplates/" + template,
+		},
+	}
+	if health != "" {
+		i.InstanceHealth = []*compute.ManagedInstanceInstanceHealth{{DetailedHealthState: health}}
+	}
+	return i
+}
+
+func TestIGMRolloutTracker(t *testing.T) {
+	gating := &igmRolloutGating{UnhealthyThreshold: 0.25, Window: 5 * time.Minute}
+	tracker := newIGMRolloutTracker(gating, []*compute.InstanceGroupManagerVersion{
+		{InstanceTemplate: "projects/p/global/instanceTemplates/v2"},
+	})
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	p := tracker.observe(start, []*compute.ManagedInstance{
+		testManagedInstance("a", "v2", "VERIFYING", "UNHEALTHY"),
+		testManagedInstance("b", "v1", "RECREATING", ""),
+		testManagedInstance("c", "v1", "NONE", "HEALTHY"),
+		testManagedInstance("d", "v1", "NONE", "HEALTHY"),
+	})
+	if p.Total != 4 || p.Updated != 1 || p.Unhealthy != 1 || p.Actions["VERIFYING"] != 1 || p.Actions["RECREATING"] != 1 {
+		t.Fatalf("unexpected progress %+v", p)
+	}
+	if tracker.exceeded(p) {
+		t.Fatalf("expected 1 of 4 unhealthy instances not to exceed the threshold of 25%%")
+	}
+	if got, expected := p.String(), "1/4 instances on the new version (1 recreating, 1 verifying), 1 unhealthy"; got != expected {
+		t.Errorf("expected progress %q, got %q", expected, got)
+	}
+
+	// The first instance recovered, but was unhealthy within the window.
+	p = tracker.observe(start.Add(time.Minute), []*compute.ManagedInstance{
+		testManagedInstance("a", "v2", "NONE", "HEALTHY"),
+		testManagedInstance("b", "v2", "VERIFYING", "TIMEOUT"),
+		testManagedInstance("c", "v1", "NONE", "HEALTHY"),
+		testManagedInstance("d", "v1", "NONE", "HEALTHY"),
+	})
+	if p.Unhealthy != 2 || !tracker.exceeded(p) {
+		t.Fatalf("expected 2 of 4 unhealthy instances to exceed the threshold, got %+v", p)
+	}
+
+	// Once the window passed, only the second instance counts.
+	p = tracker.observe(start.Add(6*time.Minute), []*compute.ManagedInstance{
+		testManagedInstance("a", "v2", "NONE", "HEALTHY"),
+		testManagedInstance("b", "v2", "NONE", "HEALTHY"),
+		testManagedInstance("c", "v2", "NONE", "HEALTHY"),
+		testManagedInstance("d", "v2", "NONE", "HEALTHY"),
+	})
+	if p.Unhealthy != 1 || p.Updated != 4 || tracker.exceeded(p) {
+		t.Fatalf("unexpected progress %+v", p)
+	}
+}
+
+func TestExpandIGMRolloutGating(t *testing.T) {
+	if g, err := expandIGMRolloutGating([]interface{}{}); g != nil || err != nil {
+		t.Fatalf("expected no gating without a block, got %v, %v", g, err)
+	}
+
+	g, err := expandIGMRolloutGating([]interface{}{map[string]interface{}{
+		"unhealthy_threshold": 0.1,
+		"window":              "90s",
+		"rollback":            false,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if g.UnhealthyThreshold != 0.1 || g.Window != 90*time.Second || g.Rollback {
+		t.Errorf("unexpected gating %+v", g)
+	}
+}
+
+func TestComputeIGMWaitForRollout_abortWithoutRollback(t *testing.T) {
+	var patched bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/projects/p/zones/us-central1-a/instanceGroupManagers/igm":
+			json.NewEncoder(w).Encode(&compute.InstanceGroupManager{Name: "igm", Fingerprint: "abc"})
+		case r.Method == "POST" && r.URL.Path == "/projects/p/zones/us-central1-a/instanceGroupManagers/igm/listManagedInstances":
+			json.NewEncoder(w).Encode(&compute.InstanceGroupManagersListManagedInstancesResponse{
+				ManagedInstances: []*compute.ManagedInstance{
+					testManagedInstance("a", "v2", "VERIFYING", "UNHEALTHY"),
+					testManagedInstance("b", "v1", "NONE", "HEALTHY"),
+				},
+			})
+		default:
+			patched = true
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := &transport_tpg.Config{
+		Client:          server.Client(),
+		Context:         context.Background(),
+		ComputeBasePath: server.URL + "/",
+		PollInterval:    time.Millisecond,
+	}
+	d := schema.TestResourceDataRaw(t, ResourceComputeInstanceGroupManager().Schema, map[string]interface{}{
+		"name":               "igm",
+		"project":            "p",
+		"zone":               "us-central1-a",
+		"base_instance_name": "igm",
+		"version": []interface{}{map[string]interface{}{
+			"instance_template": "https://www.googleapis.com/compute/v1/projects/p/global/instanceTemplates/v2",
+		}},
+	})
+	d.SetId("projects/p/zones/us-central1-a/instanceGroupManagers/igm")
+
+	gating := &igmRolloutGating{UnhealthyThreshold: 0.25, Window: time.Minute}
+	err := computeIGMWaitForRollout(d, config, gating, expandVersions([]interface{}{map[string]interface{}{
+		"name":              "",
+		"instance_template": "https://www.googleapis.com/compute/v1/projects/p/global/instanceTemplates/v1",
+		"target_size":       []interface{}{},
+	}}))
+	if err == nil || !strings.Contains(err.Error(), "Aborted the rollout") {
+		t.Fatalf("expected the rollout to be aborted, got %v", err)
+	}
+	if patched {
+		t.Errorf("expected no rollback without rollout_gating.rollback")
+	}
+	// The previous version stays in state, so the next plan shows the
+	// rollout again.
+	if got := d.Get("version.0.instance_template").(string); !strings.HasSuffix(got, "/instanceTemplates/v1") {
+		t.Errorf("expected the previous version in state, got %q", got)
+	}
+}
@@ -86,6 +86,18 @@ func (w *ComputeOperationWaiter) QueryOp() (interface{}, error) {
 	return w.Service.GlobalOperations.Get(w.Project, w.Op.Name).Do()
 }
 
+func (w *ComputeOperationWaiter) Progress() (float64, time.Time, bool) {
+	if w == nil || w.Op == nil {
+		return 0, time.Time{}, false
+	}
+	for _, raw := range []string{w.Op.StartTime, w.Op.InsertTime} {
+		if started, err := time.Parse(time.RFC3339, raw); err == nil {
+			return float64(w.Op.Progress) / 100, started, true
+		}
+	}
+	return 0, time.Time{}, false
+}
+
 func (w *ComputeOperationWaiter) OpName() string {
 	if w == nil || w.Op == nil {
 		return "<nil> Compute Op"
@@ -119,7 +131,10 @@ func ComputeOperationWaitTime(config *transport_tpg.Config, res interface{}, pro
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	if !tpgresource.OperationDone(w) {
+		config.PendingOperations.Track(op.SelfLink)
+	}
+	return tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy("compute"))
 }
 
 func ComputeOrgOperationWaitTimeWithResponse(config *transport_tpg.Config, res interface{}, response *map[string]interface{}, parent, activity, userAgent string, timeout time.Duration) error {
@@ -138,7 +153,7 @@ func ComputeOrgOperationWaitTimeWithResponse(config *transport_tpg.Config, res i
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	if err := tpgresource.OperationWait(w, activity, timeout, config.PollInterval); err != nil {
+	if err := tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy("compute")); err != nil {
 		return err
 	}
 	e, err := json.Marshal(w.Op)
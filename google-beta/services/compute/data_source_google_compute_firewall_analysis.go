@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+)
+
+var firewallAnalysisTargets = []string{"network", "network_firewall_policy", "firewall_policy"}
+
+func DataSourceGoogleComputeFirewallAnalysis() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceGoogleComputeFirewallAnalysisRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"network": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: tpgresource.CompareSelfLinkOrResourceName,
+				ExactlyOneOf:     firewallAnalysisTargets,
+			},
+
+			"network_firewall_policy": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: tpgresource.CompareSelfLinkOrResourceName,
+				ExactlyOneOf:     firewallAnalysisTargets,
+			},
+
+			"firewall_policy": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: tpgresource.CompareSelfLinkOrResourceName,
+				ExactlyOneOf:     firewallAnalysisTargets,
+			},
+
+			"findings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kind": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"related_rule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGoogleComputeFirewallAnalysisRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*transport_tpg.Config)
+	userAgent, err := tpgresource.GenerateUserAgentString(d, config.UserAgent)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var rules []*firewallAnalysisRule
+	var id string
+	policy := true
+	if v, ok := d.GetOk("firewall_policy"); ok {
+		name := tpgresource.GetResourceNameFromSelfLink(v.(string))
+		if rules, err = loadFirewallPolicyAnalysisRules(config, userAgent, "", name); err != nil {
+			return diag.FromErr(transport_tpg.HandleDataSourceNotFoundError(err, d, fmt.Sprintf("Firewall policy %s", name), name))
+		}
+		id = fmt.Sprintf("locations/global/firewallPolicies/%s", name)
+	} else {
+		project, err := tpgresource.GetProject(d, config)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("project", project); err != nil {
+			return diag.Errorf("Error setting project: %s", err)
+		}
+
+		if v, ok := d.GetOk("network_firewall_policy"); ok {
+			name := tpgresource.GetResourceNameFromSelfLink(v.(string))
+			if rules, err = loadFirewallPolicyAnalysisRules(config, userAgent, project, name); err != nil {
+				return diag.FromErr(transport_tpg.HandleDataSourceNotFoundError(err, d, fmt.Sprintf("Network firewall policy %s", name), name))
+			}
+			id = fmt.Sprintf("projects/%s/global/firewallPolicies/%s", project, name)
+		} else {
+			name := tpgresource.GetResourceNameFromSelfLink(d.Get("network").(string))
+			if rules, err = loadFirewallAnalysisRules(ctx, config, userAgent, project, name); err != nil {
+				return diag.Errorf("Error listing firewall rules of network %s: %s", name, err)
+			}
+			id = fmt.Sprintf("projects/%s/global/networks/%s", project, name)
+			policy = false
+		}
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+	findings := analyzeFirewallRules(rules, policy)
+
+	var diags diag.Diagnostics
+	flattened := make([]map[string]interface{}, 0, len(findings))
+	for _, f := range findings {
+		flattened = append(flattened, map[string]interface{}{
+			"kind":         f.Kind,
+			"rule":         f.Rule,
+			"related_rule": f.RelatedRule,
+			"message":      f.Message,
+		})
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Firewall rule analysis of %s", id),
+			Detail:   f.Message,
+		})
+	}
+	if err := d.Set("findings", flattened); err != nil {
+		return diag.Errorf("Error setting findings: %s", err)
+	}
+
+	d.SetId(id)
+	return diags
+}
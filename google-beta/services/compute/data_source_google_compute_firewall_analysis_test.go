@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package compute_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/acctest"
+)
+
+func TestAccDataSourceGoogleComputeFirewallAnalysis(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"random_suffix": acctest.RandString(t, 10),
+	}
+
+	acctest.VcrTest(t, resource.TestCase{
+		PreCheck:                 func() { acctest.AccTestPreCheck(t) },
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceGoogleComputeFirewallAnalysisConfig(context),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.default", "findings.#", "1"),
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.default", "findings.0.kind", "shadowed"),
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.default", "findings.0.rule", fmt.Sprintf("tf-test-allow-ssh-%s", context["random_suffix"])),
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.default", "findings.0.related_rule", fmt.Sprintf("tf-test-allow-tcp-%s", context["random_suffix"])),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceGoogleComputeFirewallAnalysisConfig(context map[string]interface{}) string {
+	return acctest.Nprintf(`
+resource "google_compute_network" "default" {
+  name                    = "tf-test-network-%{random_suffix}"
+  auto_create_subnetworks = false
+}
+
+resource "google_compute_firewall" "allow_tcp" {
+  name          = "tf-test-allow-tcp-%{random_suffix}"
+  network       = google_compute_network.default.name
+  priority      = 100
+  source_ranges = ["10.0.0.0/8"]
+
+  allow {
+    protocol = "tcp"
+  }
+}
+
+resource "google_compute_firewall" "allow_ssh" {
+  name          = "tf-test-allow-ssh-%{random_suffix}"
+  network       = google_compute_network.default.name
+  priority      = 1000
+  source_ranges = ["10.1.0.0/16"]
+
+  allow {
+    protocol = "tcp"
+    ports    = ["22"]
+  }
+}
+
+data "google_compute_firewall_analysis" "default" {
+  network = google_compute_network.default.name
+
+  depends_on = [
+    google_compute_firewall.allow_tcp,
+    google_compute_firewall.allow_ssh,
+  ]
+}
+`, context)
+}
@@ -67,7 +67,7 @@ func testSweepDisk(region string) error {
 
 			id := obj["name"].(string)
 			// Increment count and skip if resource is not sweepable.
-			if !sweeper.IsSweepableTestResource(id) {
+			if !sweeper.IsSweepableTestResourceObject(id, obj) {
 				nonPrefixCount++
 				continue
 			}
@@ -81,6 +81,7 @@ func testSweepDisk(region string) error {
 				RawURL:    deleteUrl,
 				UserAgent: config.UserAgent,
 			})
+			sweeper.RecordSweepResult(id, err)
 			if err != nil {
 				log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 			} else {
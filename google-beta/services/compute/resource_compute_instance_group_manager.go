@@ -16,6 +16,7 @@ import (
 
 	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
 	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
 
 	compute "google.golang.org/api/compute/v0.beta"
 )
@@ -355,6 +356,35 @@ func ResourceComputeInstanceGroupManager() *schema.Resource {
 				ValidateFunc: validation.StringInSlice([]string{"STABLE", "UPDATED"}, false),
 				Description:  `When used with wait_for_instances specifies the status to wait for. When STABLE is specified this resource will wait until the instances are stable before returning. When UPDATED is set, it will wait for the version target to be reached and any per instance configs to be effective and all instances configs to be effective as well as all instances to be stable before returning.`,
 			},
+			"rollout_gating": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: `Watches the health of the instances while a change of version rolls out, waiting until all instances run the new version. The rollout is aborted if too many instances become unhealthy.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"unhealthy_threshold": {
+							Type:         schema.TypeFloat,
+							Required:     true,
+							ValidateFunc: validation.FloatBetween(0, 1),
+							Description:  `The fraction of instances, between 0 and 1, that may be reported unhealthy by the autohealing health check within the window before the rollout is aborted.`,
+						},
+						"window": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "10m",
+							ValidateFunc: verify.ValidateDuration(),
+							Description:  `How long an instance counts as unhealthy after it was last reported unhealthy, such as "10m".`,
+						},
+						"rollback": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: `Whether to restore the previous versions when the rollout is aborted.`,
+						},
+					},
+				},
+			},
 			"stateful_internal_ip": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -876,6 +906,11 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 		change = true
 	}
 
+	rolloutGating, err := expandIGMRolloutGating(d.Get("rollout_gating").([]interface{}))
+	if err != nil {
+		return err
+	}
+
 	if d.HasChange("update_policy") {
 		updatedManager.UpdatePolicy = expandUpdatePolicy(d.Get("update_policy").([]interface{}))
 		change = true
@@ -965,6 +1000,14 @@ func resourceComputeInstanceGroupManagerUpdate(d *schema.ResourceData, meta inte
 
 	d.Partial(false)
 
+	if rolloutGating != nil && d.HasChange("version") {
+		oldVersions, _ := d.GetChange("version")
+		err := computeIGMWaitForRollout(d, meta, rolloutGating, expandVersions(oldVersions.([]interface{})))
+		if err != nil {
+			return err
+		}
+	}
+
 	if d.Get("wait_for_instances").(bool) {
 		err := computeIGMWaitForInstanceStatus(d, meta)
 		if err != nil {
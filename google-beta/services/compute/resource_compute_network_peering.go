@@ -139,7 +139,7 @@ func resourceComputeNetworkPeeringCreate(d *schema.ResourceData, meta interface{
 	// Lock on both networks, sorted so we don't deadlock for A <--> B peering pairs.
 	peeringLockNames := sortedNetworkPeeringMutexKeys(networkFieldValue, peerNetworkFieldValue)
 	for _, kn := range peeringLockNames {
-		transport_tpg.MutexStore.Lock(kn)
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, kn)
 		defer transport_tpg.MutexStore.Unlock(kn)
 	}
 
@@ -237,7 +237,7 @@ func resourceComputeNetworkPeeringUpdate(d *schema.ResourceData, meta interface{
 	// Lock on both networks, sorted so we don't deadlock for A <--> B peering pairs.
 	peeringLockNames := sortedNetworkPeeringMutexKeys(networkFieldValue, peerNetworkFieldValue)
 	for _, kn := range peeringLockNames {
-		transport_tpg.MutexStore.Lock(kn)
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, kn)
 		defer transport_tpg.MutexStore.Unlock(kn)
 	}
 
@@ -280,7 +280,7 @@ func resourceComputeNetworkPeeringDelete(d *schema.ResourceData, meta interface{
 	// Lock on both networks, sorted so we don't deadlock for A <--> B peering pairs.
 	peeringLockNames := sortedNetworkPeeringMutexKeys(networkFieldValue, peerNetworkFieldValue)
 	for _, kn := range peeringLockNames {
-		transport_tpg.MutexStore.Lock(kn)
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, kn)
 		defer transport_tpg.MutexStore.Unlock(kn)
 	}
 
@@ -179,10 +179,57 @@ func resourceComputeProjectMetadataItemDelete(d *schema.ResourceData, meta inter
 	return nil
 }
 
+// projectMetadataItemChange is a change to a single key of a project's common
+// instance metadata.
+type projectMetadataItemChange struct {
+	key           string
+	afterVal      *string
+	failIfPresent metadataPresentBehavior
+}
+
 func updateComputeCommonInstanceMetadata(config *transport_tpg.Config, projectID, key, userAgent string, afterVal *string, timeout time.Duration, failIfPresent metadataPresentBehavior) error {
+	// Changes to different keys of the same project are combined into a single
+	// read-modify-write of the project's metadata if batching is enabled.
+	request := &transport_tpg.BatchRequest{
+		ResourceName: projectID,
+		Body:         []projectMetadataItemChange{{key: key, afterVal: afterVal, failIfPresent: failIfPresent}},
+		CombineF:     combineProjectMetadataItemChanges,
+		SendF:        sendProjectMetadataItemChanges(config, userAgent, timeout),
+		DebugId:      fmt.Sprintf("Project metadata item %q for project %q", key, projectID),
+		Context:      config.RequestContext,
+	}
+
+	batchKey := fmt.Sprintf("projects/%s/commoninstancemetadata", projectID)
+	_, err := config.RequestBatchers.Get(transport_tpg.BatchTypeComputeProjectMetadata).SendRequestWithTimeout(batchKey, request, timeout)
+	return err
+}
+
+func combineProjectMetadataItemChanges(currV interface{}, toAddV interface{}) (interface{}, error) {
+	currChanges, ok := currV.([]projectMetadataItemChange)
+	if !ok {
+		return nil, fmt.Errorf("provider error in batch combiner: expected data to be type []projectMetadataItemChange, got %v with type %T", currV, currV)
+	}
+	newChanges, ok := toAddV.([]projectMetadataItemChange)
+	if !ok {
+		return nil, fmt.Errorf("provider error in batch combiner: expected data to be type []projectMetadataItemChange, got %v with type %T", toAddV, toAddV)
+	}
+	return append(currChanges, newChanges...), nil
+}
+
+func sendProjectMetadataItemChanges(config *transport_tpg.Config, userAgent string, timeout time.Duration) transport_tpg.BatcherSendFunc {
+	return func(projectID string, body interface{}) (interface{}, error) {
+		changes, ok := body.([]projectMetadataItemChange)
+		if !ok {
+			return nil, fmt.Errorf("provider error: expected data to be type []projectMetadataItemChange, got %v with type %T", body, body)
+		}
+		return nil, applyProjectMetadataItemChanges(config, projectID, userAgent, changes, timeout)
+	}
+}
+
+func applyProjectMetadataItemChanges(config *transport_tpg.Config, projectID, userAgent string, changes []projectMetadataItemChange, timeout time.Duration) error {
 	updateMD := func() error {
 		lockName := fmt.Sprintf("projects/%s/commoninstancemetadata", projectID)
-		transport_tpg.MutexStore.Lock(lockName)
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 		defer transport_tpg.MutexStore.Unlock(lockName)
 
 		log.Printf("[DEBUG] Loading project metadata: %s", projectID)
@@ -193,27 +240,35 @@ func updateComputeCommonInstanceMetadata(config *transport_tpg.Config, projectID
 
 		md := FlattenMetadata(project.CommonInstanceMetadata)
 
-		val, ok := md[key]
-
-		if !ok {
-			if afterVal == nil {
-				// Asked to set no value and we didn't find one - we're done
-				return nil
+		changed := false
+		for _, change := range changes {
+			val, ok := md[change.key]
+
+			if !ok {
+				if change.afterVal == nil {
+					// Asked to set no value and we didn't find one - nothing to do
+					continue
+				}
+			} else {
+				if change.failIfPresent {
+					return fmt.Errorf("key %q already present in metadata for project %q. Use `terraform import` to manage it with Terraform", change.key, projectID)
+				}
+				if change.afterVal != nil && *change.afterVal == val {
+					// Asked to set a value and it's already set - nothing to do
+					continue
+				}
 			}
-		} else {
-			if failIfPresent {
-				return fmt.Errorf("key %q already present in metadata for project %q. Use `terraform import` to manage it with Terraform", key, projectID)
-			}
-			if afterVal != nil && *afterVal == val {
-				// Asked to set a value and it's already set - we're done.
-				return nil
+
+			if change.afterVal == nil {
+				delete(md, change.key)
+			} else {
+				md[change.key] = *change.afterVal
 			}
+			changed = true
 		}
 
-		if afterVal == nil {
-			delete(md, key)
-		} else {
-			md[key] = *afterVal
+		if !changed {
+			return nil
 		}
 
 		// Attempt to write the new value now
@@ -140,7 +140,7 @@ func resourceComputeRouterInterfaceCreate(d *schema.ResourceData, meta interface
 	ifaceName := d.Get("name").(string)
 
 	routerLock := tpgresource.GetRouterLockName(region, routerName)
-	transport_tpg.MutexStore.Lock(routerLock)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, routerLock)
 	defer transport_tpg.MutexStore.Unlock(routerLock)
 
 	routersService := config.NewComputeClient(userAgent).Routers
@@ -311,7 +311,7 @@ func resourceComputeRouterInterfaceDelete(d *schema.ResourceData, meta interface
 	ifaceName := d.Get("name").(string)
 
 	routerLock := tpgresource.GetRouterLockName(region, routerName)
-	transport_tpg.MutexStore.Lock(routerLock)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, routerLock)
 	defer transport_tpg.MutexStore.Unlock(routerLock)
 
 	routersService := config.NewComputeClient(userAgent).Routers
@@ -88,7 +88,7 @@ func testSweepComputeRouterBgpPeer(region string) error {
 
 		name := tpgresource.GetResourceNameFromSelfLink(obj["name"].(string))
 		// Skip resources that shouldn't be sweeped
-		if !sweeper.IsSweepableTestResource(name) {
+		if !sweeper.IsSweepableTestResourceObject(name, obj) {
 			nonPrefixCount++
 			continue
 		}
@@ -109,6 +109,7 @@ func testSweepComputeRouterBgpPeer(region string) error {
 			RawURL:    deleteUrl,
 			UserAgent: config.UserAgent,
 		})
+		sweeper.RecordSweepResult(name, err)
 		if err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 		} else {
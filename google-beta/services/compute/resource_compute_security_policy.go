@@ -131,9 +131,10 @@ func ResourceComputeSecurityPolicy() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"expression": {
-													Type:        schema.TypeString,
-													Required:    true,
-													Description: `Textual representation of an expression in Common Expression Language syntax. The application context of the containing message determines which well-known feature set of CEL is supported.`,
+													Type:         schema.TypeString,
+													Required:     true,
+													ValidateFunc: verify.ValidateCELExpression(verify.CloudArmorCELEnvironment),
+													Description:  `Textual representation of an expression in Common Expression Language syntax. The application context of the containing message determines which well-known feature set of CEL is supported.`,
 												},
 												// These fields are not yet supported (Issue hashicorp/terraform-provider-google#4497: mbang)
 												// "title": {
@@ -95,6 +95,39 @@ func (w *ContainerOperationWaiter) QueryOp() (interface{}, error) {
 	return op, err
 }
 
+// Progress reads the operation's progress metrics, which are reported either
+// as "progress" out of "progress scale" or as "nodes done" out of
+// "nodes total".
+func (w *ContainerOperationWaiter) Progress() (float64, time.Time, bool) {
+	if w == nil || w.Op == nil || w.Op.Progress == nil {
+		return 0, time.Time{}, false
+	}
+	started, err := time.Parse(time.RFC3339, w.Op.StartTime)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+
+	metrics := make(map[string]float64)
+	for _, m := range w.Op.Progress.Metrics {
+		if m == nil {
+			continue
+		}
+		if m.IntValue != 0 {
+			metrics[m.Name] = float64(m.IntValue)
+		} else {
+			metrics[m.Name] = m.DoubleValue
+		}
+	}
+	for _, names := range [][2]string{{"progress", "progress scale"}, {"nodes done", "nodes total"}} {
+		done, okDone := metrics[names[0]]
+		total, okTotal := metrics[names[1]]
+		if okDone && okTotal && total > 0 {
+			return done / total, started, true
+		}
+	}
+	return 0, time.Time{}, false
+}
+
 func (w *ContainerOperationWaiter) OpName() string {
 	if w == nil || w.Op == nil {
 		return "<nil>"
@@ -123,6 +156,9 @@ func ContainerOperationWait(config *transport_tpg.Config, op *container.Operatio
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
+	if !tpgresource.OperationDone(w) {
+		config.PendingOperations.Track(op.SelfLink)
+	}
 
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy("container"))
 }
@@ -2420,7 +2420,7 @@ func resourceContainerClusterCreate(d *schema.ResourceData, meta interface{}) er
 		Cluster: cluster,
 	}
 
-	transport_tpg.MutexStore.Lock(containerClusterMutexKey(project, location, clusterName))
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, containerClusterMutexKey(project, location, clusterName))
 	defer transport_tpg.MutexStore.Unlock(containerClusterMutexKey(project, location, clusterName))
 
 	parent := fmt.Sprintf("projects/%s/locations/%s", project, location)
@@ -4250,7 +4250,7 @@ func resourceContainerClusterDelete(d *schema.ResourceData, meta interface{}) er
 	}
 
 	log.Printf("[DEBUG] Deleting GKE cluster %s", d.Get("name").(string))
-	transport_tpg.MutexStore.Lock(containerClusterMutexKey(project, location, clusterName))
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, containerClusterMutexKey(project, location, clusterName))
 	defer transport_tpg.MutexStore.Unlock(containerClusterMutexKey(project, location, clusterName))
 
 	var op *container.Operation
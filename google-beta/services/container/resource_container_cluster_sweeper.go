@@ -38,10 +38,11 @@ func testSweepContainerClusters(region string) error {
 	}
 
 	for _, cluster := range found.Clusters {
-		if sweeper.IsSweepableTestResource(cluster.Name) {
+		if sweeper.IsSweepableTestResourceObject(cluster.Name, sweeper.ResourceObject(cluster)) {
 			log.Printf("Sweeping Container Cluster: %s", cluster.Name)
 			clusterURL := fmt.Sprintf("projects/%s/locations/%s/clusters/%s", config.Project, cluster.Location, cluster.Name)
 			_, err := config.NewContainerClient(config.UserAgent).Projects.Locations.Clusters.Delete(clusterURL).Do()
+			sweeper.RecordSweepResult(cluster.Name, err)
 
 			if err != nil {
 				log.Printf("Error, failed to delete cluster %s: %s", cluster.Name, err)
@@ -566,12 +566,12 @@ func resourceContainerNodePoolCreate(d *schema.ResourceData, meta interface{}) e
 
 	// Acquire read-lock on cluster.
 	clusterLockKey := nodePoolInfo.clusterLockKey()
-	transport_tpg.MutexStore.RLock(clusterLockKey)
+	transport_tpg.MutexStore.RLockInContext(config.RequestContext, clusterLockKey)
 	defer transport_tpg.MutexStore.RUnlock(clusterLockKey)
 
 	// Acquire write-lock on nodepool.
 	npLockKey := nodePoolInfo.nodePoolLockKey(nodePool.Name)
-	transport_tpg.MutexStore.Lock(npLockKey)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, npLockKey)
 	defer transport_tpg.MutexStore.Unlock(npLockKey)
 
 	req := &container.CreateNodePoolRequest{
@@ -794,12 +794,12 @@ func resourceContainerNodePoolDelete(d *schema.ResourceData, meta interface{}) e
 
 	// Acquire read-lock on cluster.
 	clusterLockKey := nodePoolInfo.clusterLockKey()
-	transport_tpg.MutexStore.RLock(clusterLockKey)
+	transport_tpg.MutexStore.RLockInContext(config.RequestContext, clusterLockKey)
 	defer transport_tpg.MutexStore.RUnlock(clusterLockKey)
 
 	// Acquire write-lock on nodepool.
 	npLockKey := nodePoolInfo.nodePoolLockKey(name)
-	transport_tpg.MutexStore.Lock(npLockKey)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, npLockKey)
 	defer transport_tpg.MutexStore.Unlock(npLockKey)
 
 	timeout := d.Timeout(schema.TimeoutDelete)
@@ -1351,7 +1351,7 @@ func nodePoolUpdate(d *schema.ResourceData, meta interface{}, nodePoolInfo *Node
 
 	// Acquire read-lock on cluster.
 	clusterLockKey := nodePoolInfo.clusterLockKey()
-	transport_tpg.MutexStore.RLock(clusterLockKey)
+	transport_tpg.MutexStore.RLockInContext(config.RequestContext, clusterLockKey)
 	defer transport_tpg.MutexStore.RUnlock(clusterLockKey)
 
 	// Nodepool write-lock will be acquired when update function is called.
@@ -154,6 +154,7 @@ func (d *GoogleDnsKeysDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	// Read Provider meta into the meta model
 	resp.Diagnostics.Append(req.ProviderMeta.Get(ctx, &metaData)...)
+	fwtransport.CheckProviderMeta(metaData, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -142,6 +142,7 @@ func (d *GoogleDnsManagedZoneDataSource) Read(ctx context.Context, req datasourc
 
 	// Read Provider meta into the meta model
 	resp.Diagnostics.Append(req.ProviderMeta.Get(ctx, &metaData)...)
+	fwtransport.CheckProviderMeta(metaData, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -162,6 +162,7 @@ func (d *GoogleDnsManagedZonesDataSource) Read(ctx context.Context, req datasour
 
 	// Read Provider meta into the meta model
 	resp.Diagnostics.Append(req.ProviderMeta.Get(ctx, &metaData)...)
+	fwtransport.CheckProviderMeta(metaData, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
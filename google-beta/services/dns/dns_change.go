@@ -3,11 +3,14 @@
 package dns
 
 import (
+	"fmt"
+	"log"
 	"time"
 
 	"google.golang.org/api/dns/v1"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
 )
 
 type DnsChangeWaiter struct {
@@ -42,3 +45,65 @@ func (w *DnsChangeWaiter) Conf() *resource.StateChangeConf {
 		MinTimeout: 2 * time.Second,
 	}
 }
+
+// CreateDnsChange applies chg to a managed zone and waits for it to be done.
+// If batching is enabled for record sets, changes made to the same managed
+// zone at the same time are combined into a single change, and timeout bounds
+// how long the change waits for its batch to be sent and done.
+func CreateDnsChange(config *transport_tpg.Config, userAgent, project, zone string, chg *dns.Change, reqDesc string, timeout time.Duration) error {
+	request := &transport_tpg.BatchRequest{
+		ResourceName: zone,
+		Body:         chg,
+		CombineF:     combineDnsChanges,
+		SendF:        sendDnsChange(config, userAgent, project),
+		DebugId:      reqDesc,
+		Context:      config.RequestContext,
+	}
+
+	batchKey := fmt.Sprintf("projects/%s/managedZones/%s/changes", project, zone)
+	_, err := config.RequestBatchers.Get(transport_tpg.BatchTypeDnsRecordSet).SendRequestWithTimeout(batchKey, request, timeout)
+	return err
+}
+
+func combineDnsChanges(currV interface{}, toAddV interface{}) (interface{}, error) {
+	curr, ok := currV.(*dns.Change)
+	if !ok {
+		return nil, fmt.Errorf("provider error in batch combiner: expected data to be type *dns.Change, got %v with type %T", currV, currV)
+	}
+	toAdd, ok := toAddV.(*dns.Change)
+	if !ok {
+		return nil, fmt.Errorf("provider error in batch combiner: expected data to be type *dns.Change, got %v with type %T", toAddV, toAddV)
+	}
+
+	// Copy the change so the original requests can be retried on their own.
+	combined := &dns.Change{}
+	combined.Additions = append(append(combined.Additions, curr.Additions...), toAdd.Additions...)
+	combined.Deletions = append(append(combined.Deletions, curr.Deletions...), toAdd.Deletions...)
+	return combined, nil
+}
+
+func sendDnsChange(config *transport_tpg.Config, userAgent, project string) transport_tpg.BatcherSendFunc {
+	return func(zone string, body interface{}) (interface{}, error) {
+		chg, ok := body.(*dns.Change)
+		if !ok {
+			return nil, fmt.Errorf("provider error: expected data to be type *dns.Change, got %v with type %T", body, body)
+		}
+
+		log.Printf("[DEBUG] DNS change request for %q: %#v", zone, chg)
+		chg, err := config.NewDnsClient(userAgent).Changes.Create(project, zone, chg).Do()
+		if err != nil {
+			return nil, err
+		}
+
+		w := &DnsChangeWaiter{
+			Service:     config.NewDnsClient(userAgent),
+			Change:      chg,
+			Project:     project,
+			ManagedZone: zone,
+		}
+		if _, err := w.Conf().WaitForState(); err != nil {
+			return nil, fmt.Errorf("Error waiting for Google DNS change: %s", err)
+		}
+		return chg, nil
+	}
+}
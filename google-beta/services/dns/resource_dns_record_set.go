@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"net"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -84,6 +85,12 @@ func ResourceDnsRecordSet() *schema.Resource {
 			State: resourceDnsRecordSetImportState,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
 		CustomizeDiff: customdiff.All(
 			tpgresource.DefaultProviderProject,
 		),
@@ -365,24 +372,13 @@ func resourceDnsRecordSetCreate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	log.Printf("[DEBUG] DNS Record create request: %#v", chg)
-	chg, err = config.NewDnsClient(userAgent).Changes.Create(project, zone, chg).Do()
+	err = CreateDnsChange(config, userAgent, project, zone, chg, fmt.Sprintf("Create DNS RecordSet %s %s in %q", name, rType, zone), d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return fmt.Errorf("Error creating DNS RecordSet: %s", err)
 	}
 
 	d.SetId(fmt.Sprintf("projects/%s/managedZones/%s/rrsets/%s/%s", project, zone, name, rType))
 
-	w := &DnsChangeWaiter{
-		Service:     config.NewDnsClient(userAgent),
-		Change:      chg,
-		Project:     project,
-		ManagedZone: zone,
-	}
-	_, err = w.Conf().WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for Google DNS change: %s", err)
-	}
-
 	return resourceDnsRecordSetRead(d, meta)
 }
 
@@ -498,22 +494,11 @@ func resourceDnsRecordSetDelete(d *schema.ResourceData, meta interface{}) error
 	}
 
 	log.Printf("[DEBUG] DNS Record delete request: %#v", chg)
-	chg, err = config.NewDnsClient(userAgent).Changes.Create(project, zone, chg).Do()
+	err = CreateDnsChange(config, userAgent, project, zone, chg, fmt.Sprintf("Delete DNS RecordSet %s %s in %q", d.Get("name").(string), d.Get("type").(string), zone), d.Timeout(schema.TimeoutDelete))
 	if err != nil {
 		return transport_tpg.HandleNotFoundError(err, d, "google_dns_record_set")
 	}
 
-	w := &DnsChangeWaiter{
-		Service:     config.NewDnsClient(userAgent),
-		Change:      chg,
-		Project:     project,
-		ManagedZone: zone,
-	}
-	_, err = w.Conf().WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for Google DNS change: %s", err)
-	}
-
 	d.SetId("")
 	return nil
 }
@@ -579,21 +564,11 @@ func resourceDnsRecordSetUpdate(d *schema.ResourceData, meta interface{}) error
 		chg.Deletions[0].Rrdatas[i] = oldRR.(string)
 	}
 	log.Printf("[DEBUG] DNS Record change request: %#v old: %#v new: %#v", chg, chg.Deletions[0], chg.Additions[0])
-	chg, err = config.NewDnsClient(userAgent).Changes.Create(project, zone, chg).Do()
+	err = CreateDnsChange(config, userAgent, project, zone, chg, fmt.Sprintf("Update DNS RecordSet %s %s in %q", recordName, newType, zone), d.Timeout(schema.TimeoutUpdate))
 	if err != nil {
 		return fmt.Errorf("Error changing DNS RecordSet: %s", err)
 	}
 
-	w := &DnsChangeWaiter{
-		Service:     config.NewDnsClient(userAgent),
-		Change:      chg,
-		Project:     project,
-		ManagedZone: zone,
-	}
-	if _, err = w.Conf().WaitForState(); err != nil {
-		return fmt.Errorf("Error waiting for Google DNS change: %s", err)
-	}
-
 	d.SetId(fmt.Sprintf("projects/%s/managedZones/%s/rrsets/%s/%s", project, zone, recordName, newType))
 
 	return resourceDnsRecordSetRead(d, meta)
@@ -113,6 +113,7 @@ func (d *GoogleFirebaseAndroidAppConfigDataSource) Read(ctx context.Context, req
 
 	// Read Provider meta into the meta model
 	resp.Diagnostics.Append(req.ProviderMeta.Get(ctx, &metaData)...)
+	fwtransport.CheckProviderMeta(metaData, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
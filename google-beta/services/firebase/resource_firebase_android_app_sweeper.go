@@ -88,7 +88,7 @@ func testSweepFirebaseAndroidApp(region string) error {
 		}
 
 		// Skip resources that shouldn't be sweeped
-		if !sweeper.IsSweepableTestResource(obj["displayName"].(string)) {
+		if !sweeper.IsSweepableTestResourceObject(obj["displayName"].(string), obj) {
 			nonPrefixCount++
 			continue
 		}
@@ -108,6 +108,7 @@ func testSweepFirebaseAndroidApp(region string) error {
 			UserAgent: config.UserAgent,
 			Body:      body,
 		})
+		sweeper.RecordSweepResult(name, err)
 		if err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 		} else {
@@ -67,7 +67,7 @@ func resourceLoggingExclusionCreate(newUpdaterFunc newResourceLoggingExclusionUp
 
 		// Logging exclusions don't seem to be able to be mutated in parallel, see
 		// https://github.com/hashicorp/terraform-provider-google/issues/4796
-		transport_tpg.MutexStore.Lock(id.parent())
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, id.parent())
 		defer transport_tpg.MutexStore.Unlock(id.parent())
 
 		err = updater.CreateLoggingExclusion(id.parent(), exclusion)
@@ -122,7 +122,7 @@ func resourceLoggingExclusionUpdate(newUpdaterFunc newResourceLoggingExclusionUp
 
 		// Logging exclusions don't seem to be able to be mutated in parallel, see
 		// https://github.com/hashicorp/terraform-provider-google/issues/4796
-		transport_tpg.MutexStore.Lock(id.parent())
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, id.parent())
 		defer transport_tpg.MutexStore.Unlock(id.parent())
 
 		err = updater.UpdateLoggingExclusion(d.Id(), exclusion, updateMask)
@@ -145,7 +145,7 @@ func resourceLoggingExclusionDelete(newUpdaterFunc newResourceLoggingExclusionUp
 		id, _ := expandResourceLoggingExclusion(d, updater.GetResourceType(), updater.GetResourceId())
 		// Logging exclusions don't seem to be able to be mutated in parallel, see
 		// https://github.com/hashicorp/terraform-provider-google/issues/4796
-		transport_tpg.MutexStore.Lock(id.parent())
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, id.parent())
 		defer transport_tpg.MutexStore.Unlock(id.parent())
 
 		err = updater.DeleteLoggingExclusion(d.Id())
@@ -103,7 +103,7 @@ func testSweepCertificateAuthority(region string) error {
 			// Increment count and skip if resource is not sweepable.
 			nameParts := strings.Split(caName, "/")
 			id := nameParts[len(nameParts)-1]
-			if !sweeper.IsSweepableTestResource(id) {
+			if !sweeper.IsSweepableTestResourceObject(id, obj) {
 				nonPrefixCount++
 				continue
 			}
@@ -136,6 +136,7 @@ func testSweepCertificateAuthority(region string) error {
 				RawURL:    deleteUrl,
 				UserAgent: config.UserAgent,
 			})
+			sweeper.RecordSweepResult(caName, err)
 			if err != nil {
 				log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 			} else {
@@ -44,6 +44,34 @@ func TestAccPubsubTopic_update(t *testing.T) {
 	})
 }
 
+// Runs against an in-memory fake of the Pub/Sub API, so needs no credentials.
+func TestAccPubsubTopic_fakeGcpServer(t *testing.T) {
+	server := acctest.NewFakeGcpServer(t)
+	server.SetupTestEnvs(t, "fake-project", "us-central1", "us-central1-a")
+
+	topic := fmt.Sprintf("tf-test-topic-%s", acctest.RandString(t, 10))
+
+	acctest.VcrTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories(t),
+		CheckDestroy:             testAccCheckPubsubTopicDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPubsubTopic_update(topic, "foo", "bar"),
+			},
+			{
+				ResourceName:            "google_pubsub_topic.foo",
+				ImportStateId:           topic,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"labels", "terraform_labels"},
+			},
+			{
+				Config: testAccPubsubTopic_update(topic, "wibble", "wobble"),
+			},
+		},
+	})
+}
+
 func TestAccPubsubTopic_cmek(t *testing.T) {
 	t.Parallel()
 
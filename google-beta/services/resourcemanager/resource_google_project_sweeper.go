@@ -60,8 +60,12 @@ func testSweepProject(region string) error {
 		}
 
 		for _, project := range found.Projects {
+			if !sweeper.IsSweepableTestResourceObject(project.ProjectId, sweeper.ResourceObject(project)) {
+				continue
+			}
 			log.Printf("[INFO][SWEEPER_LOG] Sweeping Project id: %s", project.ProjectId)
 			_, err := config.NewResourceManagerClient(config.UserAgent).Projects.Delete(project.ProjectId).Do()
+			sweeper.RecordSweepResult(project.ProjectId, err)
 			if err != nil {
 				log.Printf("[INFO][SWEEPER_LOG] Error, failed to delete project %s: %s", project.Name, err)
 				continue
@@ -64,7 +64,7 @@ func testSweepServiceAccount(region string) error {
 
 		id := tpgresource.GetResourceNameFromSelfLink(obj["name"].(string))
 		// Increment count and skip if resource is not sweepable.
-		if !sweeper.IsSweepableTestResource(id) {
+		if !sweeper.IsSweepableTestResourceObject(id, obj) {
 			nonPrefixCount++
 			continue
 		}
@@ -78,6 +78,7 @@ func testSweepServiceAccount(region string) error {
 			RawURL:    deleteUrl,
 			UserAgent: config.UserAgent,
 		})
+		sweeper.RecordSweepResult(id, err)
 		if err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 		} else {
@@ -44,6 +44,7 @@ func BatchRequestEnableService(service string, project string, d *schema.Resourc
 		CombineF:     combineServiceUsageServicesBatches,
 		SendF:        sendBatchFuncEnableServices(config, userAgent, billingProject, d.Timeout(schema.TimeoutCreate)),
 		DebugId:      fmt.Sprintf("Enable Project Service %q for project %q", service, project),
+		Context:      config.RequestContext,
 	}
 
 	_, err = config.RequestBatcherServiceUsage.SendRequestWithTimeout(
@@ -99,6 +100,7 @@ func BatchRequestReadServices(project string, d *schema.ResourceData, config *tr
 		CombineF: func(body interface{}, toAdd interface{}) (interface{}, error) { return nil, nil },
 		SendF:    sendListServices(config, billingProject, userAgent, d.Timeout(schema.TimeoutRead)),
 		DebugId:  fmt.Sprintf("List Project Services %s", project),
+		Context:  config.RequestContext,
 	}
 
 	return config.RequestBatcherServiceUsage.SendRequestWithTimeout(
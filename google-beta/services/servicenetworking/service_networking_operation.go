@@ -35,5 +35,8 @@ func ServiceNetworkingOperationWaitTime(config *transport_tpg.Config, op *servic
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	if !tpgresource.OperationDone(w) {
+		config.PendingOperations.Track(config.ServiceNetworkingBasePath + op.Name)
+	}
+	return tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy("service_networking"))
 }
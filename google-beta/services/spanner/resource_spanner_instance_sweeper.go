@@ -68,7 +68,7 @@ func testSweepSpannerInstance(region string) error {
 		shortName := name[strings.LastIndex(name, "/")+1:]
 
 		// Increment count and skip if resource is not sweepable.
-		if !sweeper.IsSweepableTestResource(shortName) {
+		if !sweeper.IsSweepableTestResourceObject(shortName, obj) {
 			nonPrefixCount++
 			continue
 		}
@@ -82,6 +82,7 @@ func testSweepSpannerInstance(region string) error {
 			RawURL:    deleteUrl,
 			UserAgent: config.UserAgent,
 		})
+		sweeper.RecordSweepResult(shortName, err)
 		if err != nil {
 			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
 		} else {
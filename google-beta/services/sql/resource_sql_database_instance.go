@@ -1097,7 +1097,7 @@ func resourceSqlDatabaseInstanceCreate(d *schema.ResourceData, meta interface{})
 	// modified at the same time. Lock the master until we're done in order
 	// to prevent that.
 	if !sqlDatabaseIsMaster(d) {
-		transport_tpg.MutexStore.Lock(instanceMutexKey(project, instance.MasterInstanceName))
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, instance.MasterInstanceName))
 		defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, instance.MasterInstanceName))
 	}
 
@@ -1805,7 +1805,7 @@ func resourceSqlDatabaseInstanceUpdate(d *schema.ResourceData, meta interface{})
 			Password: password,
 		}
 
-		transport_tpg.MutexStore.Lock(instanceMutexKey(project, instance))
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, instance))
 		defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, instance))
 		var op *sqladmin.Operation
 		updateFunc := func() error {
@@ -1924,7 +1924,7 @@ func resourceSqlDatabaseInstanceUpdate(d *schema.ResourceData, meta interface{})
 	// Lock on the master_instance_name just in case updating any replica
 	// settings causes operations on the master.
 	if v, ok := d.GetOk("master_instance_name"); ok {
-		transport_tpg.MutexStore.Lock(instanceMutexKey(project, v.(string)))
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, v.(string)))
 		defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, v.(string)))
 	}
 
@@ -1993,7 +1993,7 @@ func resourceSqlDatabaseInstanceDelete(d *schema.ResourceData, meta interface{})
 	// Lock on the master_instance_name just in case deleting a replica causes
 	// operations on the master.
 	if v, ok := d.GetOk("master_instance_name"); ok {
-		transport_tpg.MutexStore.Lock(instanceMutexKey(project, v.(string)))
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, v.(string)))
 		defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, v.(string)))
 	}
 
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-provider-google-beta/google-beta/sweeper"
+
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
 )
 
 func init() {
@@ -39,11 +41,13 @@ func testSweepSQLDatabaseInstance(region string) error {
 	}
 
 	running := map[string]struct{}{}
+	var sweepable []*sqladmin.DatabaseInstance
 
 	for _, d := range found.Items {
-		if !sweeper.IsSweepableTestResource(d.Name) {
+		if !sweeper.IsSweepableTestResourceObject(d.Name, sweeper.ResourceObject(d)) {
 			continue
 		}
+		sweepable = append(sweepable, d)
 
 		if d.State != "RUNNABLE" {
 			continue
@@ -51,11 +55,7 @@ func testSweepSQLDatabaseInstance(region string) error {
 		running[d.Name] = struct{}{}
 	}
 
-	for _, d := range found.Items {
-		if !sweeper.IsSweepableTestResource(d.Name) {
-			continue
-		}
-
+	for _, d := range sweepable {
 		// don't delete replicas, we'll take care of that
 		// when deleting the database they replicate
 		if d.ReplicaConfiguration != nil {
@@ -108,10 +108,12 @@ func testSweepSQLDatabaseInstance(region string) error {
 					// the GCP api can return a 409 error after the delete operation
 					// reaches a successful end
 					log.Printf("Operation not found, got 409 response")
+					sweeper.RecordSweepResult(db, nil)
 					continue
 				}
 
 				log.Printf("Error, failed to delete instance %s: %s", db, err)
+				sweeper.RecordSweepResult(db, err)
 				continue
 			}
 
@@ -119,10 +121,12 @@ func testSweepSQLDatabaseInstance(region string) error {
 			if err != nil {
 				if strings.Contains(err.Error(), "does not exist") {
 					log.Printf("SQL instance not found")
+					sweeper.RecordSweepResult(db, nil)
 					continue
 				}
 				log.Printf("Error, failed to delete instance %s: %s", db, err)
 			}
+			sweeper.RecordSweepResult(db, err)
 		}
 	}
 
@@ -123,7 +123,7 @@ func resourceSqlSslCertCreate(d *schema.ResourceData, meta interface{}) error {
 		CommonName: commonName,
 	}
 
-	transport_tpg.MutexStore.Lock(instanceMutexKey(project, instance))
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, instance))
 	defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, instance))
 	resp, err := config.NewSqlAdminClient(userAgent).SslCerts.Insert(project, instance, sslCertsInsertRequest).Do()
 	if err != nil {
@@ -227,7 +227,7 @@ func resourceSqlSslCertDelete(d *schema.ResourceData, meta interface{}) error {
 	commonName := d.Get("common_name").(string)
 	fingerprint := d.Get("sha1_fingerprint").(string)
 
-	transport_tpg.MutexStore.Lock(instanceMutexKey(project, instance))
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, instance))
 	defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, instance))
 	op, err := config.NewSqlAdminClient(userAgent).SslCerts.Delete(project, instance, fingerprint).Do()
 
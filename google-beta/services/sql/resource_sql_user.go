@@ -261,7 +261,7 @@ func resourceSqlUserCreate(d *schema.ResourceData, meta interface{}) error {
 		user.PasswordPolicy = pp
 	}
 
-	transport_tpg.MutexStore.Lock(instanceMutexKey(project, instance))
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, instance))
 	defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, instance))
 
 	if v, ok := d.GetOk("host"); ok {
@@ -465,7 +465,7 @@ func resourceSqlUserUpdate(d *schema.ResourceData, meta interface{}) error {
 			Password: password,
 		}
 
-		transport_tpg.MutexStore.Lock(instanceMutexKey(project, instance))
+		transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, instance))
 		defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, instance))
 		var op *sqladmin.Operation
 		updateFunc := func() error {
@@ -518,7 +518,7 @@ func resourceSqlUserDelete(d *schema.ResourceData, meta interface{}) error {
 	host := d.Get("host").(string)
 	instance := d.Get("instance").(string)
 
-	transport_tpg.MutexStore.Lock(instanceMutexKey(project, instance))
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, instanceMutexKey(project, instance))
 	defer transport_tpg.MutexStore.Unlock(instanceMutexKey(project, instance))
 
 	var op *sqladmin.Operation
@@ -119,7 +119,10 @@ func SqlAdminOperationWaitTime(config *transport_tpg.Config, res interface{}, pr
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	if !tpgresource.OperationDone(w) {
+		config.PendingOperations.Track(op.SelfLink)
+	}
+	return tpgresource.OperationWaitWithPolicy(w, activity, timeout, config.OperationPollingPolicy("sql"))
 }
 
 // SqlAdminOperationError wraps sqladmin.OperationError and implements the
@@ -139,7 +139,7 @@ func resourceStorageBucketAclCreate(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	if len(predefined_acl) > 0 {
@@ -271,7 +271,7 @@ func resourceStorageBucketAclUpdate(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	if d.HasChange("role_entity") {
@@ -369,7 +369,7 @@ func resourceStorageBucketAclDelete(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	bkt, err := config.NewStorageClient(userAgent).Buckets.Get(bucket).Do()
@@ -129,7 +129,7 @@ func resourceTagsTagValueCreate(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{TagsBasePath}}tagValues")
@@ -270,7 +270,7 @@ func resourceTagsTagValueUpdate(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{TagsBasePath}}tagValues/{{name}}")
@@ -339,7 +339,7 @@ func resourceTagsTagValueDelete(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return err
 	}
-	transport_tpg.MutexStore.Lock(lockName)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, lockName)
 	defer transport_tpg.MutexStore.Unlock(lockName)
 
 	url, err := tpgresource.ReplaceVars(d, config, "{{TagsBasePath}}tagValues/{{name}}")
@@ -4,10 +4,14 @@ package sweeper
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"hash/crc32"
+	"log"
+	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 
@@ -30,10 +34,19 @@ var testResourcePrefixes = []string{
 	"k8s-fw-",             // firewall rules are getting created and not cleaned up by k8 resources using this prefix
 }
 
+// creationTimestampFields are the fields resources report their creation time
+// in, across APIs.
+var creationTimestampFields = []string{
+	"creationTimestamp",
+	"createTime",
+	"timeCreated",
+}
+
 // SharedConfigForRegion returns a common config setup needed for the sweeper
-// functions for a given region
+// functions for a given region. The config targets the project being swept,
+// and records mutating requests instead of sending them in dry run mode.
 func SharedConfigForRegion(region string) (*transport_tpg.Config, error) {
-	project := envvar.GetTestProjectFromEnv()
+	project := currentSweepProject()
 	if project == "" {
 		return nil, fmt.Errorf("set project using any of these env variables %v", envvar.ProjectEnvVars)
 	}
@@ -47,13 +60,50 @@ func SharedConfigForRegion(region string) (*transport_tpg.Config, error) {
 		Region:      region,
 		Project:     project,
 	}
+	if opts, err := GetOptions(); err != nil {
+		return nil, err
+	} else if opts.DryRun {
+		conf.DryRun = &transport_tpg.DryRunConfig{RecordFile: os.DevNull}
+	}
+	conf.WrapTransport = newSweepTransport
 
 	transport_tpg.ConfigureBasePaths(conf)
 
 	return conf, nil
 }
 
+// IsSweepableTestResource reports whether the resource with the given name
+// should be swept. Within a sweeper, the options apply as they do in
+// IsSweepableTestResourceObject, to the resource of that name listed with
+// the sweeper's config, and the result of deleting the resource with the
+// config is recorded in the report.
 func IsSweepableTestResource(resourceName string) bool {
+	sweepLock.Lock()
+	result := currentSweep()
+	var obj map[string]interface{}
+	if result != nil {
+		obj = result.listed[resourceName]
+	}
+	sweepLock.Unlock()
+	if result == nil {
+		return hasTestResourcePrefix(resourceName)
+	}
+
+	if obj == nil {
+		if opts, err := GetOptions(); err == nil && (len(opts.Labels) > 0 || opts.MinAge > 0) {
+			log.Printf("[WARN] No listed resource named %q to check SWEEPER_LABELS and SWEEPER_MIN_AGE against; it is only swept if it has a test resource prefix and SWEEPER_MIN_AGE is unset", resourceName)
+		}
+	}
+	if !IsSweepableTestResourceObject(resourceName, obj) {
+		return false
+	}
+	sweepLock.Lock()
+	result.pending[resourceName] = true
+	sweepLock.Unlock()
+	return true
+}
+
+func hasTestResourcePrefix(resourceName string) bool {
 	for _, p := range testResourcePrefixes {
 		if strings.HasPrefix(resourceName, p) {
 			return true
@@ -62,6 +112,70 @@ func IsSweepableTestResource(resourceName string) bool {
 	return false
 }
 
+// IsSweepableTestResourceObject reports whether the resource with the given
+// name, as returned by a list request, should be swept. It must either have a
+// test resource prefix or match the labels in SWEEPER_LABELS, and must have
+// been created over SWEEPER_MIN_AGE ago. Resources that are not swept are
+// counted as skipped in the report.
+func IsSweepableTestResourceObject(resourceName string, obj map[string]interface{}) bool {
+	opts, err := GetOptions()
+	if err != nil {
+		return false
+	}
+	sweepable := (hasTestResourcePrefix(resourceName) || matchesLabels(obj, opts.Labels)) && isOldEnough(obj, opts.MinAge)
+	if !sweepable {
+		recordSkipped()
+	}
+	return sweepable
+}
+
+// ResourceObject returns the JSON representation of a resource returned by a
+// typed API client, to pass to IsSweepableTestResourceObject.
+func ResourceObject(resource interface{}) map[string]interface{} {
+	obj := make(map[string]interface{})
+	if b, err := json.Marshal(resource); err == nil {
+		json.Unmarshal(b, &obj)
+	}
+	return obj
+}
+
+// matchesLabels reports whether obj has all the labels in selectors. An empty
+// selector value matches any value of the label. No resource matches an empty
+// set of selectors.
+func matchesLabels(obj map[string]interface{}, selectors map[string]string) bool {
+	if len(selectors) == 0 {
+		return false
+	}
+	labels, _ := obj["labels"].(map[string]interface{})
+	for k, want := range selectors {
+		v, ok := labels[k]
+		if !ok || (want != "" && v != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// isOldEnough reports whether obj was created at least minAge ago. Resources
+// without a creation timestamp are never old enough, unless minAge is zero.
+func isOldEnough(obj map[string]interface{}, minAge time.Duration) bool {
+	if minAge <= 0 {
+		return true
+	}
+	for _, field := range creationTimestampFields {
+		if v, ok := obj[field].(string); ok {
+			created, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return false
+			}
+			return time.Since(created) >= minAge
+		}
+	}
+	return false
+}
+
+// AddTestSweepers registers a sweeper, which is run once for each project in
+// SWEEPER_PROJECTS and recorded in the sweep report.
 func AddTestSweepers(name string, sweeper func(region string) error) {
 	_, filename, _, _ := runtime.Caller(0)
 	hash := crc32.NewIEEE()
@@ -71,6 +185,8 @@ func AddTestSweepers(name string, sweeper func(region string) error) {
 
 	resource.AddTestSweepers(uniqueName, &resource.Sweeper{
 		Name: name,
-		F:    sweeper,
+		F: func(region string) error {
+			return runSweeper(name, region, sweeper)
+		},
 	})
 }
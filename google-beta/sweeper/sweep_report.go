@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package sweeper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/envvar"
+)
+
+// Options control what sweepers delete, and where. They are read from the
+// environment:
+//
+//   - SWEEPER_DRY_RUN: if true, delete requests are recorded in the report
+//     instead of being sent.
+//   - SWEEPER_MIN_AGE: only resources created at least this long ago, such as
+//     "3h", are swept.
+//   - SWEEPER_LABELS: resources with all of these comma separated labels are
+//     swept even without a test resource prefix. A label is given as
+//     "key=value", or as "key" to match any value, such as
+//     "goog-terraform-provisioned=true".
+//   - SWEEPER_PROJECTS: the comma separated projects to sweep. Defaults to the
+//     test project.
+//   - SWEEPER_REPORT: the path the JSON report of each sweeper run is written
+//     to.
+type Options struct {
+	DryRun     bool
+	MinAge     time.Duration
+	Labels     map[string]string
+	Projects   []string
+	ReportFile string
+}
+
+// GetOptions returns the sweeper options set in the environment.
+func GetOptions() (*Options, error) {
+	opts := &Options{
+		Labels:     make(map[string]string),
+		ReportFile: os.Getenv("SWEEPER_REPORT"),
+	}
+
+	if v := os.Getenv("SWEEPER_DRY_RUN"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SWEEPER_DRY_RUN %q: %w", v, err)
+		}
+		opts.DryRun = dryRun
+	}
+
+	if v := os.Getenv("SWEEPER_MIN_AGE"); v != "" {
+		minAge, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SWEEPER_MIN_AGE %q: %w", v, err)
+		}
+		opts.MinAge = minAge
+	}
+
+	for _, label := range splitList(os.Getenv("SWEEPER_LABELS")) {
+		k, v, _ := strings.Cut(label, "=")
+		opts.Labels[k] = v
+	}
+
+	opts.Projects = splitList(os.Getenv("SWEEPER_PROJECTS"))
+	if len(opts.Projects) == 0 {
+		if project := envvar.GetTestProjectFromEnv(); project != "" {
+			opts.Projects = []string{project}
+		}
+	}
+	return opts, nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SweepResult is the report of a sweeper run in a project and region.
+type SweepResult struct {
+	Sweeper string `json:"sweeper"`
+	Project string `json:"project"`
+	Region  string `json:"region"`
+	DryRun  bool   `json:"dry_run"`
+	// Swept are the resources deleted, or that would be in dry run mode.
+	Swept []string `json:"swept"`
+	// Failed are the resources that could not be deleted.
+	Failed []FailedSweep `json:"failed"`
+	// Skipped is the number of resources listed that were not sweepable.
+	Skipped int `json:"skipped"`
+	// Error is the error the sweeper returned, if any.
+	Error string `json:"error,omitempty"`
+
+	// listed are the resources listed by the sweeper, keyed by name.
+	listed map[string]map[string]interface{}
+	// pending are the names of resources IsSweepableTestResource found
+	// sweepable, whose deletion is yet to be recorded.
+	pending map[string]bool
+}
+
+// FailedSweep is a resource that could not be deleted.
+type FailedSweep struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+var (
+	// sweepLock guards the sweep results.
+	sweepLock    sync.Mutex
+	sweepResults []*SweepResult
+	// runningSweeps are the results of the sweepers running, innermost last.
+	// Sweepers are run one at a time, but may run others.
+	runningSweeps []*SweepResult
+)
+
+// runSweeper runs sweeper in region once for each project to sweep, and
+// writes its results to the report.
+func runSweeper(name, region string, sweeper func(region string) error) error {
+	opts, err := GetOptions()
+	if err != nil {
+		return err
+	}
+	if len(opts.Projects) == 0 {
+		return fmt.Errorf("set project using SWEEPER_PROJECTS or any of these env variables %v", envvar.ProjectEnvVars)
+	}
+
+	var errs []error
+	for _, project := range opts.Projects {
+		result := &SweepResult{
+			Sweeper: name,
+			Project: project,
+			Region:  region,
+			DryRun:  opts.DryRun,
+			Swept:   []string{},
+			Failed:  []FailedSweep{},
+			listed:  make(map[string]map[string]interface{}),
+			pending: make(map[string]bool),
+		}
+		sweepLock.Lock()
+		sweepResults = append(sweepResults, result)
+		runningSweeps = append(runningSweeps, result)
+		sweepLock.Unlock()
+
+		err := sweeper(region)
+
+		sweepLock.Lock()
+		runningSweeps = runningSweeps[:len(runningSweeps)-1]
+		if err != nil {
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("sweeping project %s: %w", project, err))
+		}
+		reportErr := writeReport(opts.ReportFile)
+		sweepLock.Unlock()
+		if reportErr != nil {
+			log.Printf("[INFO][SWEEPER_LOG] error writing sweeper report: %s", reportErr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// currentSweep returns the result of the running sweeper, or nil if no
+// sweeper is running. sweepLock must be held.
+func currentSweep() *SweepResult {
+	if len(runningSweeps) == 0 {
+		return nil
+	}
+	return runningSweeps[len(runningSweeps)-1]
+}
+
+// currentSweepProject returns the project being swept, or the test project
+// if no sweeper is running.
+func currentSweepProject() string {
+	sweepLock.Lock()
+	defer sweepLock.Unlock()
+	if result := currentSweep(); result != nil {
+		return result.Project
+	}
+	return envvar.GetTestProjectFromEnv()
+}
+
+// RecordSweepResult records the result of deleting the named resource, where
+// err is the error of the delete request.
+func RecordSweepResult(name string, err error) {
+	sweepLock.Lock()
+	defer sweepLock.Unlock()
+	if result := currentSweep(); result != nil {
+		result.record(name, err)
+	}
+}
+
+// record records the result of deleting the named resource. sweepLock must
+// be held.
+func (result *SweepResult) record(name string, err error) {
+	if err != nil {
+		result.Failed = append(result.Failed, FailedSweep{Name: name, Error: err.Error()})
+		return
+	}
+	if result.DryRun {
+		log.Printf("[INFO][SWEEPER_LOG] Dry run: would delete %s resource %s in project %s", result.Sweeper, name, result.Project)
+	}
+	result.Swept = append(result.Swept, name)
+}
+
+func recordSkipped() {
+	sweepLock.Lock()
+	defer sweepLock.Unlock()
+	if result := currentSweep(); result != nil {
+		result.Skipped++
+	}
+}
+
+// writeReport writes the results of every sweeper run so far to path, if it
+// is set. sweepLock must be held.
+func writeReport(path string) error {
+	if path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(sweepResults, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
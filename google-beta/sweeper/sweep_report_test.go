@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package sweeper
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsSweepableTestResourceObject(t *testing.T) {
+	t.Setenv("SWEEPER_MIN_AGE", "1h")
+	t.Setenv("SWEEPER_LABELS", "goog-terraform-provisioned=true,team")
+
+	old := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().Format(time.RFC3339)
+	cases := map[string]struct {
+		name string
+		obj  map[string]interface{}
+		want bool
+	}{
+		"old test resource": {
+			name: "tf-test-abc",
+			obj:  map[string]interface{}{"creationTimestamp": old},
+			want: true,
+		},
+		"recent test resource": {
+			name: "tf-test-abc",
+			obj:  map[string]interface{}{"createTime": recent},
+			want: false,
+		},
+		"test resource without a creation time": {
+			name: "tf-test-abc",
+			obj:  map[string]interface{}{},
+			want: false,
+		},
+		"old labelled resource": {
+			name: "other",
+			obj: map[string]interface{}{
+				"timeCreated": old,
+				"labels":      map[string]interface{}{"goog-terraform-provisioned": "true", "team": "a"},
+			},
+			want: true,
+		},
+		"old resource missing a label": {
+			name: "other",
+			obj: map[string]interface{}{
+				"timeCreated": old,
+				"labels":      map[string]interface{}{"goog-terraform-provisioned": "true"},
+			},
+			want: false,
+		},
+		"old resource with a different label value": {
+			name: "other",
+			obj: map[string]interface{}{
+				"timeCreated": old,
+				"labels":      map[string]interface{}{"goog-terraform-provisioned": "false", "team": "a"},
+			},
+			want: false,
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if got := IsSweepableTestResourceObject(tc.name, tc.obj); got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRunSweeper_Report(t *testing.T) {
+	report := filepath.Join(t.TempDir(), "report.json")
+	t.Setenv("SWEEPER_PROJECTS", "project-a, project-b")
+	t.Setenv("SWEEPER_DRY_RUN", "true")
+	t.Setenv("SWEEPER_REPORT", report)
+	t.Setenv("GOOGLE_CREDENTIALS", "{}")
+	t.Cleanup(func() { sweepResults = nil })
+
+	var projects []string
+	err := runSweeper("Thing", "us-central1", func(region string) error {
+		conf, err := SharedConfigForRegion(region)
+		if conf == nil {
+			t.Fatalf("unexpected error getting the shared config: %v", err)
+		}
+		if conf.DryRun == nil {
+			t.Errorf("expected the shared config to be in dry run mode")
+		}
+		projects = append(projects, conf.Project)
+
+		IsSweepableTestResourceObject("other", map[string]interface{}{})
+		RecordSweepResult("tf-test-a", nil)
+		if conf.Project == "project-b" {
+			RecordSweepResult("tf-test-b", errors.New("boom"))
+			return errors.New("list failed")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expected the sweeper's error to be returned")
+	}
+	if !reflect.DeepEqual(projects, []string{"project-a", "project-b"}) {
+		t.Fatalf("expected each project to be swept, got %v", projects)
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*SweepResult
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []*SweepResult{
+		{Sweeper: "Thing", Project: "project-a", Region: "us-central1", DryRun: true, Swept: []string{"tf-test-a"}, Failed: []FailedSweep{}, Skipped: 1},
+		{Sweeper: "Thing", Project: "project-b", Region: "us-central1", DryRun: true, Swept: []string{"tf-test-a"}, Failed: []FailedSweep{{Name: "tf-test-b", Error: "boom"}}, Skipped: 1, Error: "list failed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected report:\n%s", b)
+	}
+}
+
+func TestIsSweepableTestResource_listedWithSharedConfig(t *testing.T) {
+	t.Setenv("SWEEPER_PROJECTS", "project-a")
+	t.Setenv("SWEEPER_MIN_AGE", "1h")
+	t.Setenv("GOOGLE_CREDENTIALS", "{}")
+	t.Cleanup(func() { sweepResults = nil })
+
+	old := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"things": []interface{}{
+					map[string]interface{}{"name": "projects/project-a/things/tf-test-old", "createTime": old},
+					map[string]interface{}{"name": "projects/project-a/things/tf-test-new", "createTime": recent},
+					map[string]interface{}{"name": "projects/project-a/things/tf-test-gone", "createTime": old},
+					map[string]interface{}{"name": "projects/project-a/things/other", "createTime": old},
+				},
+			})
+		case r.URL.Path == "/things/tf-test-gone":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	err := runSweeper("Thing", "us-central1", func(region string) error {
+		conf, err := SharedConfigForRegion(region)
+		if err != nil {
+			return err
+		}
+		client := &http.Client{Transport: conf.WrapTransport(http.DefaultTransport)}
+
+		res, err := client.Get(server.URL + "/things")
+		if err != nil {
+			return err
+		}
+		var list struct {
+			Things []struct{ Name string }
+		}
+		if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+			return err
+		}
+		res.Body.Close()
+
+		for _, thing := range list.Things {
+			name := path.Base(thing.Name)
+			if !IsSweepableTestResource(name) {
+				continue
+			}
+			req, _ := http.NewRequest(http.MethodDelete, server.URL+"/things/"+name, nil)
+			res, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			res.Body.Close()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []*SweepResult{{
+		Sweeper: "Thing",
+		Project: "project-a",
+		Region:  "us-central1",
+		Swept:   []string{"tf-test-old"},
+		Failed:  []FailedSweep{{Name: "tf-test-gone", Error: "DELETE /things/tf-test-gone: 404 Not Found"}},
+		Skipped: 2,
+	}}
+	for _, r := range sweepResults {
+		r.listed, r.pending = nil, nil
+	}
+	if !reflect.DeepEqual(sweepResults, want) {
+		t.Fatalf("expected results %+v, got %+v", want[0], sweepResults[0])
+	}
+}
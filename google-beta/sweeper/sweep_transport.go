@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package sweeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+)
+
+// sweepTransport observes the requests of sweepers that only pass resource
+// names to IsSweepableTestResource. It remembers the resources listed, so
+// the options can apply to them, and records the result of deleting the
+// resources found sweepable.
+type sweepTransport struct {
+	base http.RoundTripper
+}
+
+func newSweepTransport(base http.RoundTripper) http.RoundTripper {
+	return &sweepTransport{base: base}
+}
+
+func (t *sweepTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	switch req.Method {
+	case http.MethodGet:
+		if err == nil && resp.StatusCode == http.StatusOK {
+			resp.Body = recordListed(resp.Body)
+		}
+	case http.MethodDelete:
+		if err == nil && resp.StatusCode >= http.StatusBadRequest {
+			recordDeleted(path.Base(req.URL.Path), fmt.Errorf("DELETE %s: %s", req.URL.Path, resp.Status))
+		} else {
+			recordDeleted(path.Base(req.URL.Path), err)
+		}
+	}
+	return resp, err
+}
+
+// recordListed remembers the resources in a list response, keyed by the
+// last segment of their name, and returns a reader for the body read.
+func recordListed(body io.ReadCloser) io.ReadCloser {
+	b, err := io.ReadAll(body)
+	body.Close()
+	restored := io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return io.NopCloser(io.MultiReader(bytes.NewReader(b), errReader{err}))
+	}
+
+	var res map[string]interface{}
+	if json.Unmarshal(b, &res) != nil {
+		return restored
+	}
+
+	sweepLock.Lock()
+	defer sweepLock.Unlock()
+	result := currentSweep()
+	if result == nil {
+		return restored
+	}
+	for _, v := range res {
+		items, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, ok := obj["name"].(string); ok {
+				result.listed[path.Base(name)] = obj
+			}
+		}
+	}
+	return restored
+}
+
+// recordDeleted records the result of deleting the named resource, if
+// IsSweepableTestResource found it sweepable.
+func recordDeleted(name string, err error) {
+	sweepLock.Lock()
+	defer sweepLock.Unlock()
+	result := currentSweep()
+	if result == nil || !result.pending[name] {
+		return
+	}
+	delete(result.pending, name)
+	result.record(name, err)
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -82,22 +82,108 @@ func iamPolicyReadWithRetry(updater ResourceIamUpdater) (*cloudresourcemanager.P
 	return policy, nil
 }
 
+// iamDefaultRetryPolicy describes the backoff used by iamPolicyReadModifyWrite
+// when the provider has no `retry_policy` block: waits start at one second and
+// double, and the loop gives up once it would wait longer than 31 seconds in
+// total. Conflicts are retried after 1, 2, 4, 8 and 16 seconds, and waiting
+// for propagation stops after as many waits.
+var iamDefaultRetryPolicy = &transport_tpg.RetryPolicy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     maxBackoffSeconds * time.Second,
+	Multiplier:     2,
+	Timeout:        31 * time.Second,
+}
+
+// iamDefaultReadRetryPolicy describes the backoff used when reading a policy
+// fails with a 429 and the provider has no `retry_policy` block: reads are
+// retried every second until they succeed.
+var iamDefaultReadRetryPolicy = &transport_tpg.RetryPolicy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+}
+
+func iamRetryPolicy(config *transport_tpg.Config) *transport_tpg.RetryPolicy {
+	if config == nil || config.RetryPolicy == nil {
+		return iamDefaultRetryPolicy
+	}
+	return config.RetryPolicy
+}
+
+func iamReadRetryPolicy(config *transport_tpg.Config) *transport_tpg.RetryPolicy {
+	if config == nil || config.RetryPolicy == nil {
+		return iamDefaultReadRetryPolicy
+	}
+	return config.RetryPolicy
+}
+
+// iamBackoff yields the waits of one retry loop of iamPolicyReadModifyWrite.
+// As in the retry transport, MaxBackoff caps each wait, while MaxAttempts and
+// Timeout bound the loop as a whole.
+type iamBackoff struct {
+	backoff *transport_tpg.RetryBackoff
+	timeout time.Duration
+	waited  time.Duration
+	waits   int
+}
+
+func newIamBackoff(policy *transport_tpg.RetryPolicy) *iamBackoff {
+	return &iamBackoff{
+		backoff: policy.NewBackoff(),
+		timeout: policy.Timeout,
+	}
+}
+
+// Next returns the wait before the next attempt. ok is false once the policy's
+// MaxAttempts is reached or the loop would wait longer than its Timeout.
+func (b *iamBackoff) Next() (wait time.Duration, ok bool) {
+	wait, ok = b.backoff.Next()
+	if !ok || (b.timeout > 0 && b.waited+wait > b.timeout) {
+		return 0, false
+	}
+	b.waited += wait
+	b.waits++
+	return wait, true
+}
+
+// Attempts returns the number of attempts the loop made, one more than the
+// number of waits Next returned.
+func (b *iamBackoff) Attempts() int {
+	return b.waits + 1
+}
+
+// iamShouldRetryRead reports whether reading a policy is retried after err.
+// Reads are retried on 429s, unless a status_code_override says otherwise.
+func iamShouldRetryRead(policy *transport_tpg.RetryPolicy, err error) bool {
+	if retry, ok := policy.StatusCodeOverride(err); ok {
+		return retry
+	}
+	return transport_tpg.IsGoogleApiErrorWithCode(err, 429)
+}
+
 // Locking wrapper around read-modify-write cycle for IAM policy.
-func iamPolicyReadModifyWrite(updater ResourceIamUpdater, modify iamPolicyModifyFunc) error {
+func iamPolicyReadModifyWrite(updater ResourceIamUpdater, modify iamPolicyModifyFunc, config *transport_tpg.Config) error {
 	mutexKey := updater.GetMutexKey()
-	transport_tpg.MutexStore.Lock(mutexKey)
+	transport_tpg.MutexStore.LockInContext(config.RequestContext, mutexKey)
 	defer transport_tpg.MutexStore.Unlock(mutexKey)
 
-	backoff := time.Second
+	policy := iamRetryPolicy(config)
+	backoff := newIamBackoff(policy)
+	readBackoff := newIamBackoff(iamReadRetryPolicy(config))
 	for {
 		log.Printf("[DEBUG]: Retrieving policy for %s\n", updater.DescribeResource())
 		p, err := updater.GetResourceIamPolicy()
-		if transport_tpg.IsGoogleApiErrorWithCode(err, 429) {
-			log.Printf("[DEBUG] 429 while attempting to read policy for %s, waiting %v before attempting again", updater.DescribeResource(), backoff)
-			time.Sleep(backoff)
+		if err != nil {
+			if !iamShouldRetryRead(policy, err) {
+				return err
+			}
+			wait, ok := readBackoff.Next()
+			if !ok {
+				return errwrap.Wrapf(fmt.Sprintf("Error retrieving IAM policy for %s: Too many retries.  Latest error: {{err}}", updater.DescribeResource()), err)
+			}
+			log.Printf("[DEBUG] %v while attempting to read policy for %s, waiting %v before attempting again", err, updater.DescribeResource(), wait)
+			time.Sleep(wait)
 			continue
-		} else if err != nil {
-			return err
 		}
 		log.Printf("[DEBUG]: Retrieved policy for %s: %+v\n", updater.DescribeResource(), p)
 
@@ -109,26 +195,27 @@ func iamPolicyReadModifyWrite(updater ResourceIamUpdater, modify iamPolicyModify
 		log.Printf("[DEBUG]: Setting policy for %s to %+v\n", updater.DescribeResource(), p)
 		err = updater.SetResourceIamPolicy(p)
 		if err == nil {
-			fetchBackoff := 1 * time.Second
+			// Waiting for propagation is bounded by Timeout rather than by
+			// the number of attempts.
+			propagationPolicy := *policy
+			propagationPolicy.MaxAttempts = 0
+			fetchBackoff := newIamBackoff(&propagationPolicy)
+			fetchWait, _ := fetchBackoff.Next()
 			for successfulFetches := 0; successfulFetches < 3; {
-				if fetchBackoff > maxBackoffSeconds*time.Second {
-					return fmt.Errorf("Error applying IAM policy to %s: Waited too long for propagation.\n", updater.DescribeResource())
-				}
-				time.Sleep(fetchBackoff)
+				time.Sleep(fetchWait)
 				log.Printf("[DEBUG]: Retrieving policy for %s\n", updater.DescribeResource())
 				new_p, err := updater.GetResourceIamPolicy()
-				if err != nil {
-					// Quota for Read is pretty limited, so watch out for running out of quota.
-					if transport_tpg.IsGoogleApiErrorWithCode(err, 429) {
-						fetchBackoff = fetchBackoff * 2
-					} else {
-						return err
-					}
+				// Quota for Read is pretty limited, so watch out for running out of quota.
+				if err != nil && !transport_tpg.IsGoogleApiErrorWithCode(err, 429) {
+					return err
 				}
 				log.Printf("[DEBUG]: Retrieved policy for %s: %+v\n", updater.DescribeResource(), p)
 				if new_p == nil {
 					// https://github.com/hashicorp/terraform-provider-google/issues/2625
-					fetchBackoff = fetchBackoff * 2
+					var ok bool
+					if fetchWait, ok = fetchBackoff.Next(); !ok {
+						return fmt.Errorf("Error applying IAM policy to %s: Waited too long for propagation.\n", updater.DescribeResource())
+					}
 					continue
 				}
 				modified_p := new_p
@@ -143,18 +230,21 @@ func iamPolicyReadModifyWrite(updater ResourceIamUpdater, modify iamPolicyModify
 				if modified_p == new_p {
 					successfulFetches += 1
 				} else {
-					fetchBackoff = fetchBackoff * 2
+					var ok bool
+					if fetchWait, ok = fetchBackoff.Next(); !ok {
+						return fmt.Errorf("Error applying IAM policy to %s: Waited too long for propagation.\n", updater.DescribeResource())
+					}
 				}
 			}
 			break
 		}
 		if tpgresource.IsConflictError(err) {
-			log.Printf("[DEBUG]: Concurrent policy changes, restarting read-modify-write after %s\n", backoff)
-			time.Sleep(backoff)
-			backoff = backoff * 2
-			if backoff > 30*time.Second {
+			wait, ok := backoff.Next()
+			if !ok {
 				return errwrap.Wrapf(fmt.Sprintf("Error applying IAM policy to %s: Too many conflicts.  Latest error: {{err}}", updater.DescribeResource()), err)
 			}
+			log.Printf("[DEBUG]: Concurrent policy changes, restarting read-modify-write after %s\n", wait)
+			time.Sleep(wait)
 			continue
 		}
 
@@ -169,8 +259,11 @@ func iamPolicyReadModifyWrite(updater ResourceIamUpdater, modify iamPolicyModify
 				if p.Etag != currentPolicy.Etag {
 					// not matching indicates that there is a new state to attempt to apply
 					log.Printf("current and old etag did not match for %s, retrying", updater.DescribeResource())
-					time.Sleep(backoff)
-					backoff = backoff * 2
+					wait, ok := backoff.Next()
+					if !ok {
+						return errwrap.Wrapf(fmt.Sprintf("Error applying IAM policy to %s: gave up after %d attempts as its etag kept changing.  Latest error: {{err}}", updater.DescribeResource(), backoff.Attempts()), err)
+					}
+					time.Sleep(wait)
 					continue
 				}
 
@@ -259,7 +352,7 @@ func iamMemberIsCaseSensitive(member string) bool {
 		strings.HasPrefix(member, "principalHierarchy:")
 }
 
-// normalizeIamMemberCasing returns the case adjusted value of an iamMember
+// NormalizeIamMemberCasing returns the case adjusted value of an iamMember
 // this is important as iam will ignore casing unless it is one of the following
 // member types: principalSet, principal, principalHierarchy
 // members are in <type>:<value> format
@@ -268,7 +361,7 @@ func iamMemberIsCaseSensitive(member string) bool {
 // so lowercase the value unless iamMemberIsCaseSensitive and leave the type alone
 // since Dec '19 members can be prefixed with "deleted:" to indicate the principal
 // has been deleted
-func normalizeIamMemberCasing(member string) string {
+func NormalizeIamMemberCasing(member string) string {
 	var pieces []string
 	if strings.HasPrefix(member, "deleted:") {
 		pieces = strings.SplitN(member, ":", 3)
@@ -306,7 +399,7 @@ func createIamBindingsMap(bindings []*cloudresourcemanager.Binding) map[iamBindi
 		}
 		// Get each member (user/principal) for the binding
 		for _, m := range b.Members {
-			m = normalizeIamMemberCasing(m)
+			m = NormalizeIamMemberCasing(m)
 			// Add the member
 			members[m] = struct{}{}
 		}
@@ -469,6 +562,9 @@ func CompareAuditConfigs(a, b []*cloudresourcemanager.AuditConfig) bool {
 type IamSettings struct {
 	DeprecationMessage string
 	EnableBatching     bool
+	// BatchType is the batch type modifications are batched as if
+	// EnableBatching is set. It defaults to transport_tpg.BatchTypeIam.
+	BatchType string
 }
 
 func NewIamSettings(options ...func(*IamSettings)) *IamSettings {
@@ -493,6 +589,27 @@ func IamWithBatching(s *IamSettings) {
 	s.EnableBatching = true
 }
 
+// IamWithBatchType batches policy modifications with the batcher for
+// batchType, which is configured separately from other IAM resources.
+func IamWithBatchType(batchType string) func(s *IamSettings) {
+	return func(s *IamSettings) {
+		s.EnableBatching = true
+		s.BatchType = batchType
+	}
+}
+
+// batchType returns the batch type to batch policy modifications as, or ""
+// if they are not batched.
+func (s *IamSettings) batchType() string {
+	if !s.EnableBatching {
+		return ""
+	}
+	if s.BatchType == "" {
+		return transport_tpg.BatchTypeIam
+	}
+	return s.BatchType
+}
+
 // Util to deref and print auditConfigs
 func DebugPrintAuditConfigs(bs []*cloudresourcemanager.AuditConfig) string {
 	v, _ := json.MarshalIndent(bs, "", "\t")
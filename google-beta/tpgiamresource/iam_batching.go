@@ -14,18 +14,22 @@ const (
 	batchKeyTmplModifyIamPolicy = "%s modifyIamPolicy"
 )
 
-func BatchRequestModifyIamPolicy(updater ResourceIamUpdater, modify iamPolicyModifyFunc, config *transport_tpg.Config, reqDesc string) error {
+// BatchRequestModifyIamPolicy modifies the IAM policy of updater's resource,
+// combining modify with concurrent modifications of the same policy sent by
+// the batcher for batchType.
+func BatchRequestModifyIamPolicy(updater ResourceIamUpdater, modify iamPolicyModifyFunc, config *transport_tpg.Config, batchType, reqDesc string) error {
 	batchKey := fmt.Sprintf(batchKeyTmplModifyIamPolicy, updater.GetMutexKey())
 
 	request := &transport_tpg.BatchRequest{
 		ResourceName: updater.GetResourceId(),
 		Body:         []iamPolicyModifyFunc{modify},
 		CombineF:     combineBatchIamPolicyModifiers,
-		SendF:        sendBatchModifyIamPolicy(updater),
+		SendF:        sendBatchModifyIamPolicy(updater, config),
 		DebugId:      reqDesc,
+		Context:      config.RequestContext,
 	}
 
-	_, err := config.RequestBatcherIam.SendRequestWithTimeout(batchKey, request, time.Minute*30)
+	_, err := config.RequestBatchers.Get(batchType).SendRequestWithTimeout(batchKey, request, time.Minute*30)
 	return err
 }
 
@@ -43,7 +47,7 @@ func combineBatchIamPolicyModifiers(currV interface{}, toAddV interface{}) (inte
 	return append(currModifiers, newModifiers...), nil
 }
 
-func sendBatchModifyIamPolicy(updater ResourceIamUpdater) transport_tpg.BatcherSendFunc {
+func sendBatchModifyIamPolicy(updater ResourceIamUpdater, config *transport_tpg.Config) transport_tpg.BatcherSendFunc {
 	return func(resourceName string, body interface{}) (interface{}, error) {
 		modifiers, ok := body.([]iamPolicyModifyFunc)
 		if !ok {
@@ -56,6 +60,6 @@ func sendBatchModifyIamPolicy(updater ResourceIamUpdater) transport_tpg.BatcherS
 				}
 			}
 			return nil
-		})
+		}, config)
 	}
 }
@@ -4,10 +4,15 @@ package tpgiamresource
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/googleapi"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
 )
 
 func TestIamMergeBindings(t *testing.T) {
@@ -1325,3 +1330,125 @@ func TestIamListFromIamAuditConfigsMap(t *testing.T) {
 		}
 	}
 }
+
+// testIamUpdater fails the first reads with readErrors, then serves and
+// stores a policy.
+type testIamUpdater struct {
+	readErrors []error
+	reads      int
+	policy     *cloudresourcemanager.Policy
+}
+
+func (u *testIamUpdater) GetResourceIamPolicy() (*cloudresourcemanager.Policy, error) {
+	u.reads++
+	if len(u.readErrors) > 0 {
+		err := u.readErrors[0]
+		u.readErrors = u.readErrors[1:]
+		return nil, err
+	}
+	return u.policy, nil
+}
+
+func (u *testIamUpdater) SetResourceIamPolicy(policy *cloudresourcemanager.Policy) error {
+	u.policy = policy
+	return nil
+}
+
+func (u *testIamUpdater) GetMutexKey() string      { return "iam-test" }
+func (u *testIamUpdater) GetResourceId() string    { return "test" }
+func (u *testIamUpdater) DescribeResource() string { return "test" }
+
+func TestIamPolicyReadModifyWrite_readRetries(t *testing.T) {
+	modify := func(p *cloudresourcemanager.Policy) error { return nil }
+	policy := &transport_tpg.RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Multiplier:     2,
+		MaxAttempts:    3,
+		Timeout:        time.Minute,
+	}
+	config := &transport_tpg.Config{RetryPolicy: policy}
+
+	// 429s are retried until max_attempts is reached.
+	u := &testIamUpdater{policy: &cloudresourcemanager.Policy{}}
+	for i := 0; i < 5; i++ {
+		u.readErrors = append(u.readErrors, &googleapi.Error{Code: 429})
+	}
+	err := iamPolicyReadModifyWrite(u, modify, config)
+	if err == nil || !strings.Contains(err.Error(), "Too many retries") {
+		t.Fatalf("expected reading to give up, got %v", err)
+	}
+	if u.reads != 3 {
+		t.Errorf("expected 3 reads, got %d", u.reads)
+	}
+
+	// Other codes are retried when a status_code_override says so.
+	policy.StatusCodeOverrides = map[int]bool{500: true}
+	u = &testIamUpdater{
+		readErrors: []error{&googleapi.Error{Code: 500}},
+		policy:     &cloudresourcemanager.Policy{},
+	}
+	if err := iamPolicyReadModifyWrite(u, modify, config); err != nil {
+		t.Fatalf("expected the 500 to be retried, got %v", err)
+	}
+
+	// And 429s are not retried when one says otherwise.
+	policy.StatusCodeOverrides = map[int]bool{429: false}
+	u = &testIamUpdater{
+		readErrors: []error{&googleapi.Error{Code: 429}},
+		policy:     &cloudresourcemanager.Policy{},
+	}
+	if err := iamPolicyReadModifyWrite(u, modify, config); err == nil || u.reads != 1 {
+		t.Fatalf("expected the 429 not to be retried, got %v after %d reads", err, u.reads)
+	}
+}
+
+func TestIamBackoff_timeout(t *testing.T) {
+	b := newIamBackoff(&transport_tpg.RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     4 * time.Second,
+		Multiplier:     2,
+		Timeout:        10 * time.Second,
+	})
+	var waits []time.Duration
+	for {
+		wait, ok := b.Next()
+		if !ok {
+			break
+		}
+		waits = append(waits, wait)
+	}
+	// Waits are capped by MaxBackoff, and stop before exceeding Timeout.
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if !reflect.DeepEqual(waits, expected) {
+		t.Errorf("expected waits %v, got %v", expected, waits)
+	}
+}
+
+func TestIamBackoff_defaults(t *testing.T) {
+	b := newIamBackoff(iamRetryPolicy(&transport_tpg.Config{}))
+	var waits []time.Duration
+	for {
+		wait, ok := b.Next()
+		if !ok {
+			break
+		}
+		waits = append(waits, wait)
+	}
+	// Without a retry_policy, conflicts and propagation keep their old limits.
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second}
+	if !reflect.DeepEqual(waits, expected) {
+		t.Errorf("expected waits %v, got %v", expected, waits)
+	}
+	if b.Attempts() != 6 {
+		t.Errorf("expected 6 attempts, got %d", b.Attempts())
+	}
+
+	// And reads are retried every second without a limit.
+	b = newIamBackoff(iamReadRetryPolicy(&transport_tpg.Config{}))
+	for i := 0; i < 1000; i++ {
+		if wait, ok := b.Next(); !ok || wait != time.Second {
+			t.Fatalf("expected read %d to be retried after a second, got %v, %v", i, wait, ok)
+		}
+	}
+}
@@ -52,10 +52,10 @@ func ResourceIamAuditConfig(parentSpecificSchema map[string]*schema.Schema, newU
 	settings := NewIamSettings(options...)
 
 	return &schema.Resource{
-		Create: resourceIamAuditConfigCreateUpdate(newUpdaterFunc, settings.EnableBatching),
+		Create: resourceIamAuditConfigCreateUpdate(newUpdaterFunc, settings.batchType()),
 		Read:   resourceIamAuditConfigRead(newUpdaterFunc),
-		Update: resourceIamAuditConfigCreateUpdate(newUpdaterFunc, settings.EnableBatching),
-		Delete: resourceIamAuditConfigDelete(newUpdaterFunc, settings.EnableBatching),
+		Update: resourceIamAuditConfigCreateUpdate(newUpdaterFunc, settings.batchType()),
+		Delete: resourceIamAuditConfigDelete(newUpdaterFunc, settings.batchType()),
 		Schema: tpgresource.MergeSchemas(iamAuditConfigSchema, parentSpecificSchema),
 		Importer: &schema.ResourceImporter{
 			State: iamAuditConfigImport(resourceIdParser),
@@ -137,7 +137,7 @@ func iamAuditConfigImport(resourceIdParser ResourceIdParserFunc) schema.StateFun
 	}
 }
 
-func resourceIamAuditConfigCreateUpdate(newUpdaterFunc NewResourceIamUpdaterFunc, enableBatching bool) func(*schema.ResourceData, interface{}) error {
+func resourceIamAuditConfigCreateUpdate(newUpdaterFunc NewResourceIamUpdaterFunc, batchType string) func(*schema.ResourceData, interface{}) error {
 	return func(d *schema.ResourceData, meta interface{}) error {
 		config := meta.(*transport_tpg.Config)
 
@@ -152,11 +152,11 @@ func resourceIamAuditConfigCreateUpdate(newUpdaterFunc NewResourceIamUpdaterFunc
 			ep.AuditConfigs = append(cleaned, ac)
 			return nil
 		}
-		if enableBatching {
-			err = BatchRequestModifyIamPolicy(updater, modifyF, config, fmt.Sprintf(
+		if batchType != "" {
+			err = BatchRequestModifyIamPolicy(updater, modifyF, config, batchType, fmt.Sprintf(
 				"Overwrite audit config for service %s on resource %q", ac.Service, updater.DescribeResource()))
 		} else {
-			err = iamPolicyReadModifyWrite(updater, modifyF)
+			err = iamPolicyReadModifyWrite(updater, modifyF, config)
 		}
 		if err != nil {
 			return err
@@ -166,7 +166,7 @@ func resourceIamAuditConfigCreateUpdate(newUpdaterFunc NewResourceIamUpdaterFunc
 	}
 }
 
-func resourceIamAuditConfigDelete(newUpdaterFunc NewResourceIamUpdaterFunc, enableBatching bool) schema.DeleteFunc {
+func resourceIamAuditConfigDelete(newUpdaterFunc NewResourceIamUpdaterFunc, batchType string) schema.DeleteFunc {
 	return func(d *schema.ResourceData, meta interface{}) error {
 		config := meta.(*transport_tpg.Config)
 
@@ -180,11 +180,11 @@ func resourceIamAuditConfigDelete(newUpdaterFunc NewResourceIamUpdaterFunc, enab
 			ep.AuditConfigs = removeAllAuditConfigsWithService(ep.AuditConfigs, ac.Service)
 			return nil
 		}
-		if enableBatching {
-			err = BatchRequestModifyIamPolicy(updater, modifyF, config, fmt.Sprintf(
+		if batchType != "" {
+			err = BatchRequestModifyIamPolicy(updater, modifyF, config, batchType, fmt.Sprintf(
 				"Delete audit config for service %s on resource %q", ac.Service, updater.DescribeResource()))
 		} else {
-			err = iamPolicyReadModifyWrite(updater, modifyF)
+			err = iamPolicyReadModifyWrite(updater, modifyF, config)
 		}
 		if err != nil {
 			return transport_tpg.HandleNotFoundError(err, d, fmt.Sprintf("Resource %s with IAM audit config %q", updater.DescribeResource(), d.Id()))
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
 	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -42,9 +43,10 @@ var iamBindingSchema = map[string]*schema.Schema{
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
 				"expression": {
-					Type:     schema.TypeString,
-					Required: true,
-					ForceNew: true,
+					Type:         schema.TypeString,
+					Required:     true,
+					ForceNew:     true,
+					ValidateFunc: verify.ValidateCELExpression(verify.IAMConditionCELEnvironment),
 				},
 				"title": {
 					Type:     schema.TypeString,
@@ -69,10 +71,10 @@ func ResourceIamBinding(parentSpecificSchema map[string]*schema.Schema, newUpdat
 	settings := NewIamSettings(options...)
 
 	return &schema.Resource{
-		Create: resourceIamBindingCreateUpdate(newUpdaterFunc, settings.EnableBatching),
+		Create: resourceIamBindingCreateUpdate(newUpdaterFunc, settings.batchType()),
 		Read:   resourceIamBindingRead(newUpdaterFunc),
-		Update: resourceIamBindingCreateUpdate(newUpdaterFunc, settings.EnableBatching),
-		Delete: resourceIamBindingDelete(newUpdaterFunc, settings.EnableBatching),
+		Update: resourceIamBindingCreateUpdate(newUpdaterFunc, settings.batchType()),
+		Delete: resourceIamBindingDelete(newUpdaterFunc, settings.batchType()),
 
 		// if non-empty, this will be used to send a deprecation message when the
 		// resource is used.
@@ -86,7 +88,7 @@ func ResourceIamBinding(parentSpecificSchema map[string]*schema.Schema, newUpdat
 	}
 }
 
-func resourceIamBindingCreateUpdate(newUpdaterFunc NewResourceIamUpdaterFunc, enableBatching bool) func(*schema.ResourceData, interface{}) error {
+func resourceIamBindingCreateUpdate(newUpdaterFunc NewResourceIamUpdaterFunc, batchType string) func(*schema.ResourceData, interface{}) error {
 	return func(d *schema.ResourceData, meta interface{}) error {
 		config := meta.(*transport_tpg.Config)
 		updater, err := newUpdaterFunc(d, config)
@@ -102,11 +104,11 @@ func resourceIamBindingCreateUpdate(newUpdaterFunc NewResourceIamUpdaterFunc, en
 			return nil
 		}
 
-		if enableBatching {
-			err = BatchRequestModifyIamPolicy(updater, modifyF, config, fmt.Sprintf(
+		if batchType != "" {
+			err = BatchRequestModifyIamPolicy(updater, modifyF, config, batchType, fmt.Sprintf(
 				"Set IAM Binding for role %q on %q", binding.Role, updater.DescribeResource()))
 		} else {
-			err = iamPolicyReadModifyWrite(updater, modifyF)
+			err = iamPolicyReadModifyWrite(updater, modifyF, config)
 		}
 		if err != nil {
 			return err
@@ -254,7 +256,7 @@ func iamBindingImport(newUpdaterFunc NewResourceIamUpdaterFunc, resourceIdParser
 	}
 }
 
-func resourceIamBindingDelete(newUpdaterFunc NewResourceIamUpdaterFunc, enableBatching bool) schema.DeleteFunc {
+func resourceIamBindingDelete(newUpdaterFunc NewResourceIamUpdaterFunc, batchType string) schema.DeleteFunc {
 	return func(d *schema.ResourceData, meta interface{}) error {
 		config := meta.(*transport_tpg.Config)
 
@@ -269,11 +271,11 @@ func resourceIamBindingDelete(newUpdaterFunc NewResourceIamUpdaterFunc, enableBa
 			return nil
 		}
 
-		if enableBatching {
-			err = BatchRequestModifyIamPolicy(updater, modifyF, config, fmt.Sprintf(
+		if batchType != "" {
+			err = BatchRequestModifyIamPolicy(updater, modifyF, config, batchType, fmt.Sprintf(
 				"Delete IAM Binding for role %q on %q", binding.Role, updater.DescribeResource()))
 		} else {
-			err = iamPolicyReadModifyWrite(updater, modifyF)
+			err = iamPolicyReadModifyWrite(updater, modifyF, config)
 		}
 		if err != nil {
 			return transport_tpg.HandleNotFoundError(err, d, fmt.Sprintf("Resource %q for IAM binding with role %q", updater.DescribeResource(), binding.Role))
@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
 	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/verify"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -66,9 +67,10 @@ var IamMemberBaseSchema = map[string]*schema.Schema{
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
 				"expression": {
-					Type:     schema.TypeString,
-					Required: true,
-					ForceNew: true,
+					Type:         schema.TypeString,
+					Required:     true,
+					ForceNew:     true,
+					ValidateFunc: verify.ValidateCELExpression(verify.IAMConditionCELEnvironment),
 				},
 				"title": {
 					Type:     schema.TypeString,
@@ -115,7 +117,7 @@ func iamMemberImport(newUpdaterFunc NewResourceIamUpdaterFunc, resourceIdParser
 		if err := d.Set("role", role); err != nil {
 			return nil, fmt.Errorf("Error setting role: %s", err)
 		}
-		if err := d.Set("member", normalizeIamMemberCasing(member)); err != nil {
+		if err := d.Set("member", NormalizeIamMemberCasing(member)); err != nil {
 			return nil, fmt.Errorf("Error setting member: %s", err)
 		}
 
@@ -126,7 +128,7 @@ func iamMemberImport(newUpdaterFunc NewResourceIamUpdaterFunc, resourceIdParser
 
 		// Set the ID again so that the ID matches the ID it would have if it had been created via TF.
 		// Use the current ID in case it changed in the ResourceIdParserFunc.
-		d.SetId(d.Id() + "/" + role + "/" + normalizeIamMemberCasing(member))
+		d.SetId(d.Id() + "/" + role + "/" + NormalizeIamMemberCasing(member))
 
 		// Read the upstream policy so we can set the full condition.
 		updater, err := newUpdaterFunc(d, config)
@@ -175,9 +177,9 @@ func ResourceIamMember(parentSpecificSchema map[string]*schema.Schema, newUpdate
 	settings := NewIamSettings(options...)
 
 	return &schema.Resource{
-		Create: resourceIamMemberCreate(newUpdaterFunc, settings.EnableBatching),
+		Create: resourceIamMemberCreate(newUpdaterFunc, settings.batchType()),
 		Read:   resourceIamMemberRead(newUpdaterFunc),
-		Delete: resourceIamMemberDelete(newUpdaterFunc, settings.EnableBatching),
+		Delete: resourceIamMemberDelete(newUpdaterFunc, settings.batchType()),
 
 		// if non-empty, this will be used to send a deprecation message when the
 		// resource is used.
@@ -202,7 +204,7 @@ func getResourceIamMember(d *schema.ResourceData) *cloudresourcemanager.Binding
 	return b
 }
 
-func resourceIamMemberCreate(newUpdaterFunc NewResourceIamUpdaterFunc, enableBatching bool) schema.CreateFunc {
+func resourceIamMemberCreate(newUpdaterFunc NewResourceIamUpdaterFunc, batchType string) schema.CreateFunc {
 	return func(d *schema.ResourceData, meta interface{}) error {
 		config := meta.(*transport_tpg.Config)
 
@@ -218,16 +220,16 @@ func resourceIamMemberCreate(newUpdaterFunc NewResourceIamUpdaterFunc, enableBat
 			ep.Version = IamPolicyVersion
 			return nil
 		}
-		if enableBatching {
-			err = BatchRequestModifyIamPolicy(updater, modifyF, config,
+		if batchType != "" {
+			err = BatchRequestModifyIamPolicy(updater, modifyF, config, batchType,
 				fmt.Sprintf("Create IAM Members %s %+v for %s", memberBind.Role, memberBind.Members[0], updater.DescribeResource()))
 		} else {
-			err = iamPolicyReadModifyWrite(updater, modifyF)
+			err = iamPolicyReadModifyWrite(updater, modifyF, config)
 		}
 		if err != nil {
 			return err
 		}
-		d.SetId(updater.GetResourceId() + "/" + memberBind.Role + "/" + normalizeIamMemberCasing(memberBind.Members[0]))
+		d.SetId(updater.GetResourceId() + "/" + memberBind.Role + "/" + NormalizeIamMemberCasing(memberBind.Members[0]))
 		if k := conditionKeyFromCondition(memberBind.Condition); !k.Empty() {
 			d.SetId(d.Id() + "/" + k.String())
 		}
@@ -297,7 +299,7 @@ func resourceIamMemberRead(newUpdaterFunc NewResourceIamUpdaterFunc) schema.Read
 	}
 }
 
-func resourceIamMemberDelete(newUpdaterFunc NewResourceIamUpdaterFunc, enableBatching bool) schema.DeleteFunc {
+func resourceIamMemberDelete(newUpdaterFunc NewResourceIamUpdaterFunc, batchType string) schema.DeleteFunc {
 	return func(d *schema.ResourceData, meta interface{}) error {
 		config := meta.(*transport_tpg.Config)
 
@@ -312,11 +314,11 @@ func resourceIamMemberDelete(newUpdaterFunc NewResourceIamUpdaterFunc, enableBat
 			ep.Bindings = subtractFromBindings(ep.Bindings, memberBind)
 			return nil
 		}
-		if enableBatching {
-			err = BatchRequestModifyIamPolicy(updater, modifyF, config,
+		if batchType != "" {
+			err = BatchRequestModifyIamPolicy(updater, modifyF, config, batchType,
 				fmt.Sprintf("Delete IAM Members %s %s for %q", memberBind.Role, memberBind.Members[0], updater.DescribeResource()))
 		} else {
-			err = iamPolicyReadModifyWrite(updater, modifyF)
+			err = iamPolicyReadModifyWrite(updater, modifyF, config)
 		}
 		if err != nil {
 			return transport_tpg.HandleNotFoundError(err, d, fmt.Sprintf("Resource %s for IAM Member (role %q, %q)", updater.GetResourceId(), memberBind.Members[0], memberBind.Role))
@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package tpgresource
+
+import (
+	"fmt"
+	"net"
+)
+
+// ParseCidrs parses a list of IP ranges in CIDR notation.
+func ParseCidrs(cidrs []string) ([]*net.IPNet, error) {
+	ipnets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			// Firewall rules and routes accept bare addresses as /32 or /128
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not a valid IP CIDR range", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ipnet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		}
+		ipnets = append(ipnets, ipnet)
+	}
+	return ipnets, nil
+}
+
+// CidrContains returns whether every address of b is in a. Ranges of
+// different IP versions never contain each other.
+func CidrContains(a, b *net.IPNet) bool {
+	aPrefixLength, aBits := a.Mask.Size()
+	bPrefixLength, bBits := b.Mask.Size()
+	return aBits == bBits && aPrefixLength <= bPrefixLength && a.Contains(b.IP)
+}
+
+// CidrsOverlap returns whether a and b have any address in common. Two
+// ranges overlap exactly when one of them contains the other.
+func CidrsOverlap(a, b *net.IPNet) bool {
+	return CidrContains(a, b) || CidrContains(b, a)
+}
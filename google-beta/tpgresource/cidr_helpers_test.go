@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package tpgresource
+
+import (
+	"testing"
+)
+
+func TestCidrContains(t *testing.T) {
+	cases := map[string]struct {
+		A, B     string
+		Contains bool
+		Overlaps bool
+	}{
+		"subnet": {
+			A: "10.0.0.0/16", B: "10.0.4.0/24",
+			Contains: true, Overlaps: true,
+		},
+		"supernet": {
+			A: "10.0.4.0/24", B: "10.0.0.0/16",
+			Contains: false, Overlaps: true,
+		},
+		"disjoint": {
+			A: "10.0.0.0/24", B: "10.0.1.0/24",
+			Contains: false, Overlaps: false,
+		},
+		"address": {
+			A: "0.0.0.0/0", B: "192.168.1.1",
+			Contains: true, Overlaps: true,
+		},
+		"different IP versions": {
+			A: "0.0.0.0/0", B: "2001:db8::/32",
+			Contains: false, Overlaps: false,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ipnets, err := ParseCidrs([]string{tc.A, tc.B})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := CidrContains(ipnets[0], ipnets[1]); got != tc.Contains {
+				t.Errorf("expected CidrContains to be %t, got %t", tc.Contains, got)
+			}
+			if got := CidrsOverlap(ipnets[0], ipnets[1]); got != tc.Overlaps {
+				t.Errorf("expected CidrsOverlap to be %t, got %t", tc.Overlaps, got)
+			}
+		})
+	}
+
+	if _, err := ParseCidrs([]string{"10.0.0.0/33"}); err == nil {
+		t.Errorf("expected an error parsing an invalid range")
+	}
+}
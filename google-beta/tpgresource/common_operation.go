@@ -3,12 +3,16 @@
 package tpgresource
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+	"go.opentelemetry.io/otel/attribute"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 )
 
@@ -100,6 +104,70 @@ func (w *CommonOperationWaiter) TargetStates() []string {
 	return []string{"done: true"}
 }
 
+// Progress implements ProgressWaiter for operations whose metadata reports a
+// completion percentage.
+func (w *CommonOperationWaiter) Progress() (float64, time.Time, bool) {
+	if w == nil || len(w.Op.Metadata) == 0 {
+		return 0, time.Time{}, false
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(w.Op.Metadata, &metadata); err != nil {
+		return 0, time.Time{}, false
+	}
+	return OperationMetadataProgress(metadata)
+}
+
+// OperationMetadataProgress reads the completion percentage and start time
+// from an operation's metadata. APIs report them either at the top level of
+// the metadata or in a nested `progress` object, as `progressPercent` or
+// `progressPercentage` and `startTime` or `createTime`.
+func OperationMetadataProgress(metadata map[string]interface{}) (float64, time.Time, bool) {
+	candidates := []map[string]interface{}{metadata}
+	if nested, ok := metadata["progress"].(map[string]interface{}); ok {
+		candidates = append([]map[string]interface{}{nested}, candidates...)
+	}
+
+	percent, hasPercent := 0.0, false
+	var started time.Time
+	for _, m := range candidates {
+		if !hasPercent {
+			for _, k := range []string{"progressPercent", "progressPercentage"} {
+				if p, ok := metadataNumber(m[k]); ok {
+					percent, hasPercent = p, true
+					break
+				}
+			}
+		}
+		if started.IsZero() {
+			for _, k := range []string{"startTime", "createTime"} {
+				if raw, ok := m[k].(string); ok {
+					if t, err := time.Parse(time.RFC3339, raw); err == nil {
+						started = t
+						break
+					}
+				}
+			}
+		}
+	}
+	if !hasPercent || started.IsZero() {
+		return 0, time.Time{}, false
+	}
+	return percent / 100, started, true
+}
+
+// metadataNumber converts a JSON number, or an int64 encoded as a JSON string,
+// to a float64.
+func metadataNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 func OperationDone(w Waiter) bool {
 	for _, s := range w.TargetStates() {
 		if s == w.State() {
@@ -110,7 +178,17 @@ func OperationDone(w Waiter) bool {
 }
 
 func CommonRefreshFunc(w Waiter) resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
+	return commonRefreshFunc(context.Background(), w)
+}
+
+func commonRefreshFunc(ctx context.Context, w Waiter) resource.StateRefreshFunc {
+	return func() (opRaw interface{}, state string, err error) {
+		_, span := transport_tpg.StartSpan(ctx, "OperationWait poll", attribute.String("operation.name", w.OpName()))
+		defer func() {
+			span.SetAttributes(attribute.String("operation.state", state))
+			transport_tpg.EndSpan(span, err)
+		}()
+
 		op, err := w.QueryOp()
 		if err != nil {
 			// Retry 404 when getting operation (not resource state)
@@ -138,30 +216,161 @@ func CommonRefreshFunc(w Waiter) resource.StateRefreshFunc {
 	}
 }
 
+// ProgressWaiter is implemented by waiters whose operations report how far
+// along they are. OperationWaitWithPolicy uses the progress to estimate when
+// the operation will finish and time the next poll accordingly.
+type ProgressWaiter interface {
+	// Progress returns the fraction of the operation that is complete, between
+	// 0 and 1, and the time it started. ok is false if the operation doesn't
+	// report its progress.
+	Progress() (done float64, started time.Time, ok bool)
+}
+
+// notFoundChecks is the number of consecutive polls that may find no
+// operation before waiting fails.
+const notFoundChecks = 20
+
+// OperationWait waits for the operation with a fixed ceiling on the interval
+// between polls, outside of any resource CRUD call's trace. Waiters with a
+// config should use OperationWaitWithPolicy with the config's
+// OperationPollingPolicy instead.
 func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	policy := &transport_tpg.OperationPollingPolicy{
+		InitialInterval: transport_tpg.DefaultOperationPollInitialInterval,
+		MaxInterval:     pollInterval,
+		Multiplier:      transport_tpg.DefaultOperationPollMultiplier,
+	}
+	if policy.MaxInterval <= 0 {
+		policy.MaxInterval = transport_tpg.DefaultOperationPollMaxInterval
+	}
+	if policy.InitialInterval > policy.MaxInterval {
+		policy.InitialInterval = policy.MaxInterval
+	}
+	return OperationWaitWithPolicy(w, activity, timeout, policy)
+}
+
+// OperationWaitWithPolicy waits for the operation, polling it immediately and
+// then backing off as described by the policy. The wait is traced as part of
+// the policy's Context.
+func OperationWaitWithPolicy(w Waiter, activity string, timeout time.Duration, policy *transport_tpg.OperationPollingPolicy) error {
 	if OperationDone(w) {
 		return w.Error()
 	}
 
-	c := &resource.StateChangeConf{
-		Pending:      w.PendingStates(),
-		Target:       w.TargetStates(),
-		Refresh:      CommonRefreshFunc(w),
-		Timeout:      timeout,
-		MinTimeout:   2 * time.Second,
-		PollInterval: pollInterval,
+	ctx, span := transport_tpg.StartSpan(policy.Context, "OperationWait",
+		attribute.String("operation.activity", activity),
+		attribute.String("operation.name", w.OpName()),
+	)
+	err := operationWait(ctx, w, activity, timeout, policy)
+	transport_tpg.EndSpan(span, err)
+	return err
+}
+
+func operationWait(ctx context.Context, w Waiter, activity string, timeout time.Duration, policy *transport_tpg.OperationPollingPolicy) error {
+	refresh := commonRefreshFunc(ctx, w)
+	deadline := time.Now().Add(timeout)
+	interval := policy.InitialInterval
+	notFound := 0
+	lastState := ""
+
+	for {
+		opRaw, state, err := refresh()
+		if err != nil {
+			return fmt.Errorf("Error waiting for %s: %w", activity, err)
+		}
+
+		if opRaw == nil {
+			notFound++
+			if notFound > notFoundChecks {
+				return fmt.Errorf("Error waiting for %s: %w", activity, &resource.NotFoundError{
+					LastError: fmt.Errorf("couldn't find operation %s", w.OpName()),
+					Retries:   notFound,
+				})
+			}
+		} else {
+			notFound = 0
+			lastState = state
+			if StringInSlice(w.TargetStates(), state) {
+				if err := w.SetOp(opRaw); err != nil {
+					return err
+				}
+				return w.Error()
+			}
+			if !StringInSlice(w.PendingStates(), state) {
+				return fmt.Errorf("Error waiting for %s: %w", activity, &resource.UnexpectedStateError{
+					State:         state,
+					ExpectedState: w.TargetStates(),
+				})
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("Error waiting for %s: %w", activity, &resource.TimeoutError{
+				LastState:     lastState,
+				Timeout:       timeout,
+				ExpectedState: w.TargetStates(),
+			})
+		}
+
+		wait := interval
+		if estimate, ok := estimatePollInterval(w, policy); ok {
+			wait = estimate
+		}
+		if wait > remaining {
+			wait = remaining
+		}
+		log.Printf("[DEBUG] Waiting %s before polling operation %s again", wait, w.OpName())
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("Error waiting for %s: %w", activity, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = nextPollInterval(interval, policy)
+	}
+}
+
+// nextPollInterval returns the interval that follows interval under the
+// policy.
+func nextPollInterval(interval time.Duration, policy *transport_tpg.OperationPollingPolicy) time.Duration {
+	if policy.Multiplier > 1 {
+		interval = time.Duration(float64(interval) * policy.Multiplier)
 	}
-	opRaw, err := c.WaitForState()
-	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %w", activity, err)
+	if interval > policy.MaxInterval {
+		interval = policy.MaxInterval
 	}
+	return interval
+}
 
-	err = w.SetOp(opRaw)
-	if err != nil {
-		return err
+// estimatePollInterval returns the time until the operation is expected to
+// finish, based on the progress the waiter reports, bounded by the policy's
+// intervals. ok is false if the waiter doesn't report progress.
+func estimatePollInterval(w Waiter, policy *transport_tpg.OperationPollingPolicy) (time.Duration, bool) {
+	pw, ok := w.(ProgressWaiter)
+	if !ok {
+		return 0, false
+	}
+	done, started, ok := pw.Progress()
+	if !ok || done <= 0 || done > 1 || started.IsZero() {
+		return 0, false
 	}
 
-	return w.Error()
+	elapsed := time.Since(started)
+	if elapsed <= 0 {
+		return 0, false
+	}
+	remaining := time.Duration(float64(elapsed) * (1 - done) / done)
+	if remaining < policy.InitialInterval {
+		remaining = policy.InitialInterval
+	}
+	if remaining > policy.MaxInterval {
+		remaining = policy.MaxInterval
+	}
+	return remaining, true
 }
 
 // The cloud resource manager API operation is an example of one of many
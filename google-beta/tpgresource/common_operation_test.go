@@ -4,6 +4,7 @@ package tpgresource
 
 import (
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -69,3 +70,174 @@ func TestOperationWait_TimeoutsShouldRetry(t *testing.T) {
 			expectedRunCount, testWaiter.runCount)
 	}
 }
+
+type pollingTestWaiter struct {
+	doneAfter int
+	polls     []time.Time
+	progress  float64
+	started   time.Time
+}
+
+func (w *pollingTestWaiter) State() string {
+	if len(w.polls) >= w.doneAfter {
+		return "DONE"
+	}
+	return "RUNNING"
+}
+
+func (pollingTestWaiter) IsRetryable(err error) bool {
+	return false
+}
+
+func (pollingTestWaiter) Error() error {
+	return nil
+}
+
+func (pollingTestWaiter) SetOp(interface{}) error {
+	return nil
+}
+
+func (w *pollingTestWaiter) QueryOp() (interface{}, error) {
+	w.polls = append(w.polls, time.Now())
+	return "op", nil
+}
+
+func (pollingTestWaiter) OpName() string {
+	return "my-operation-name"
+}
+
+func (pollingTestWaiter) PendingStates() []string {
+	return []string{"RUNNING"}
+}
+
+func (pollingTestWaiter) TargetStates() []string {
+	return []string{"DONE"}
+}
+
+type progressTestWaiter struct {
+	pollingTestWaiter
+}
+
+func (w *progressTestWaiter) Progress() (float64, time.Time, bool) {
+	return w.progress, w.started, true
+}
+
+func TestOperationWaitWithPolicy_backsOff(t *testing.T) {
+	w := &pollingTestWaiter{doneAfter: 4}
+	policy := &transport_tpg.OperationPollingPolicy{
+		InitialInterval: 20 * time.Millisecond,
+		MaxInterval:     50 * time.Millisecond,
+		Multiplier:      2,
+	}
+	if err := OperationWaitWithPolicy(w, "my-activity", time.Minute, policy); err != nil {
+		t.Fatalf("unexpected error waiting for operation: %s", err)
+	}
+	if len(w.polls) != 4 {
+		t.Fatalf("expected 4 polls, got %d", len(w.polls))
+	}
+
+	// Waits of 20ms, 40ms and 50ms, capped by the max interval
+	for i, min := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond} {
+		if got := w.polls[i+1].Sub(w.polls[i]); got < min {
+			t.Errorf("expected wait %d to be at least %s, got %s", i, min, got)
+		}
+	}
+}
+
+func TestOperationWaitWithPolicy_timeout(t *testing.T) {
+	w := &pollingTestWaiter{doneAfter: 1000}
+	policy := &transport_tpg.OperationPollingPolicy{
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+		Multiplier:      2,
+	}
+	err := OperationWaitWithPolicy(w, "my-activity", 50*time.Millisecond, policy)
+	if err == nil || !strings.Contains(err.Error(), "timeout while waiting for state to become 'DONE'") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestEstimatePollInterval(t *testing.T) {
+	policy := &transport_tpg.OperationPollingPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      2,
+	}
+
+	cases := map[string]struct {
+		Progress float64
+		Elapsed  time.Duration
+		Expected time.Duration
+	}{
+		"halfway": {
+			Progress: 0.5,
+			Elapsed:  20 * time.Second,
+			Expected: 20 * time.Second,
+		},
+		"nearly done": {
+			Progress: 0.99,
+			Elapsed:  10 * time.Second,
+			Expected: time.Second,
+		},
+		"just started": {
+			Progress: 0.01,
+			Elapsed:  10 * time.Second,
+			Expected: time.Minute,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			w := &progressTestWaiter{}
+			w.progress = tc.Progress
+			w.started = time.Now().Add(-tc.Elapsed)
+			got, ok := estimatePollInterval(w, policy)
+			if !ok {
+				t.Fatalf("expected an estimate")
+			}
+			if diff := got - tc.Expected; diff < -time.Second || diff > time.Second {
+				t.Errorf("expected an estimate of about %s, got %s", tc.Expected, got)
+			}
+		})
+	}
+
+	if _, ok := estimatePollInterval(&pollingTestWaiter{}, policy); ok {
+		t.Errorf("expected no estimate for a waiter without progress")
+	}
+}
+
+func TestOperationMetadataProgress(t *testing.T) {
+	cases := map[string]struct {
+		Metadata map[string]interface{}
+		Expected float64
+		Ok       bool
+	}{
+		"top level": {
+			Metadata: map[string]interface{}{"progressPercent": float64(40), "createTime": "2024-01-02T15:04:05Z"},
+			Expected: 0.4,
+			Ok:       true,
+		},
+		"nested": {
+			Metadata: map[string]interface{}{
+				"progress": map[string]interface{}{"progressPercent": "75", "startTime": "2024-01-02T15:04:05.123Z"},
+			},
+			Expected: 0.75,
+			Ok:       true,
+		},
+		"no start time": {
+			Metadata: map[string]interface{}{"progressPercentage": float64(40)},
+		},
+		"no progress": {
+			Metadata: map[string]interface{}{"createTime": "2024-01-02T15:04:05Z"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, _, ok := OperationMetadataProgress(tc.Metadata)
+			if ok != tc.Ok || got != tc.Expected {
+				t.Errorf("expected %v, %v, got %v, %v", tc.Expected, tc.Ok, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package tpgresource
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// PendingOperationWaiter waits on an operation by the URL it is polled at, such
+// as the pending operations recorded in the state of resources whose
+// creation timed out. Operations reporting a status, such as those of
+// Compute Engine, Cloud SQL and GKE, and operations reporting whether they
+// are done are both supported.
+type PendingOperationWaiter struct {
+	Config    *transport_tpg.Config
+	UserAgent string
+	Url       string
+	Op        map[string]interface{}
+}
+
+func (w *PendingOperationWaiter) hasStatus() bool {
+	_, ok := w.Op["status"].(string)
+	return ok
+}
+
+func (w *PendingOperationWaiter) State() string {
+	if w == nil || w.Op == nil {
+		return "<nil>"
+	}
+	if w.hasStatus() {
+		status, _ := w.Op["status"].(string)
+		return status
+	}
+	done, _ := w.Op["done"].(bool)
+	return fmt.Sprintf("done: %v", done)
+}
+
+func (w *PendingOperationWaiter) Error() error {
+	if w == nil || w.Op == nil || w.Op["error"] == nil {
+		return nil
+	}
+	opError, _ := w.Op["error"].(map[string]interface{})
+	if errors, ok := opError["errors"].([]interface{}); ok {
+		var messages []string
+		for _, e := range errors {
+			if m, ok := e.(map[string]interface{}); ok {
+				messages = append(messages, fmt.Sprintf("%v", m["message"]))
+			}
+		}
+		return fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+	status := &cloudresourcemanager.Status{}
+	if err := Convert(w.Op["error"], status); err != nil {
+		return err
+	}
+	return &CommonOpError{status}
+}
+
+func (w *PendingOperationWaiter) IsRetryable(error) bool {
+	return false
+}
+
+func (w *PendingOperationWaiter) SetOp(op interface{}) error {
+	m, ok := op.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Unable to set operation. Bad type!")
+	}
+	w.Op = m
+	return nil
+}
+
+func (w *PendingOperationWaiter) QueryOp() (interface{}, error) {
+	if w == nil {
+		return nil, fmt.Errorf("Cannot query operation, it's unset or nil.")
+	}
+	op, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+		Config:    w.Config,
+		Method:    "GET",
+		RawURL:    w.Url,
+		UserAgent: w.UserAgent,
+	})
+	if transport_tpg.IsGoogleApiErrorWithCode(err, 404) {
+		// Operations are deleted some time after they finish
+		log.Printf("[DEBUG] Operation %s was not found, assuming it finished", w.Url)
+		if strings.Contains(w.Url, "/compute/") {
+			return map[string]interface{}{"kind": "compute#operation", "status": "DONE"}, nil
+		}
+		return map[string]interface{}{"done": true}, nil
+	}
+	return op, err
+}
+
+func (w *PendingOperationWaiter) Progress() (float64, time.Time, bool) {
+	if w == nil || w.Op == nil {
+		return 0, time.Time{}, false
+	}
+	if !w.hasStatus() {
+		metadata, _ := w.Op["metadata"].(map[string]interface{})
+		return OperationMetadataProgress(metadata)
+	}
+	progress, ok := metadataNumber(w.Op["progress"])
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	for _, k := range []string{"startTime", "insertTime"} {
+		if raw, ok := w.Op[k].(string); ok {
+			if started, err := time.Parse(time.RFC3339, raw); err == nil {
+				return progress / 100, started, true
+			}
+		}
+	}
+	return 0, time.Time{}, false
+}
+
+func (w *PendingOperationWaiter) OpName() string {
+	if w == nil {
+		return "<nil>"
+	}
+	return w.Url
+}
+
+func (w *PendingOperationWaiter) PendingStates() []string {
+	return []string{"done: false", "PENDING", "RUNNING", "ABORTING"}
+}
+
+func (w *PendingOperationWaiter) TargetStates() []string {
+	return []string{"done: true", "DONE"}
+}
+
+// WaitForPendingOperation resumes waiting on an operation that was still
+// running when a resource was last applied. Failed operations are logged but
+// not returned, as reading the resource afterwards tells whether it exists.
+func WaitForPendingOperation(config *transport_tpg.Config, operationUrl, userAgent string, timeout time.Duration) error {
+	w := &PendingOperationWaiter{
+		Config:    config,
+		UserAgent: userAgent,
+		Url:       operationUrl,
+	}
+	log.Printf("[DEBUG] Resuming waiting on pending operation %s", operationUrl)
+	err := OperationWaitWithPolicy(w, "pending operation "+operationUrl, timeout, config.OperationPollingPolicy(""))
+	if err != nil && OperationDone(w) {
+		log.Printf("[WARN] Pending operation %s failed: %s", operationUrl, err)
+		return nil
+	}
+	return err
+}
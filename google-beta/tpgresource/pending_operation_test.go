@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package tpgresource
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPendingOperationWaiter(t *testing.T) {
+	cases := map[string]struct {
+		Op            map[string]interface{}
+		ExpectedDone  bool
+		ExpectedError string
+	}{
+		"running compute operation": {
+			Op:           map[string]interface{}{"kind": "compute#operation", "status": "RUNNING"},
+			ExpectedDone: false,
+		},
+		"failed compute operation": {
+			Op: map[string]interface{}{"kind": "compute#operation", "status": "DONE", "error": map[string]interface{}{
+				"errors": []interface{}{map[string]interface{}{"message": "quota exceeded"}},
+			}},
+			ExpectedDone:  true,
+			ExpectedError: "quota exceeded",
+		},
+		"running sql operation": {
+			Op:           map[string]interface{}{"kind": "sql#operation", "status": "PENDING"},
+			ExpectedDone: false,
+		},
+		"done container operation": {
+			Op:           map[string]interface{}{"name": "operation-123", "status": "DONE"},
+			ExpectedDone: true,
+		},
+		"failed container operation": {
+			Op:            map[string]interface{}{"name": "operation-123", "status": "DONE", "error": map[string]interface{}{"code": 13, "message": "internal"}},
+			ExpectedDone:  true,
+			ExpectedError: "internal",
+		},
+		"running operation": {
+			Op:           map[string]interface{}{"name": "operations/123", "done": false},
+			ExpectedDone: false,
+		},
+		"done operation": {
+			Op:           map[string]interface{}{"name": "operations/123", "done": true},
+			ExpectedDone: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			w := &PendingOperationWaiter{}
+			if err := w.SetOp(tc.Op); err != nil {
+				t.Fatal(err)
+			}
+			if done := OperationDone(w); done != tc.ExpectedDone {
+				t.Errorf("expected done to be %t, got %t for state %q", tc.ExpectedDone, done, w.State())
+			}
+			err := w.Error()
+			if tc.ExpectedError == "" && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+			if tc.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), tc.ExpectedError)) {
+				t.Errorf("expected an error containing %q, got %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
@@ -379,7 +379,13 @@ func ServiceAccountFQN(serviceAccount string, d TerraformResourceData, config *t
 		return "", err
 	}
 
-	return fmt.Sprintf("projects/-/serviceAccounts/%s@%s.iam.gserviceaccount.com", serviceAccount, project), nil
+	return "projects/-/serviceAccounts/" + ServiceAccountEmail(serviceAccount, project), nil
+}
+
+// ServiceAccountEmail returns the email of the service account with the given
+// account id in project.
+func ServiceAccountEmail(accountId, project string) string {
+	return fmt.Sprintf("%s@%s.iam.gserviceaccount.com", accountId, project)
 }
 
 func PaginatedListRequest(project, baseUrl, userAgent string, config *transport_tpg.Config, flattener func(map[string]interface{}) []interface{}) ([]interface{}, error) {
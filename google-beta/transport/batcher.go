@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/errwrap"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const DefaultBatchSendIntervalSec = 3
@@ -55,6 +56,10 @@ type (
 		// ID for debugging request. This should be specific to a single request
 		// (i.e. per Terraform resource)
 		DebugId string
+
+		// Context is the context of the resource CRUD call making the request.
+		// Sending a batch is traced as part of the context of its first request.
+		Context context.Context
 	}
 
 	// BatcherCombineFunc is a function type for combine existing batches and additional batch data
@@ -86,7 +91,8 @@ type startedBatch struct {
 
 	subscribers []batchSubscriber
 
-	timer *time.Timer
+	timer   *time.Timer
+	started time.Time
 }
 
 // batchSubscriber contains information required for a single request for a startedBatch.
@@ -102,6 +108,19 @@ type batchSubscriber struct {
 type BatchingConfig struct {
 	SendAfter      time.Duration
 	EnableBatching bool
+
+	// MaxBatchSize is the number of requests after which a batch is sent
+	// without waiting for SendAfter. Zero means batches are unbounded.
+	MaxBatchSize int
+
+	// MaxWait, if set, makes SendAfter restart whenever a request joins a
+	// batch, so a batch is sent once no request has joined it for SendAfter.
+	// A batch is never held for longer than MaxWait after its first request.
+	MaxWait time.Duration
+
+	// BatchTypes holds settings for individual batch types, such as
+	// BatchTypeIam, that override the settings above.
+	BatchTypes map[string]*BatchingConfig
 }
 
 // Initializes a new batcher.
@@ -217,7 +236,12 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 
 	// If batch already exists, combine this request into existing request.
 	if batch, ok := b.batches[batchKey]; ok {
-		return batch.addRequest(newRequest)
+		respCh, err := batch.addRequest(newRequest)
+		if err != nil {
+			return nil, err
+		}
+		b.rescheduleLocked(batch)
+		return respCh, nil
 	}
 
 	// Batch doesn't exist for given batch key - create a new batch.
@@ -239,9 +263,11 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 			CombineF:     newRequest.CombineF,
 			SendF:        newRequest.SendF,
 			DebugId:      fmt.Sprintf("Combined batch for started batch %q", batchKey),
+			Context:      newRequest.Context,
 		},
 		batchKey:    batchKey,
 		subscribers: []batchSubscriber{sub},
+		started:     time.Now(),
 	}
 
 	// Start a timer to send the request
@@ -256,17 +282,50 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 		}
 	})
 
+	b.rescheduleLocked(b.batches[batchKey])
 	return respCh, nil
 }
 
+// rescheduleLocked sends batch right away if it has reached MaxBatchSize, or
+// restarts its timer if MaxWait is set. b must be locked.
+func (b *RequestBatcher) rescheduleLocked(batch *startedBatch) {
+	if b.MaxBatchSize > 0 && len(batch.subscribers) >= b.MaxBatchSize {
+		// If the timer has already fired, it is waiting to pop the batch.
+		if batch.timer.Stop() {
+			log.Printf("[DEBUG] Batch %q reached max batch size %d, sending now", batch.batchKey, b.MaxBatchSize)
+			delete(b.batches, batch.batchKey)
+			go b.sendBatchWithSingleRetry(batch.batchKey, batch)
+		}
+		return
+	}
+
+	if b.MaxWait <= 0 {
+		return
+	}
+	wait := b.SendAfter
+	if remaining := time.Until(batch.started.Add(b.MaxWait)); remaining < wait {
+		wait = remaining
+	}
+	if batch.timer.Stop() {
+		batch.timer.Reset(wait)
+	}
+}
+
 func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *startedBatch) {
 	log.Printf("[DEBUG] Sending batch %q combining %d requests)", batchKey, len(batch.subscribers))
+	_, span := StartSpan(batch.Context, "RequestBatcher send",
+		attribute.String("batcher", b.debugId),
+		attribute.String("batch.key", batchKey),
+		attribute.Int("batch.requests", len(batch.subscribers)),
+	)
 	resp := batch.send()
+	defer func() { EndSpan(span, resp.err) }()
 
 	// If the batch failed and combines more than one request, retry each single request.
 	if resp.IsError() && len(batch.subscribers) > 1 {
 		log.Printf("[DEBUG] Batch failed with error: %v", resp.err)
 		log.Printf("[DEBUG] Sending each request in batch separately")
+		span.SetAttributes(attribute.Bool("batch.sent_individually", true))
 		for _, sub := range batch.subscribers {
 			log.Printf("[DEBUG] Retrying single request %q", sub.singleRequest.DebugId)
 			singleResp := sub.singleRequest.send()
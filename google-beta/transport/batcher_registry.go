@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Batch types name the kinds of requests that can be batched. Each batch type
+// gets its own RequestBatcher, and may be configured separately in the
+// provider's `batching` block.
+const (
+	BatchTypeServiceUsage           = "service_usage"
+	BatchTypeIam                    = "iam"
+	BatchTypeComputeProjectMetadata = "compute_project_metadata"
+	BatchTypeStorageBucketIam       = "storage_bucket_iam"
+	BatchTypeDnsRecordSet           = "dns_record_set"
+)
+
+// batchTypeDebugIds are the names batchers are logged with.
+var batchTypeDebugIds = map[string]string{
+	BatchTypeServiceUsage:           "Service Usage",
+	BatchTypeIam:                    "IAM",
+	BatchTypeComputeProjectMetadata: "Compute Project Metadata",
+	BatchTypeStorageBucketIam:       "Storage Bucket IAM",
+	BatchTypeDnsRecordSet:           "DNS Record Set",
+}
+
+// optInBatchTypes are batch types that are only batched if they are enabled in
+// a `batch_type` block, since batching delays every request they make.
+var optInBatchTypes = map[string]bool{
+	BatchTypeComputeProjectMetadata: true,
+	BatchTypeStorageBucketIam:       true,
+	BatchTypeDnsRecordSet:           true,
+}
+
+// BatchTypes returns the names of all batch types, sorted.
+func BatchTypes() []string {
+	types := make([]string, 0, len(batchTypeDebugIds))
+	for batchType := range batchTypeDebugIds {
+		types = append(types, batchType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ForBatchType returns the settings that apply to batchType: the settings for
+// all batch types, overridden by any set for batchType in BatchTypes.
+func (c *BatchingConfig) ForBatchType(batchType string) *BatchingConfig {
+	if c == nil {
+		return nil
+	}
+	bc := &BatchingConfig{
+		SendAfter:      c.SendAfter,
+		EnableBatching: c.EnableBatching && !optInBatchTypes[batchType],
+		MaxBatchSize:   c.MaxBatchSize,
+		MaxWait:        c.MaxWait,
+	}
+	if override, ok := c.BatchTypes[batchType]; ok {
+		bc.EnableBatching = override.EnableBatching
+		if override.SendAfter != 0 {
+			bc.SendAfter = override.SendAfter
+		}
+		if override.MaxBatchSize != 0 {
+			bc.MaxBatchSize = override.MaxBatchSize
+		}
+		if override.MaxWait != 0 {
+			bc.MaxWait = override.MaxWait
+		}
+	}
+	return bc
+}
+
+// BatcherRegistry holds a RequestBatcher per batch type, created the first
+// time the batch type is used.
+type BatcherRegistry struct {
+	sync.Mutex
+
+	ctx      context.Context
+	config   *BatchingConfig
+	batchers map[string]*RequestBatcher
+}
+
+// NewBatcherRegistry returns a registry of batchers that are configured by
+// config and stop when ctx is done.
+func NewBatcherRegistry(ctx context.Context, config *BatchingConfig) *BatcherRegistry {
+	return &BatcherRegistry{
+		ctx:      ctx,
+		config:   config,
+		batchers: make(map[string]*RequestBatcher),
+	}
+}
+
+// Get returns the batcher for batchType.
+func (r *BatcherRegistry) Get(batchType string) *RequestBatcher {
+	r.Lock()
+	defer r.Unlock()
+
+	if batcher, ok := r.batchers[batchType]; ok {
+		return batcher
+	}
+	debugId, ok := batchTypeDebugIds[batchType]
+	if !ok {
+		debugId = batchType
+	}
+	batcher := NewRequestBatcher(debugId, r.ctx, r.config.ForBatchType(batchType))
+	r.batchers[batchType] = batcher
+	return batcher
+}
+
+// expandBatchTypeConfigs returns the settings in the `batch_type` blocks of
+// the provider's `batching` block, keyed by batch type.
+func expandBatchTypeConfigs(v interface{}) (map[string]*BatchingConfig, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 {
+		return nil, nil
+	}
+
+	configs := make(map[string]*BatchingConfig)
+	for _, raw := range ls {
+		if raw == nil {
+			continue
+		}
+		cfgV := raw.(map[string]interface{})
+
+		name := cfgV["name"].(string)
+		if _, ok := batchTypeDebugIds[name]; !ok {
+			return nil, fmt.Errorf("batching: unknown batch_type %q, expected one of %s", name, strings.Join(BatchTypes(), ", "))
+		}
+		if _, ok := configs[name]; ok {
+			return nil, fmt.Errorf("batching: batch_type %q is configured more than once", name)
+		}
+
+		config, err := expandBatchingSettings(cfgV, &BatchingConfig{EnableBatching: true})
+		if err != nil {
+			return nil, fmt.Errorf("batching: batch_type %q: %s", name, err)
+		}
+		configs[name] = config
+	}
+	return configs, nil
+}
+
+// expandBatchingSettings reads the settings shared by the `batching` block
+// and its `batch_type` blocks into config.
+func expandBatchingSettings(cfgV map[string]interface{}, config *BatchingConfig) (*BatchingConfig, error) {
+	if sendAfterV, ok := cfgV["send_after"]; ok && sendAfterV != "" {
+		SendAfter, err := time.ParseDuration(sendAfterV.(string))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse duration from 'send_after' value %q", sendAfterV)
+		}
+		config.SendAfter = SendAfter
+	}
+
+	if enable, ok := cfgV["enable_batching"]; ok {
+		config.EnableBatching = enable.(bool)
+	}
+
+	if maxBatchSizeV, ok := cfgV["max_batch_size"]; ok {
+		config.MaxBatchSize = maxBatchSizeV.(int)
+		if config.MaxBatchSize < 0 {
+			return nil, fmt.Errorf("'max_batch_size' must not be negative, got %d", config.MaxBatchSize)
+		}
+	}
+
+	if maxWaitV, ok := cfgV["max_wait"]; ok && maxWaitV != "" {
+		MaxWait, err := time.ParseDuration(maxWaitV.(string))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse duration from 'max_wait' value %q", maxWaitV)
+		}
+		config.MaxWait = MaxWait
+	}
+
+	return config, nil
+}
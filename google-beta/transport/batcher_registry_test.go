@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExpandProviderBatchingConfig_batchTypes(t *testing.T) {
+	config, err := ExpandProviderBatchingConfig([]interface{}{
+		map[string]interface{}{
+			"send_after":      "5s",
+			"enable_batching": true,
+			"max_batch_size":  50,
+			"batch_type": []interface{}{
+				map[string]interface{}{
+					"name":            BatchTypeDnsRecordSet,
+					"enable_batching": true,
+					"max_wait":        "30s",
+				},
+				map[string]interface{}{
+					"name":            BatchTypeIam,
+					"enable_batching": false,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]BatchingConfig{
+		BatchTypeServiceUsage:           {SendAfter: 5 * time.Second, EnableBatching: true, MaxBatchSize: 50},
+		BatchTypeIam:                    {SendAfter: 5 * time.Second, EnableBatching: false, MaxBatchSize: 50},
+		BatchTypeDnsRecordSet:           {SendAfter: 5 * time.Second, EnableBatching: true, MaxBatchSize: 50, MaxWait: 30 * time.Second},
+		BatchTypeComputeProjectMetadata: {SendAfter: 5 * time.Second, EnableBatching: false, MaxBatchSize: 50},
+	}
+	for batchType, want := range cases {
+		got := config.ForBatchType(batchType)
+		if got.SendAfter != want.SendAfter || got.EnableBatching != want.EnableBatching || got.MaxBatchSize != want.MaxBatchSize || got.MaxWait != want.MaxWait {
+			t.Errorf("expected %s to be configured as %+v, got %+v", batchType, want, *got)
+		}
+	}
+}
+
+func TestExpandProviderBatchingConfig_invalidBatchTypes(t *testing.T) {
+	cases := map[string][]interface{}{
+		"unknown batch type": {
+			map[string]interface{}{"name": "compute_instance"},
+		},
+		"duplicate batch type": {
+			map[string]interface{}{"name": BatchTypeIam},
+			map[string]interface{}{"name": BatchTypeIam},
+		},
+		"invalid max_wait": {
+			map[string]interface{}{"name": BatchTypeIam, "max_wait": "soon"},
+		},
+		"negative max_batch_size": {
+			map[string]interface{}{"name": BatchTypeIam, "max_batch_size": -1},
+		},
+	}
+	for tn, batchTypes := range cases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := ExpandProviderBatchingConfig([]interface{}{
+				map[string]interface{}{"batch_type": batchTypes},
+			})
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestBatcherRegistry_Get(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registry := NewBatcherRegistry(ctx, &BatchingConfig{
+		SendAfter:      time.Second,
+		EnableBatching: true,
+		BatchTypes: map[string]*BatchingConfig{
+			BatchTypeStorageBucketIam: {EnableBatching: true, SendAfter: 2 * time.Second},
+		},
+	})
+
+	iam := registry.Get(BatchTypeIam)
+	if registry.Get(BatchTypeIam) != iam {
+		t.Fatalf("expected the same batcher to be returned for a batch type")
+	}
+	if iam.debugId != "IAM" || !iam.EnableBatching {
+		t.Fatalf("unexpected IAM batcher %q, enabled: %v", iam.debugId, iam.EnableBatching)
+	}
+
+	storage := registry.Get(BatchTypeStorageBucketIam)
+	if storage == iam || storage.SendAfter != 2*time.Second || !storage.EnableBatching {
+		t.Fatalf("expected a separately configured storage bucket IAM batcher, got %+v", storage.BatchingConfig)
+	}
+
+	if registry.Get(BatchTypeDnsRecordSet).EnableBatching {
+		t.Fatalf("expected DNS record set batching to be disabled unless configured")
+	}
+}
@@ -294,3 +294,102 @@ func testBasicCountBatches(t *testing.T, testName string, numBatches int) {
 		}(i)
 	}
 }
+
+func TestRequestBatcher_maxBatchSize(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&BatchingConfig{
+			SendAfter:      time.Minute,
+			EnableBatching: true,
+			MaxBatchSize:   3,
+		})
+
+	testCombine := func(currV interface{}, toAddV interface{}) (interface{}, error) {
+		return currV.(int) + toAddV.(int), nil
+	}
+	testSendBatch := func(name string, body interface{}) (interface{}, error) {
+		return body, nil
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			req := &BatchRequest{
+				DebugId:      fmt.Sprintf("Test Max Batch Size #%d", idx),
+				ResourceName: "testMaxBatchSize",
+				Body:         1,
+				CombineF:     testCombine,
+				SendF:        testSendBatch,
+			}
+
+			// The batch is sent long before SendAfter once it is full.
+			respV, err := testBatcher.SendRequestWithTimeout("testMaxBatchSize", req, 5*time.Second)
+			if err != nil {
+				t.Errorf("got unexpected error %s", err)
+				return
+			}
+			if respV.(int) != 3 {
+				t.Errorf("expected a batch of 3 requests, got %v", respV)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestRequestBatcher_maxWait(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&BatchingConfig{
+			SendAfter:      300 * time.Millisecond,
+			EnableBatching: true,
+			MaxWait:        time.Second,
+		})
+
+	testCombine := func(currV interface{}, toAddV interface{}) (interface{}, error) {
+		return currV.(int) + toAddV.(int), nil
+	}
+	testSendBatch := func(name string, body interface{}) (interface{}, error) {
+		return body, nil
+	}
+
+	// Requests joining every 200ms keep the batch open until MaxWait, although
+	// each arrives after more than SendAfter has passed since the first.
+	var mu sync.Mutex
+	var sizes []int
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+
+			req := &BatchRequest{
+				DebugId:      fmt.Sprintf("Test Max Wait #%d", idx),
+				ResourceName: "testMaxWait",
+				Body:         1,
+				CombineF:     testCombine,
+				SendF:        testSendBatch,
+			}
+			respV, err := testBatcher.SendRequestWithTimeout("testMaxWait", req, 5*time.Second)
+			if err != nil {
+				t.Errorf("got unexpected error %s", err)
+				return
+			}
+			mu.Lock()
+			sizes = append(sizes, respV.(int))
+			mu.Unlock()
+		}(i)
+		time.Sleep(200 * time.Millisecond)
+	}
+	wg.Wait()
+
+	for _, size := range sizes {
+		if size != 4 {
+			t.Fatalf("expected all requests to be sent in one batch, got batch sizes %v", sizes)
+		}
+	}
+}
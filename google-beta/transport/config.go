@@ -67,8 +67,25 @@ import (
 	"google.golang.org/grpc"
 )
 
+const (
+	// FirewallAnalysisLog logs the issues found by firewall rule analysis as
+	// warnings.
+	FirewallAnalysisLog = "log"
+
+	// FirewallAnalysisError fails the plan when firewall rule analysis finds
+	// an issue.
+	FirewallAnalysisError = "error"
+)
+
+// FirewallAnalysisModes lists the accepted values of the provider's
+// `firewall_analysis` field.
+var FirewallAnalysisModes = []string{FirewallAnalysisLog, FirewallAnalysisError}
+
 type ProviderMeta struct {
 	ModuleName string `cty:"module_name"`
+	// ImpersonateServiceAccount is the service account resources of the
+	// module impersonate instead of the provider's identity.
+	ImpersonateServiceAccount string `cty:"impersonate_service_account"`
 }
 
 type Formatter struct {
@@ -162,6 +179,7 @@ type Config struct {
 	DCLConfig
 	AccessToken                               string
 	Credentials                               string
+	ExternalCredentials                       *ExternalCredentialsConfig
 	ImpersonateServiceAccount                 string
 	ImpersonateServiceAccountDelegates        []string
 	Project                                   string
@@ -171,15 +189,25 @@ type Config struct {
 	UniverseDomain                            string
 	Scopes                                    []string
 	BatchingConfig                            *BatchingConfig
+	RetryPolicy                               *RetryPolicy
+	RateLimits                                []RateLimit
+	ResponseCache                             *ResponseCacheConfig
+	UsageMetricsConfig                        *UsageMetricsConfig
+	DryRun                                    *DryRunConfig
+	LogFormat                                 string
+	FirewallAnalysis                          string
 	UserProjectOverride                       bool
 	RequestReason                             string
 	RequestTimeout                            time.Duration
 	DefaultLabels                             map[string]string
 	AddTerraformAttributionLabel              bool
 	TerraformAttributionLabelAdditionStrategy string
-	// PollInterval is passed to resource.StateChangeConf in common_operation.go
-	// It controls the interval at which we poll for successful operations
+	// PollInterval caps the interval at which we poll for successful
+	// operations, see OperationPollingPolicy.
 	PollInterval time.Duration
+	// OperationPolling holds the per-service polling intervals set in the
+	// provider's `operation_polling` block.
+	OperationPolling map[string]OperationPollingOverride
 
 	Client             *http.Client
 	Context            context.Context
@@ -188,6 +216,28 @@ type Config struct {
 
 	tokenSource oauth2.TokenSource
 
+	// RequestContext is set while a resource's CRUD function runs, and is the
+	// context its requests are made with. See ForRequestContext.
+	RequestContext context.Context
+
+	// PendingOperations is set while a resource is created, and records the
+	// operation it waits on.
+	PendingOperations *PendingOperationTracker
+
+	// WrapTransport, if set, wraps the transport of the provider's clients
+	// outside of every transport the provider adds, so it sees the responses
+	// the provider does.
+	WrapTransport func(http.RoundTripper) http.RoundTripper
+
+	// impersonatedConfigs caches the configs returned by
+	// ForImpersonatedServiceAccount, keyed by service account.
+	impersonatedConfigs map[string]*Config
+
+	// rateLimiters enforce RateLimits. They are built once and copied into
+	// the configs of impersonated service accounts, so the limits apply to
+	// the provider's requests as a whole.
+	rateLimiters []basePathLimiter
+
 	AccessApprovalBasePath           string
 	AccessContextManagerBasePath     string
 	ActiveDirectoryBasePath          string
@@ -333,6 +383,8 @@ type Config struct {
 	ContainerAwsBasePath   string
 	ContainerAzureBasePath string
 
+	RequestBatchers            *BatcherRegistry
+	UsageMetrics               *UsageMetrics
 	RequestBatcherServiceUsage *RequestBatcher
 	RequestBatcherIam          *RequestBatcher
 }
@@ -689,6 +741,12 @@ func HandleSDKDefaults(d *schema.ResourceData) error {
 			"CLOUDSDK_CORE_REQUEST_REASON",
 		}, nil))
 	}
+
+	if d.Get("log_format") == "" {
+		d.Set("log_format", MultiEnvDefault([]string{
+			"GOOGLE_LOG_FORMAT",
+		}, nil))
+	}
 	return nil
 }
 
@@ -1438,30 +1496,12 @@ func (c *Config) LoadAndValidate(ctx context.Context) error {
 		return err
 	}
 
-	// 2. Logging Transport - ensure we log HTTP requests to GCP APIs.
-	loggingTransport := logging.NewTransport("Google", client.Transport)
-
-	// 3. Retry Transport - retries common temporary errors
-	// Keep order for wrapping logging so we log each retried request as well.
-	// This value should be used if needed to create shallow copies with additional retry predicates.
-	// See ClientWithAdditionalRetries
-	retryTransport := NewTransportWithDefaultRetries(loggingTransport)
-
-	// 4. Header Transport - outer wrapper to inject additional headers we want to apply
-	// before making requests
-	headerTransport := NewTransportWithHeaders(retryTransport)
-	if c.RequestReason != "" {
-		headerTransport.Set("X-Goog-Request-Reason", c.RequestReason)
-	}
-
-	// Ensure $userProject is set for all HTTP requests using the client if specified by the provider config
-	// See https://cloud.google.com/apis/docs/system-parameters
-	if c.UserProjectOverride && c.BillingProject != "" {
-		headerTransport.Set("X-Goog-User-Project", c.BillingProject)
+	c.UsageMetrics, err = NewUsageMetrics(c.UsageMetricsConfig)
+	if err != nil {
+		return err
 	}
 
-	// Set final transport value.
-	client.Transport = headerTransport
+	client.Transport = c.wrapTransport(client.Transport)
 
 	// This timeout is a timeout per HTTP request, not per logical operation.
 	client.Timeout = c.synchronousTimeout()
@@ -1469,18 +1509,18 @@ func (c *Config) LoadAndValidate(ctx context.Context) error {
 	c.Client = client
 	c.Context = ctx
 	c.Region = GetRegionFromRegionSelfLink(c.Region)
-	c.RequestBatcherServiceUsage = NewRequestBatcher("Service Usage", ctx, c.BatchingConfig)
-	c.RequestBatcherIam = NewRequestBatcher("IAM", ctx, c.BatchingConfig)
-	c.PollInterval = 10 * time.Second
+	c.RequestBatchers = NewBatcherRegistry(ctx, c.BatchingConfig)
+	c.RequestBatcherServiceUsage = c.RequestBatchers.Get(BatchTypeServiceUsage)
+	c.RequestBatcherIam = c.RequestBatchers.Get(BatchTypeIam)
+	if c.PollInterval == 0 {
+		c.PollInterval = DefaultOperationPollMaxInterval
+	}
 
 	// gRPC Logging setup
 	logger := logrus.StandardLogger()
 
 	logrus.SetLevel(logrus.DebugLevel)
-	logrus.SetFormatter(&Formatter{
-		TimestampFormat: "2006/01/02 15:04:05",
-		LogFormat:       "%time% [%lvl%] %msg% \n",
-	})
+	logrus.SetFormatter(NewLogFormatter(c.LogFormat))
 
 	alwaysLoggingDeciderClient := func(ctx context.Context, fullMethodName string) bool { return true }
 	grpc_logrus.ReplaceGrpcLogger(logrus.NewEntry(logger))
@@ -1495,6 +1535,56 @@ func (c *Config) LoadAndValidate(ctx context.Context) error {
 	return nil
 }
 
+// wrapTransport wraps the authenticated transport of a client with the
+// transports every request made by the provider goes through.
+func (c *Config) wrapTransport(base http.RoundTripper) http.RoundTripper {
+	// 2. Dry Run Transport - records mutating requests instead of sending them if enabled
+	// Keep order for wrapping the client so that every other transport sees the synthetic responses.
+	dryRunTransport := NewTransportWithDryRun(base, c.DryRun)
+
+	// 3. Logging Transport - ensure we log HTTP requests to GCP APIs.
+	loggingTransport := NewLoggingTransport(dryRunTransport, c.LogFormat)
+
+	// 4. Rate Limit Transport - throttles requests to services with a configured rate limit
+	// Keep order for wrapping retries so each retried request is throttled as well.
+	if c.rateLimiters == nil {
+		c.rateLimiters = sharedBasePathLimiters(c.RateLimits)
+	}
+	rateLimitTransport := newTransportWithLimiters(loggingTransport, c.rateLimiters)
+
+	// 5. Retry Transport - retries common temporary errors
+	// Keep order for wrapping logging so we log each retried request as well.
+	// This value should be used if needed to create shallow copies with additional retry predicates.
+	// See ClientWithAdditionalRetries
+	retryTransport := NewTransportWithRetryPolicy(rateLimitTransport, c.RetryPolicy)
+
+	// 6. Usage Metrics Transport - counts requests to each API if enabled
+	// Keep order for wrapping retries so retries are counted against the request they retry.
+	usageMetricsTransport := NewTransportWithUsageMetrics(retryTransport, c.UsageMetrics)
+
+	// 7. Response Cache Transport - serves repeated GET requests from memory if enabled
+	// Keep order for wrapping retries so a cache hit skips retrying and throttling entirely.
+	responseCacheTransport := NewTransportWithResponseCache(usageMetricsTransport, c.ResponseCache)
+
+	// 8. Header Transport - outer wrapper to inject additional headers we want to apply
+	// before making requests
+	headerTransport := NewTransportWithHeaders(responseCacheTransport)
+	if c.RequestReason != "" {
+		headerTransport.Set("X-Goog-Request-Reason", c.RequestReason)
+	}
+
+	// Ensure $userProject is set for all HTTP requests using the client if specified by the provider config
+	// See https://cloud.google.com/apis/docs/system-parameters
+	if c.UserProjectOverride && c.BillingProject != "" {
+		headerTransport.Set("X-Goog-User-Project", c.BillingProject)
+	}
+
+	if c.WrapTransport != nil {
+		return c.WrapTransport(headerTransport)
+	}
+	return headerTransport
+}
+
 func ExpandProviderBatchingConfig(v interface{}) (*BatchingConfig, error) {
 	config := &BatchingConfig{
 		SendAfter:      time.Second * DefaultBatchSendIntervalSec,
@@ -1510,16 +1600,14 @@ func ExpandProviderBatchingConfig(v interface{}) (*BatchingConfig, error) {
 	}
 
 	cfgV := ls[0].(map[string]interface{})
-	if sendAfterV, ok := cfgV["send_after"]; ok && sendAfterV != "" {
-		SendAfter, err := time.ParseDuration(sendAfterV.(string))
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse duration from 'send_after' value %q", sendAfterV)
-		}
-		config.SendAfter = SendAfter
+	config, err := expandBatchingSettings(cfgV, config)
+	if err != nil {
+		return nil, err
 	}
 
-	if enable, ok := cfgV["enable_batching"]; ok {
-		config.EnableBatching = enable.(bool)
+	config.BatchTypes, err = expandBatchTypeConfigs(cfgV["batch_type"])
+	if err != nil {
+		return nil, err
 	}
 
 	return config, nil
@@ -1582,11 +1670,26 @@ func (c *Config) logGoogleIdentities() error {
 // Get a TokenSource based on the Google Credentials configured.
 // If initialCredentialsOnly is true, don't follow the impersonation settings and return the initial set of creds.
 func (c *Config) getTokenSource(clientScopes []string, initialCredentialsOnly bool) (oauth2.TokenSource, error) {
-	creds, err := c.GetCredentials(clientScopes, initialCredentialsOnly)
+	key, err := NewTokenSourceCacheKey(c.AccessToken, c.Credentials, c.ExternalCredentials, c.ImpersonateServiceAccount, c.ImpersonateServiceAccountDelegates, clientScopes, c.UniverseDomain, initialCredentialsOnly)
 	if err != nil {
-		return nil, fmt.Errorf("%s", err)
+		return nil, err
 	}
-	return creds.TokenSource, nil
+	if tokenSource := CachedTokenSource(key); tokenSource != nil {
+		return tokenSource, nil
+	}
+
+	build := func() (oauth2.TokenSource, error) {
+		creds, err := c.GetCredentials(clientScopes, initialCredentialsOnly)
+		if err != nil {
+			return nil, fmt.Errorf("%s", err)
+		}
+		return creds.TokenSource, nil
+	}
+	tokenSource, err := build()
+	if err != nil {
+		return nil, err
+	}
+	return CacheTokenSource(key, tokenSource, build), nil
 }
 
 // Methods to create new services from config
@@ -1724,7 +1827,7 @@ func (c *Config) NewSqlAdminClient(userAgent string) *sqladmin.Service {
 func (c *Config) NewPubsubClient(userAgent string) *pubsub.Service {
 	pubsubClientBasePath := RemoveBasePathVersion(c.PubsubBasePath)
 	log.Printf("[INFO] Instantiating Google Pubsub client for path %s", pubsubClientBasePath)
-	wrappedPubsubClient := ClientWithAdditionalRetries(c.Client, PubsubTopicProjectNotReady)
+	wrappedPubsubClient := ClientWithRetryPolicy(c.Client, c.RetryPolicy, PubsubTopicProjectNotReady)
 	clientPubsub, err := pubsub.NewService(c.Context, option.WithHTTPClient(wrappedPubsubClient))
 	if err != nil {
 		log.Printf("[WARN] Error creating client pubsub: %s", err)
@@ -1907,7 +2010,7 @@ func (c *Config) NewSourceRepoClient(userAgent string) *sourcerepo.Service {
 func (c *Config) NewBigQueryClient(userAgent string) *bigquery.Service {
 	bigQueryClientBasePath := c.BigQueryBasePath
 	log.Printf("[INFO] Instantiating Google Cloud BigQuery client for path %s", bigQueryClientBasePath)
-	wrappedBigQueryClient := ClientWithAdditionalRetries(c.Client, IamMemberMissing)
+	wrappedBigQueryClient := ClientWithRetryPolicy(c.Client, c.RetryPolicy, IamMemberMissing)
 	clientBigQuery, err := bigquery.NewService(c.Context, option.WithHTTPClient(wrappedBigQueryClient))
 	if err != nil {
 		log.Printf("[WARN] Error creating client big query: %s", err)
@@ -2138,6 +2241,33 @@ func (c *Config) GetCredentials(clientScopes []string, initialCredentialsOnly bo
 		}, nil
 	}
 
+	if c.ExternalCredentials != nil {
+		contents, err := c.ExternalCredentials.CredentialsJSON()
+		if err != nil {
+			return googleoauth.Credentials{}, fmt.Errorf("error building external_credentials: %s", err)
+		}
+
+		opts := []option.ClientOption{option.WithCredentialsJSON(contents), option.WithScopes(clientScopes...)}
+		if c.ImpersonateServiceAccount != "" && !initialCredentialsOnly {
+			opts = append(opts, option.ImpersonateCredentials(c.ImpersonateServiceAccount, c.ImpersonateServiceAccountDelegates...))
+		}
+		creds, err := transport.Creds(context.TODO(), opts...)
+		if err != nil {
+			// The credential configuration is built from the provider block, so
+			// report the error against it rather than the generated JSON.
+			return googleoauth.Credentials{}, fmt.Errorf("unable to load credentials from the external_credentials block for audience %q: %s", c.ExternalCredentials.Audience, err)
+		}
+		log.Printf("[INFO] Authenticating using configured 'external_credentials'...")
+		log.Printf("[INFO]   -- Scopes: %s", clientScopes)
+		// Build the credentials from their fields, as copying them would copy
+		// their mutex.
+		return googleoauth.Credentials{
+			ProjectID:   creds.ProjectID,
+			TokenSource: creds.TokenSource,
+			JSON:        creds.JSON,
+		}, nil
+	}
+
 	if c.Credentials != "" {
 		contents, _, err := verify.PathOrContents(c.Credentials)
 		if err != nil {
@@ -2205,7 +2335,7 @@ func (c *Config) GetCredentials(clientScopes []string, initialCredentialsOnly bo
 
 // Remove the `/{{version}}/` from a base path if present.
 func RemoveBasePathVersion(url string) string {
-	re := regexp.MustCompile(`(?P<base>http[s]://.*)(?P<version>/[^/]+?/$)`)
+	re := regexp.MustCompile(`(?P<base>https?://.*)(?P<version>/[^/]+?/$)`)
 	return re.ReplaceAllString(url, "$1/")
 }
 
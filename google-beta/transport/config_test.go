@@ -694,6 +694,8 @@ func TestRemoveBasePathVersion(t *testing.T) {
 		{"https://staging-version.googleapis.com/", "https://staging-version.googleapis.com/"},
 		// For URLs with any parts, the last part is always removed- it's assumed to be the version.
 		{"https://runtimeconfig.googleapis.com/runtimeconfig/", "https://runtimeconfig.googleapis.com/"},
+		// Custom endpoints such as local emulators may not use TLS.
+		{"http://127.0.0.1:8085/pubsub/v1/", "http://127.0.0.1:8085/pubsub/"},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DryRunConfig configures the dry run mode set up by the provider-level
+// `dry_run` block.
+type DryRunConfig struct {
+	// RecordFile is the path mutating requests are recorded to instead of
+	// being sent.
+	RecordFile string
+}
+
+// ExpandProviderDryRunConfig returns the dry run configuration for the
+// provider's `dry_run` block, or nil if the block is not set and requests
+// should be sent as normal.
+func ExpandProviderDryRunConfig(v interface{}) (*DryRunConfig, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return nil, nil
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	config := &DryRunConfig{}
+	if v, ok := cfgV["record_file"]; ok {
+		config.RecordFile = v.(string)
+	}
+	if config.RecordFile == "" {
+		return nil, fmt.Errorf("dry_run: record_file must be set")
+	}
+	return config, nil
+}
+
+// dryRunReadOnlyMethods are custom methods that are sent with POST but do not
+// change anything, so they are sent even in dry run mode.
+var dryRunReadOnlyMethods = []string{
+	":getIamPolicy",
+	":testIamPermissions",
+}
+
+// DryRunRecord is a mutating request recorded in dry run mode. The record
+// file holds one JSON-encoded DryRunRecord per line. Sensitive query
+// parameters and fields of the body are redacted as in JSON logs.
+type DryRunRecord struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// dryRunRecordFileMu serializes writes to record files, which may be shared
+// by the SDK and plugin framework halves of the provider.
+var dryRunRecordFileMu sync.Mutex
+
+// dryRunObjects holds the resources created, updated and deleted by the
+// requests recorded to a record file, keyed by the URL of the resource
+// without its query. Deleted resources are nil. They are shared by the SDK
+// and plugin framework halves of the provider through dryRunObjectsByFile.
+type dryRunObjects struct {
+	mu      sync.Mutex
+	objects map[string]map[string]interface{}
+}
+
+// dryRunObjectsByFile maps record files to their *dryRunObjects.
+var dryRunObjectsByFile sync.Map
+
+// dryRunTransport sends read-only requests, and records mutating requests to
+// a file and answers them with a synthetic response instead of sending them.
+// Reads of resources changed by recorded requests are answered with the
+// resources as the requests left them, so that resources depending on them
+// are planned and recorded too.
+type dryRunTransport struct {
+	internal   http.RoundTripper
+	recordFile string
+	operations int64
+	objects    *dryRunObjects
+}
+
+// NewTransportWithDryRun constructs a dryRunTransport wrapping t. If config is
+// nil, t is returned unchanged.
+func NewTransportWithDryRun(t http.RoundTripper, config *DryRunConfig) http.RoundTripper {
+	if config == nil {
+		return t
+	}
+	objects, _ := dryRunObjectsByFile.LoadOrStore(config.RecordFile, &dryRunObjects{objects: make(map[string]map[string]interface{})})
+	return &dryRunTransport{internal: t, recordFile: config.RecordFile, objects: objects.(*dryRunObjects)}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		if object, ok := t.objects.get(dryRunObjectKey(req.URL)); ok {
+			return t.objectResponse(req, object)
+		}
+	}
+	if !isMutatingRequest(req) {
+		return t.internal.RoundTrip(req)
+	}
+
+	var b bytes.Buffer
+	if req.Body != nil {
+		if _, err := b.ReadFrom(req.Body); err != nil {
+			return nil, fmt.Errorf("dry run: unable to read request body: %s", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(b.Bytes()))
+	}
+
+	record := DryRunRecord{
+		Method: req.Method,
+		URL:    RedactURL(req.URL),
+	}
+	if body := redactBody(b.Bytes(), req.Header.Get("Content-Type")); body != nil {
+		// Media uploads and other non-JSON bodies are recorded as a note of
+		// their size.
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("dry run: unable to encode request body: %s", err)
+		}
+		record.Body = json.RawMessage(encoded)
+	}
+	if err := t.writeRecord(record); err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] Dry run: recorded %s %s instead of sending it", req.Method, RedactURL(req.URL))
+
+	t.objects.apply(req, b.Bytes())
+	return t.syntheticResponse(req, b.Bytes())
+}
+
+// dryRunObjectKey returns the key of the resource at u.
+func dryRunObjectKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + strings.TrimSuffix(u.Path, "/")
+}
+
+// dryRunCreatedKey returns the key of the resource created by a POST to a
+// collection at u with the given body, or "" if it's not known. Resources
+// are named by an `...Id` query parameter, such as `topicId`, or by the name
+// in the body, such as for Compute Engine resources.
+func dryRunCreatedKey(u *url.URL, body map[string]interface{}) string {
+	if strings.Contains(path.Base(u.Path), ":") {
+		// Custom methods such as ":setLabels" don't create a resource.
+		return ""
+	}
+	for k, v := range u.Query() {
+		if strings.HasSuffix(k, "Id") && k != "requestId" && len(v) == 1 && v[0] != "" {
+			return dryRunObjectKey(u) + "/" + v[0]
+		}
+	}
+	if name, ok := body["name"].(string); ok && name != "" {
+		return dryRunObjectKey(u) + "/" + path.Base(name)
+	}
+	return ""
+}
+
+func (o *dryRunObjects) get(key string) (map[string]interface{}, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	object, ok := o.objects[key]
+	return object, ok
+}
+
+// apply records the resource created, updated or deleted by a request that
+// was not sent.
+func (o *dryRunObjects) apply(req *http.Request, reqBody []byte) {
+	var body map[string]interface{}
+	json.Unmarshal(reqBody, &body)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	switch req.Method {
+	case http.MethodDelete:
+		o.objects[dryRunObjectKey(req.URL)] = nil
+	case http.MethodPost:
+		key := dryRunCreatedKey(req.URL, body)
+		if key == "" || body == nil {
+			return
+		}
+		object := make(map[string]interface{}, len(body)+1)
+		for k, v := range body {
+			object[k] = v
+		}
+		object["selfLink"] = key
+		o.objects[key] = object
+	case http.MethodPut, http.MethodPatch:
+		if body == nil {
+			return
+		}
+		key := dryRunObjectKey(req.URL)
+		object := o.objects[key]
+		if object == nil || req.Method == http.MethodPut {
+			object = map[string]interface{}{"selfLink": key}
+		}
+		for k, v := range body {
+			object[k] = v
+		}
+		o.objects[key] = object
+	}
+}
+
+// objectResponse answers a read of a resource changed by recorded requests,
+// with a 404 response if it was deleted.
+func (t *dryRunTransport) objectResponse(req *http.Request, object map[string]interface{}) (*http.Response, error) {
+	status := http.StatusOK
+	var res interface{} = object
+	if object == nil {
+		status = http.StatusNotFound
+		res = map[string]interface{}{"error": map[string]interface{}{
+			"code":    status,
+			"message": "The resource was deleted by a request recorded in dry run mode.",
+		}}
+	}
+	log.Printf("[DEBUG] Dry run: answered GET %s with the resource as recorded requests left it", RedactURL(req.URL))
+	return jsonResponse(req, status, res)
+}
+
+func jsonResponse(req *http.Request, status int, res interface{}) (*http.Response, error) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("dry run: unable to encode synthetic response: %s", err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        http.Header{"Content-Type": []string{"application/json; charset=UTF-8"}},
+		Body:          io.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}, nil
+}
+
+func (t *dryRunTransport) writeRecord(record DryRunRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("dry run: unable to encode request: %s", err)
+	}
+
+	dryRunRecordFileMu.Lock()
+	defer dryRunRecordFileMu.Unlock()
+
+	f, err := os.OpenFile(t.recordFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("dry run: unable to open record file: %s", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("dry run: unable to write to record file: %s", err)
+	}
+	return nil
+}
+
+// syntheticResponse returns a successful response to a request that was not
+// sent. The response is the request body, marked as a finished operation so
+// that waiting on it returns immediately: `done` is set for long-running
+// operations, and `status` for Compute operations. The request body is also
+// set as the operation's `response`, for resources that read the created
+// resource from the operation.
+func (t *dryRunTransport) syntheticResponse(req *http.Request, reqBody []byte) (*http.Response, error) {
+	res := make(map[string]interface{})
+	var body map[string]interface{}
+	if err := json.Unmarshal(reqBody, &body); err == nil {
+		for k, v := range body {
+			res[k] = v
+		}
+		res["response"] = body
+	}
+	if _, ok := res["name"]; !ok {
+		res["name"] = fmt.Sprintf("operations/dry-run-%d", atomic.AddInt64(&t.operations, 1))
+	}
+	res["done"] = true
+	res["status"] = "DONE"
+	return jsonResponse(req, http.StatusOK, res)
+}
+
+// isMutatingRequest returns whether req may change a resource, and so is
+// recorded rather than sent in dry run mode.
+func isMutatingRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	for _, method := range dryRunReadOnlyMethods {
+		if strings.HasSuffix(req.URL.Path, method) {
+			return false
+		}
+	}
+	return true
+}
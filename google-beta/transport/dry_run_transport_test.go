@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	compute "google.golang.org/api/compute/v0.beta"
+)
+
+func readDryRunRecords(t *testing.T, path string) []DryRunRecord {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unable to open record file: %v", err)
+	}
+	defer f.Close()
+
+	var records []DryRunRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record DryRunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestDryRunTransport_RecordsMutatingRequests(t *testing.T) {
+	sent := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "dry-run.jsonl")
+	client := &http.Client{Transport: NewTransportWithDryRun(http.DefaultTransport, &DryRunConfig{RecordFile: path})}
+
+	resp, err := client.Get(ts.URL + "/compute/beta/projects/p/zones/z/instances/i")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = client.Post(ts.URL+"/compute/beta/projects/p/zones/z/instances", "application/json", strings.NewReader(`{"name":"i"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/compute/beta/projects/p/zones/z/instances/i", nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = client.Post(ts.URL+"/v1/projects/p:getIamPolicy", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if sent != 2 {
+		t.Fatalf("expected only the GET and getIamPolicy requests to be sent, got %d requests", sent)
+	}
+
+	records := readDryRunRecords(t, path)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", records)
+	}
+	if records[0].Method != http.MethodPost || records[0].URL != ts.URL+"/compute/beta/projects/p/zones/z/instances" || string(records[0].Body) != `{"name":"i"}` {
+		t.Errorf("unexpected record for the POST request: %+v", records[0])
+	}
+	if records[1].Method != http.MethodDelete || records[1].Body != nil {
+		t.Errorf("unexpected record for the DELETE request: %+v", records[1])
+	}
+}
+
+func TestDryRunTransport_ServesRecordedResources(t *testing.T) {
+	sent := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "dry-run.jsonl")
+	client := &http.Client{Transport: NewTransportWithDryRun(http.DefaultTransport, &DryRunConfig{RecordFile: path})}
+	get := func(rawURL string) (int, map[string]interface{}) {
+		resp, err := client.Get(rawURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		var res map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&res)
+		return resp.StatusCode, res
+	}
+
+	// Compute Engine resources are named in the body
+	resp, err := client.Post(ts.URL+"/compute/beta/projects/p/global/networks?requestId=r", "application/json", strings.NewReader(`{"name":"net","description":"a network"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if status, res := get(ts.URL + "/compute/beta/projects/p/global/networks/net?alt=json"); status != http.StatusOK || res["description"] != "a network" {
+		t.Errorf("expected the created network to be read, got %d: %v", status, res)
+	}
+
+	// Other resources are named by a query parameter
+	resp, err = client.Post(ts.URL+"/v1/projects/p/topics?topicId=t", "application/json", strings.NewReader(`{"labels":{"a":"b"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	req, _ := http.NewRequest(http.MethodPatch, ts.URL+"/v1/projects/p/topics/t?updateMask=labels", strings.NewReader(`{"labels":{"a":"c"}}`))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if status, res := get(ts.URL + "/v1/projects/p/topics/t"); status != http.StatusOK || res["labels"].(map[string]interface{})["a"] != "c" {
+		t.Errorf("expected the updated topic to be read, got %d: %v", status, res)
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, ts.URL+"/v1/projects/p/topics/t", nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if status, _ := get(ts.URL + "/v1/projects/p/topics/t"); status != http.StatusNotFound {
+		t.Errorf("expected the deleted topic not to be found, got %d", status)
+	}
+
+	if sent != 0 {
+		t.Errorf("expected reads of recorded resources not to be sent, got %d requests", sent)
+	}
+}
+
+func TestDryRunTransport_RedactsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dry-run.jsonl")
+	client := &http.Client{Transport: NewTransportWithDryRun(http.DefaultTransport, &DryRunConfig{RecordFile: path})}
+
+	resp, err := client.Post("https://sqladmin.googleapis.com/v1/projects/p/instances/i/users?access_token=ya29.secret", "application/json", strings.NewReader(`{"name":"u","password":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	records := readDryRunRecords(t, path)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %+v", records)
+	}
+	if strings.Contains(records[0].URL, "ya29.secret") || strings.Contains(string(records[0].Body), "hunter2") {
+		t.Errorf("expected the record to be redacted, got %+v", records[0])
+	}
+	if !strings.Contains(string(records[0].Body), `"name":"u"`) {
+		t.Errorf("expected other fields to be recorded, got %s", records[0].Body)
+	}
+}
+
+func TestDryRunTransport_SyntheticResponseIsDoneOperation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dry-run.jsonl")
+	client := &http.Client{Transport: NewTransportWithDryRun(http.DefaultTransport, &DryRunConfig{RecordFile: path})}
+
+	resp, err := client.Post("https://compute.googleapis.com/compute/beta/projects/p/global/networks", "application/json", strings.NewReader(`{"name":"net","description":"a network"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a 200 response, got %d", resp.StatusCode)
+	}
+
+	var res map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		t.Fatalf("synthetic response is not valid JSON: %v", err)
+	}
+	if res["name"] != "net" {
+		t.Errorf("expected the request body to be echoed, got %v", res)
+	}
+
+	b, _ := json.Marshal(res)
+	var computeOp compute.Operation
+	if err := json.Unmarshal(b, &computeOp); err != nil || computeOp.Status != "DONE" {
+		t.Errorf("expected a finished Compute operation, got %+v, %v", computeOp, err)
+	}
+	var commonOp cloudresourcemanager.Operation
+	if err := json.Unmarshal(b, &commonOp); err != nil || !commonOp.Done || commonOp.Error != nil {
+		t.Errorf("expected a finished operation, got %+v, %v", commonOp, err)
+	}
+}
+
+func TestExpandProviderDryRunConfig(t *testing.T) {
+	if cfg, err := ExpandProviderDryRunConfig([]interface{}{}); err != nil || cfg != nil {
+		t.Fatalf("expected no config without a block, got %v, %v", cfg, err)
+	}
+
+	cfg, err := ExpandProviderDryRunConfig([]interface{}{map[string]interface{}{"record_file": "dry-run.jsonl"}})
+	if err != nil || cfg.RecordFile != "dry-run.jsonl" {
+		t.Fatalf("unexpected config %v, %v", cfg, err)
+	}
+
+	if _, err := ExpandProviderDryRunConfig([]interface{}{map[string]interface{}{"record_file": ""}}); err == nil {
+		t.Fatalf("expected an error without a record_file")
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+const (
+	DefaultExternalCredentialsSubjectTokenType = "urn:ietf:params:oauth:token-type:jwt"
+
+	// ExternalCredentialsAllowExecutablesEnvVar must be set to 1 for the
+	// Google auth library to run subject token executables.
+	ExternalCredentialsAllowExecutablesEnvVar = "GOOGLE_EXTERNAL_ACCOUNT_ALLOW_EXECUTABLES"
+)
+
+// Bounds enforced by the Google auth library and the IAM Credentials API.
+const (
+	minExternalCredentialsExecutableTimeout = 5 * time.Second
+	maxExternalCredentialsExecutableTimeout = 120 * time.Second
+	minExternalCredentialsTokenLifetime     = 10 * time.Minute
+	maxExternalCredentialsTokenLifetime     = 12 * time.Hour
+)
+
+var (
+	externalCredentialsAudienceRegex                       = regexp.MustCompile(`^//iam\.[^/]+/(projects/[^/]+/locations/global/workloadIdentityPools/[^/]+|locations/global/workforcePools/[^/]+)/providers/[^/]+$`)
+	externalCredentialsServiceAccountImpersonationUrlRegex = regexp.MustCompile(`^https://iamcredentials\.[^/]+/v1/projects/-/serviceAccounts/[^/]+:generateAccessToken$`)
+)
+
+// ExternalCredentialsConfig configures workload identity federation set up by
+// the provider-level `external_credentials` block.
+type ExternalCredentialsConfig struct {
+	// Audience is the full resource name of the workload identity pool
+	// provider, or of the workforce pool provider.
+	Audience         string
+	SubjectTokenType string
+
+	// Exactly one of SubjectTokenFile, SubjectTokenUrl and
+	// SubjectTokenExecutable is set.
+	SubjectTokenFile       string
+	SubjectTokenUrl        string
+	SubjectTokenExecutable string
+
+	// SubjectTokenHeaders are sent with the request to SubjectTokenUrl.
+	SubjectTokenHeaders map[string]string
+	// SubjectTokenFieldName is set if the subject token file or URL response
+	// is a JSON object, and is the field holding the token.
+	SubjectTokenFieldName string
+	// ExecutableTimeout is zero if the auth library's default should be used.
+	ExecutableTimeout time.Duration
+
+	ServiceAccountImpersonationUrl string
+	// TokenLifetime is zero if the IAM Credentials API's default should be
+	// used.
+	TokenLifetime time.Duration
+}
+
+// ExpandProviderExternalCredentialsConfig returns the configuration for the
+// provider's `external_credentials` block, or nil if the block is not set.
+func ExpandProviderExternalCredentialsConfig(v interface{}) (*ExternalCredentialsConfig, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return nil, nil
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	stringValue := func(k string) string {
+		s, _ := cfgV[k].(string)
+		return s
+	}
+	config := &ExternalCredentialsConfig{
+		Audience:                       stringValue("audience"),
+		SubjectTokenType:               stringValue("subject_token_type"),
+		SubjectTokenFile:               stringValue("subject_token_file"),
+		SubjectTokenUrl:                stringValue("subject_token_url"),
+		SubjectTokenExecutable:         stringValue("subject_token_executable"),
+		SubjectTokenFieldName:          stringValue("subject_token_field_name"),
+		ServiceAccountImpersonationUrl: stringValue("service_account_impersonation_url"),
+	}
+	if config.SubjectTokenType == "" {
+		config.SubjectTokenType = DefaultExternalCredentialsSubjectTokenType
+	}
+	if headers, ok := cfgV["subject_token_headers"].(map[string]interface{}); ok && len(headers) > 0 {
+		config.SubjectTokenHeaders = make(map[string]string, len(headers))
+		for k, v := range headers {
+			config.SubjectTokenHeaders[k] = v.(string)
+		}
+	}
+
+	if err := ValidateExternalCredentialsAudience(config.Audience); err != nil {
+		return nil, fmt.Errorf("external_credentials: %s", err)
+	}
+
+	sources := 0
+	for _, s := range []string{config.SubjectTokenFile, config.SubjectTokenUrl, config.SubjectTokenExecutable} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf("external_credentials: exactly one of subject_token_file, subject_token_url and subject_token_executable must be set")
+	}
+	if len(config.SubjectTokenHeaders) > 0 && config.SubjectTokenUrl == "" {
+		return nil, fmt.Errorf("external_credentials: subject_token_headers can only be set with subject_token_url")
+	}
+	if config.SubjectTokenFieldName != "" && config.SubjectTokenExecutable != "" {
+		return nil, fmt.Errorf("external_credentials: subject_token_field_name cannot be set with subject_token_executable, as executables must output the token in the format documented for executable-sourced credentials")
+	}
+
+	if v := stringValue("executable_timeout"); v != "" {
+		if config.SubjectTokenExecutable == "" {
+			return nil, fmt.Errorf("external_credentials: executable_timeout can only be set with subject_token_executable")
+		}
+		if err := ValidateExternalCredentialsExecutableTimeout(v); err != nil {
+			return nil, fmt.Errorf("external_credentials: %s", err)
+		}
+		config.ExecutableTimeout, _ = time.ParseDuration(v)
+	}
+	if config.SubjectTokenExecutable != "" && os.Getenv(ExternalCredentialsAllowExecutablesEnvVar) != "1" {
+		return nil, fmt.Errorf("external_credentials: subject_token_executable is only run if the %s environment variable is set to 1", ExternalCredentialsAllowExecutablesEnvVar)
+	}
+
+	if config.ServiceAccountImpersonationUrl != "" {
+		if err := ValidateExternalCredentialsServiceAccountImpersonationUrl(config.ServiceAccountImpersonationUrl); err != nil {
+			return nil, fmt.Errorf("external_credentials: %s", err)
+		}
+	}
+	if v := stringValue("token_lifetime"); v != "" {
+		if config.ServiceAccountImpersonationUrl == "" {
+			return nil, fmt.Errorf("external_credentials: token_lifetime can only be set with service_account_impersonation_url")
+		}
+		if err := ValidateExternalCredentialsTokenLifetime(v); err != nil {
+			return nil, fmt.Errorf("external_credentials: %s", err)
+		}
+		config.TokenLifetime, _ = time.ParseDuration(v)
+	}
+
+	return config, nil
+}
+
+// ValidateExternalCredentialsAudience checks that an audience is the full
+// resource name of a workload identity pool provider or a workforce pool
+// provider.
+func ValidateExternalCredentialsAudience(v string) error {
+	if !externalCredentialsAudienceRegex.MatchString(v) {
+		return fmt.Errorf("audience %q is not the full resource name of a workload identity pool provider or a workforce pool provider. Expected the format \"//iam.googleapis.com/projects/{project_number}/locations/global/workloadIdentityPools/{pool}/providers/{provider}\" or \"//iam.googleapis.com/locations/global/workforcePools/{pool}/providers/{provider}\"", v)
+	}
+	return nil
+}
+
+// ValidateExternalCredentialsServiceAccountImpersonationUrl checks that a URL
+// is the generateAccessToken URL of a service account.
+func ValidateExternalCredentialsServiceAccountImpersonationUrl(v string) error {
+	if !externalCredentialsServiceAccountImpersonationUrlRegex.MatchString(v) {
+		return fmt.Errorf("service_account_impersonation_url %q is not a generateAccessToken URL. Expected the format \"https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/{email}:generateAccessToken\"", v)
+	}
+	return nil
+}
+
+// ValidateExternalCredentialsExecutableTimeout checks that a duration is
+// within the bounds the auth library allows for subject token executables.
+func ValidateExternalCredentialsExecutableTimeout(v string) error {
+	return validateDurationBetween("executable_timeout", v, minExternalCredentialsExecutableTimeout, maxExternalCredentialsExecutableTimeout)
+}
+
+// ValidateExternalCredentialsTokenLifetime checks that a duration is within
+// the bounds the IAM Credentials API allows for access tokens.
+func ValidateExternalCredentialsTokenLifetime(v string) error {
+	return validateDurationBetween("token_lifetime", v, minExternalCredentialsTokenLifetime, maxExternalCredentialsTokenLifetime)
+}
+
+func validateDurationBetween(k, v string, min, max time.Duration) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s %q is not a duration: %s", k, v, err)
+	}
+	if d < min || d > max {
+		return fmt.Errorf("%s %q must be between %s and %s", k, v, min, max)
+	}
+	return nil
+}
+
+// CredentialsJSON returns the external account credential configuration for
+// the config, in the format of the files generated by
+// `gcloud iam workload-identity-pools create-cred-config`.
+func (c *ExternalCredentialsConfig) CredentialsJSON() ([]byte, error) {
+	source := map[string]interface{}{}
+	switch {
+	case c.SubjectTokenFile != "":
+		source["file"] = c.SubjectTokenFile
+	case c.SubjectTokenUrl != "":
+		source["url"] = c.SubjectTokenUrl
+		if len(c.SubjectTokenHeaders) > 0 {
+			source["headers"] = c.SubjectTokenHeaders
+		}
+	case c.SubjectTokenExecutable != "":
+		executable := map[string]interface{}{
+			"command": c.SubjectTokenExecutable,
+		}
+		if c.ExecutableTimeout != 0 {
+			executable["timeout_millis"] = c.ExecutableTimeout.Milliseconds()
+		}
+		source["executable"] = executable
+	}
+	if c.SubjectTokenFieldName != "" {
+		source["format"] = map[string]interface{}{
+			"type":                     "json",
+			"subject_token_field_name": c.SubjectTokenFieldName,
+		}
+	}
+
+	creds := map[string]interface{}{
+		"type":               "external_account",
+		"audience":           c.Audience,
+		"subject_token_type": c.SubjectTokenType,
+		"credential_source":  source,
+	}
+	if c.ServiceAccountImpersonationUrl != "" {
+		creds["service_account_impersonation_url"] = c.ServiceAccountImpersonationUrl
+		if c.TokenLifetime != 0 {
+			creds["service_account_impersonation"] = map[string]interface{}{
+				"token_lifetime_seconds": int64(c.TokenLifetime.Seconds()),
+			}
+		}
+	}
+	return json.Marshal(creds)
+}
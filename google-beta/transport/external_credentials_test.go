@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	googleoauth "golang.org/x/oauth2/google"
+)
+
+const testExternalCredentialsAudience = "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/my-pool/providers/my-provider"
+
+func TestExpandProviderExternalCredentialsConfig(t *testing.T) {
+	if cfg, err := ExpandProviderExternalCredentialsConfig([]interface{}{}); err != nil || cfg != nil {
+		t.Fatalf("expected no config without a block, got %v, %v", cfg, err)
+	}
+
+	cfg, err := ExpandProviderExternalCredentialsConfig([]interface{}{map[string]interface{}{
+		"audience":                          testExternalCredentialsAudience,
+		"subject_token_url":                 "https://token.actions.githubusercontent.com?audience=google",
+		"subject_token_headers":             map[string]interface{}{"Authorization": "bearer token"},
+		"subject_token_field_name":          "value",
+		"service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/sa@my-project.iam.gserviceaccount.com:generateAccessToken",
+		"token_lifetime":                    "2h",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.SubjectTokenType != DefaultExternalCredentialsSubjectTokenType {
+		t.Errorf("expected the default subject token type, got %q", cfg.SubjectTokenType)
+	}
+	if cfg.SubjectTokenHeaders["Authorization"] != "bearer token" {
+		t.Errorf("expected the subject token headers to be set, got %v", cfg.SubjectTokenHeaders)
+	}
+	if cfg.TokenLifetime != 2*time.Hour {
+		t.Errorf("expected a token lifetime of 2h, got %s", cfg.TokenLifetime)
+	}
+}
+
+func TestExpandProviderExternalCredentialsConfig_invalid(t *testing.T) {
+	cases := map[string]struct {
+		Config        map[string]interface{}
+		Env           map[string]string
+		ExpectedError string
+	}{
+		"audience is not a provider": {
+			Config: map[string]interface{}{
+				"audience":           "my-pool",
+				"subject_token_file": "/var/run/token",
+			},
+			ExpectedError: "is not the full resource name",
+		},
+		"no subject token source": {
+			Config: map[string]interface{}{
+				"audience": testExternalCredentialsAudience,
+			},
+			ExpectedError: "exactly one of",
+		},
+		"several subject token sources": {
+			Config: map[string]interface{}{
+				"audience":           testExternalCredentialsAudience,
+				"subject_token_file": "/var/run/token",
+				"subject_token_url":  "http://169.254.169.254/token",
+			},
+			ExpectedError: "exactly one of",
+		},
+		"headers without a url": {
+			Config: map[string]interface{}{
+				"audience":              testExternalCredentialsAudience,
+				"subject_token_file":    "/var/run/token",
+				"subject_token_headers": map[string]interface{}{"Metadata": "true"},
+			},
+			ExpectedError: "subject_token_headers can only be set with subject_token_url",
+		},
+		"executables are not allowed": {
+			Config: map[string]interface{}{
+				"audience":                 testExternalCredentialsAudience,
+				"subject_token_executable": "/usr/bin/get-token",
+			},
+			Env:           map[string]string{ExternalCredentialsAllowExecutablesEnvVar: ""},
+			ExpectedError: ExternalCredentialsAllowExecutablesEnvVar,
+		},
+		"executable timeout out of range": {
+			Config: map[string]interface{}{
+				"audience":                 testExternalCredentialsAudience,
+				"subject_token_executable": "/usr/bin/get-token",
+				"executable_timeout":       "5m",
+			},
+			Env:           map[string]string{ExternalCredentialsAllowExecutablesEnvVar: "1"},
+			ExpectedError: "executable_timeout \"5m\" must be between 5s and 2m0s",
+		},
+		"invalid impersonation url": {
+			Config: map[string]interface{}{
+				"audience":                          testExternalCredentialsAudience,
+				"subject_token_file":                "/var/run/token",
+				"service_account_impersonation_url": "sa@my-project.iam.gserviceaccount.com",
+			},
+			ExpectedError: "is not a generateAccessToken URL",
+		},
+		"token lifetime without impersonation": {
+			Config: map[string]interface{}{
+				"audience":           testExternalCredentialsAudience,
+				"subject_token_file": "/var/run/token",
+				"token_lifetime":     "1h",
+			},
+			ExpectedError: "token_lifetime can only be set with service_account_impersonation_url",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			for k, v := range tc.Env {
+				t.Setenv(k, v)
+			}
+			_, err := ExpandProviderExternalCredentialsConfig([]interface{}{tc.Config})
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got none", tc.ExpectedError)
+			}
+			if !strings.Contains(err.Error(), tc.ExpectedError) {
+				t.Fatalf("expected an error containing %q, got %q", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestExternalCredentialsConfig_CredentialsJSON(t *testing.T) {
+	cfg := &ExternalCredentialsConfig{
+		Audience:                       testExternalCredentialsAudience,
+		SubjectTokenType:               DefaultExternalCredentialsSubjectTokenType,
+		SubjectTokenFile:               "/var/run/secrets/token",
+		SubjectTokenFieldName:          "id_token",
+		ServiceAccountImpersonationUrl: "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/sa@my-project.iam.gserviceaccount.com:generateAccessToken",
+		TokenLifetime:                  time.Hour,
+	}
+
+	contents, err := cfg.CredentialsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(contents, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got["type"] != "external_account" || got["audience"] != testExternalCredentialsAudience {
+		t.Errorf("unexpected credentials %s", contents)
+	}
+	source := got["credential_source"].(map[string]interface{})
+	if source["file"] != "/var/run/secrets/token" || source["format"].(map[string]interface{})["subject_token_field_name"] != "id_token" {
+		t.Errorf("unexpected credential source %v", source)
+	}
+	if got["service_account_impersonation"].(map[string]interface{})["token_lifetime_seconds"] != float64(3600) {
+		t.Errorf("unexpected service account impersonation %v", got["service_account_impersonation"])
+	}
+
+	if _, err := googleoauth.CredentialsFromJSON(context.Background(), contents, "https://www.googleapis.com/auth/cloud-platform"); err != nil {
+		t.Errorf("expected the credentials to be accepted by the auth library, got %s", err)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
+)
+
+// impersonatedConfigsMu guards the impersonatedConfigs caches of all configs.
+// Configs are only built on the first use of a service account, so a single
+// lock is not contended.
+var impersonatedConfigsMu sync.Mutex
+
+// ForImpersonatedServiceAccount returns a copy of the config whose clients
+// authenticate as serviceAccount, impersonated by the provider's own identity.
+// If the provider itself impersonates a service account, that account is
+// added to the delegation chain. The copy is cached, so its access tokens are
+// reused by every resource impersonating the same service account.
+//
+// Rate limits are shared with the provider's own clients, while the response
+// cache and request batching are tracked separately for each impersonated
+// service account.
+func (c *Config) ForImpersonatedServiceAccount(serviceAccount string) (*Config, error) {
+	if serviceAccount == "" || serviceAccount == c.ImpersonateServiceAccount {
+		return c, nil
+	}
+
+	impersonatedConfigsMu.Lock()
+	defer impersonatedConfigsMu.Unlock()
+
+	if config, ok := c.impersonatedConfigs[serviceAccount]; ok {
+		return config, nil
+	}
+
+	config := *c
+	config.ImpersonateServiceAccount = serviceAccount
+	config.ImpersonateServiceAccountDelegates = impersonationDelegates(c.ImpersonateServiceAccount, c.ImpersonateServiceAccountDelegates)
+	config.impersonatedConfigs = nil
+
+	tokenSource, err := config.getTokenSource(config.Scopes, false)
+	if err != nil {
+		return nil, fmt.Errorf("error impersonating service account %q: %s", serviceAccount, err)
+	}
+	config.tokenSource = tokenSource
+
+	ctx := config.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cleanCtx := context.WithValue(ctx, oauth2.HTTPClient, cleanhttp.DefaultClient())
+	client, _, err := transport.NewHTTPClient(cleanCtx, option.WithTokenSource(tokenSource))
+	if err != nil {
+		return nil, fmt.Errorf("error impersonating service account %q: %s", serviceAccount, err)
+	}
+	client.Transport = config.wrapTransport(client.Transport)
+	client.Timeout = config.synchronousTimeout()
+	config.Client = client
+
+	config.RequestBatchers = NewBatcherRegistry(ctx, config.BatchingConfig)
+	config.RequestBatcherServiceUsage = config.RequestBatchers.Get(BatchTypeServiceUsage)
+	config.RequestBatcherIam = config.RequestBatchers.Get(BatchTypeIam)
+
+	log.Printf("[INFO] Configured clients impersonating service account %s", serviceAccount)
+
+	if c.impersonatedConfigs == nil {
+		c.impersonatedConfigs = make(map[string]*Config)
+	}
+	c.impersonatedConfigs[serviceAccount] = &config
+	return &config, nil
+}
+
+// impersonationDelegates returns the delegation chain for impersonating a
+// service account from the provider's identity. The chain ends with the
+// provider's impersonated service account, if any.
+func impersonationDelegates(impersonateServiceAccount string, delegates []string) []string {
+	if impersonateServiceAccount == "" {
+		return delegates
+	}
+	chain := make([]string, 0, len(delegates)+1)
+	chain = append(chain, delegates...)
+	return append(chain, impersonateServiceAccount)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestConfigForImpersonatedServiceAccount(t *testing.T) {
+	config := &Config{
+		Credentials:                        TestFakeCredentialsPath,
+		ImpersonateServiceAccount:          "provider@my-project.iam.gserviceaccount.com",
+		ImpersonateServiceAccountDelegates: []string{"delegate@my-project.iam.gserviceaccount.com"},
+		Project:                            "my-gce-project",
+		Region:                             "us-central1",
+		RateLimits: []RateLimit{{
+			Service:           "compute",
+			BasePath:          "https://compute.googleapis.com/compute/beta/",
+			RequestsPerSecond: 1,
+			Burst:             1,
+		}},
+	}
+	ConfigureBasePaths(config)
+	if err := config.LoadAndValidate(context.Background()); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if c, err := config.ForImpersonatedServiceAccount(""); err != nil || c != config {
+		t.Errorf("expected the config itself without a service account, got %p, %v", c, err)
+	}
+	if c, err := config.ForImpersonatedServiceAccount(config.ImpersonateServiceAccount); err != nil || c != config {
+		t.Errorf("expected the config itself for the provider's service account, got %p, %v", c, err)
+	}
+
+	sa := "resource@my-project.iam.gserviceaccount.com"
+	impersonated, err := config.ForImpersonatedServiceAccount(sa)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if impersonated == config || impersonated.Client == config.Client {
+		t.Errorf("expected a config with its own client")
+	}
+	if impersonated.ImpersonateServiceAccount != sa {
+		t.Errorf("expected the config to impersonate %q, got %q", sa, impersonated.ImpersonateServiceAccount)
+	}
+	expectedDelegates := []string{"delegate@my-project.iam.gserviceaccount.com", "provider@my-project.iam.gserviceaccount.com"}
+	if !reflect.DeepEqual(impersonated.ImpersonateServiceAccountDelegates, expectedDelegates) {
+		t.Errorf("expected delegates %v, got %v", expectedDelegates, impersonated.ImpersonateServiceAccountDelegates)
+	}
+	if !reflect.DeepEqual(config.ImpersonateServiceAccountDelegates, expectedDelegates[:1]) {
+		t.Errorf("expected the provider's delegates to be unchanged, got %v", config.ImpersonateServiceAccountDelegates)
+	}
+	if impersonated.Project != config.Project || impersonated.ComputeBasePath != config.ComputeBasePath {
+		t.Errorf("expected the config to keep the provider's settings")
+	}
+
+	if len(impersonated.rateLimiters) != 1 || impersonated.rateLimiters[0].limiter != config.rateLimiters[0].limiter {
+		t.Errorf("expected the config to share the provider's rate limiters")
+	}
+
+	cached, err := config.ForImpersonatedServiceAccount(sa)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if cached != impersonated {
+		t.Errorf("expected the config for %q to be cached", sa)
+	}
+}
+
+func TestImpersonationDelegates(t *testing.T) {
+	if got := impersonationDelegates("", []string{"a"}); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("expected the provider's delegates without a provider service account, got %v", got)
+	}
+	if got := impersonationDelegates("sa", nil); !reflect.DeepEqual(got, []string{"sa"}) {
+		t.Errorf("expected the provider's service account as the only delegate, got %v", got)
+	}
+}
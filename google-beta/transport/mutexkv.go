@@ -3,67 +3,279 @@
 package transport
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// DefaultMutexHoldWarningThreshold is how long a MutexKV lock may be held
+// before a warning is logged, unless HoldWarningThreshold is changed.
+const DefaultMutexHoldWarningThreshold = 10 * time.Minute
+
 // MutexKV is a simple key/value store for arbitrary mutexes. It can be used to
 // serialize changes across arbitrary collaborators that share knowledge of the
 // keys they must serialize on.
 //
 // The initial use case is to let aws_security_group_rule resources serialize
 // their access to individual security groups based on SG ID.
+//
+// MutexKV tracks who holds and who waits for each key, so that a hung apply
+// can be diagnosed from LockTable.
 type MutexKV struct {
-	lock  sync.Mutex
-	store map[string]*sync.RWMutex
+	lock   sync.Mutex
+	store  map[string]*mutexEntry
+	nextId uint64
+
+	// HoldWarningThreshold is how long a lock may be held before a warning is
+	// logged. Zero disables the warning.
+	HoldWarningThreshold time.Duration
+}
+
+// mutexEntry is the mutex for a key, and who holds and waits for it.
+type mutexEntry struct {
+	mutex   sync.RWMutex
+	holders []*lockRecord
+	waiters []*lockRecord
+}
+
+// lockRecord is a holder of, or a waiter for, a lock.
+type lockRecord struct {
+	id    uint64
+	read  bool
+	since time.Time
+	warn  *time.Timer
+}
+
+func (r *lockRecord) mode() string {
+	if r.read {
+		return "read"
+	}
+	return "write"
 }
 
 // Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *MutexKV) Lock(key string) {
+	m.LockInContext(context.Background(), key)
+}
+
+// LockInContext locks the mutex for the given key like Lock, tracing the wait
+// as part of the span in ctx, such as that of a resource CRUD call. Unlike
+// LockWithContext, it waits for the mutex even if ctx is done.
+func (m *MutexKV) LockInContext(ctx context.Context, key string) {
+	log.Printf("[DEBUG] Locking %q", key)
+	_, span := StartSpan(ctx, "MutexKV.Lock", attribute.String("mutex.key", key))
+	m.acquire(context.Background(), key, false)
+	span.End()
+	log.Printf("[DEBUG] Locked %q", key)
+}
+
+// LockWithContext locks the mutex for the given key, giving up if ctx is done
+// first. If the lock was acquired, the caller is responsible for calling
+// Unlock for the same key; otherwise the error of ctx is returned.
+func (m *MutexKV) LockWithContext(ctx context.Context, key string) error {
 	log.Printf("[DEBUG] Locking %q", key)
-	m.get(key).Lock()
+	_, span := StartSpan(ctx, "MutexKV.Lock", attribute.String("mutex.key", key))
+	err := m.acquire(ctx, key, false)
+	EndSpan(span, err)
+	if err != nil {
+		log.Printf("[DEBUG] Gave up waiting to lock %q: %s. Lock table:\n%s", key, err, m.LockTable())
+		return fmt.Errorf("gave up waiting to lock %q: %w", key, err)
+	}
 	log.Printf("[DEBUG] Locked %q", key)
+	return nil
 }
 
 // Unlock the mutex for the given key. Caller must have called Lock for the same key first
 func (m *MutexKV) Unlock(key string) {
 	log.Printf("[DEBUG] Unlocking %q", key)
-	m.get(key).Unlock()
+	m.release(key, false)
 	log.Printf("[DEBUG] Unlocked %q", key)
 }
 
 // Acquires a read-lock on the mutex for the given key. Caller is responsible for calling RUnlock
 // for the same key
 func (m *MutexKV) RLock(key string) {
+	m.RLockInContext(context.Background(), key)
+}
+
+// RLockInContext read-locks the mutex for the given key like RLock, tracing
+// the wait as part of the span in ctx.
+func (m *MutexKV) RLockInContext(ctx context.Context, key string) {
 	log.Printf("[DEBUG] RLocking %q", key)
-	m.get(key).RLock()
+	_, span := StartSpan(ctx, "MutexKV.RLock", attribute.String("mutex.key", key))
+	m.acquire(context.Background(), key, true)
+	span.End()
 	log.Printf("[DEBUG] RLocked %q", key)
 }
 
 // Releases a read-lock on the mutex for the given key. Caller must have called RLock for the same key first
 func (m *MutexKV) RUnlock(key string) {
 	log.Printf("[DEBUG] RUnlocking %q", key)
-	m.get(key).RUnlock()
+	m.release(key, true)
 	log.Printf("[DEBUG] RUnlocked %q", key)
 }
 
+// acquire waits for the mutex for the given key, recording the caller as a
+// waiter until it becomes a holder. If ctx is done first, the caller stops
+// waiting and the mutex is released as soon as it is acquired.
+func (m *MutexKV) acquire(ctx context.Context, key string, read bool) error {
+	entry, waiter := m.addWaiter(key, read)
+	lock, unlock := entry.mutex.Lock, entry.mutex.Unlock
+	if read {
+		lock, unlock = entry.mutex.RLock, entry.mutex.RUnlock
+	}
+
+	if ctx.Done() == nil {
+		lock()
+		m.addHolder(key, entry, waiter)
+		return nil
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		lock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		m.addHolder(key, entry, waiter)
+		return nil
+	case <-ctx.Done():
+		m.lock.Lock()
+		entry.waiters = removeLockRecord(entry.waiters, waiter)
+		m.lock.Unlock()
+		go func() {
+			<-acquired
+			unlock()
+		}()
+		return ctx.Err()
+	}
+}
+
+// release unlocks the mutex for the given key, removing its oldest holder of
+// the same mode.
+func (m *MutexKV) release(key string, read bool) {
+	entry := m.get(key)
+
+	m.lock.Lock()
+	for _, holder := range entry.holders {
+		if holder.read == read {
+			if holder.warn != nil {
+				holder.warn.Stop()
+			}
+			entry.holders = removeLockRecord(entry.holders, holder)
+			break
+		}
+	}
+	m.lock.Unlock()
+
+	if read {
+		entry.mutex.RUnlock()
+	} else {
+		entry.mutex.Unlock()
+	}
+}
+
+func (m *MutexKV) addWaiter(key string, read bool) (*mutexEntry, *lockRecord) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	entry := m.getLocked(key)
+	m.nextId++
+	waiter := &lockRecord{id: m.nextId, read: read, since: time.Now()}
+	entry.waiters = append(entry.waiters, waiter)
+	return entry, waiter
+}
+
+// addHolder moves waiter, which now holds the mutex for the given key, from
+// the waiters to the holders of entry.
+func (m *MutexKV) addHolder(key string, entry *mutexEntry, waiter *lockRecord) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	entry.waiters = removeLockRecord(entry.waiters, waiter)
+	holder := &lockRecord{id: waiter.id, read: waiter.read, since: time.Now()}
+	if m.HoldWarningThreshold > 0 {
+		holder.warn = time.AfterFunc(m.HoldWarningThreshold, func() {
+			m.lock.Lock()
+			waiting := len(entry.waiters)
+			m.lock.Unlock()
+			log.Printf("[WARN] %q has been locked (%s) for over %s, with %d waiting for it", key, holder.mode(), m.HoldWarningThreshold, waiting)
+		})
+	}
+	entry.holders = append(entry.holders, holder)
+}
+
+func removeLockRecord(records []*lockRecord, r *lockRecord) []*lockRecord {
+	for i, record := range records {
+		if record == r {
+			return append(records[:i:i], records[i+1:]...)
+		}
+	}
+	return records
+}
+
+// LockTable describes each key that is locked or waited for: who holds it, who
+// waits for it, and for how long.
+func (m *MutexKV) LockTable() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	keys := make([]string, 0, len(m.store))
+	for key, entry := range m.store {
+		if len(entry.holders) > 0 || len(entry.waiters) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "no locks are held or waited for"
+	}
+	sort.Strings(keys)
+
+	now := time.Now()
+	var b strings.Builder
+	for _, key := range keys {
+		entry := m.store[key]
+		fmt.Fprintf(&b, "%q:\n", key)
+		for _, holder := range entry.holders {
+			fmt.Fprintf(&b, "  held (%s) by #%d for %s\n", holder.mode(), holder.id, now.Sub(holder.since).Round(time.Second))
+		}
+		for _, waiter := range entry.waiters {
+			fmt.Fprintf(&b, "  waited for (%s) by #%d for %s\n", waiter.mode(), waiter.id, now.Sub(waiter.since).Round(time.Second))
+		}
+	}
+	return b.String()
+}
+
 // Returns a mutex for the given key, no guarantee of its lock status
-func (m *MutexKV) get(key string) *sync.RWMutex {
+func (m *MutexKV) get(key string) *mutexEntry {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	mutex, ok := m.store[key]
+	return m.getLocked(key)
+}
+
+func (m *MutexKV) getLocked(key string) *mutexEntry {
+	entry, ok := m.store[key]
 	if !ok {
-		mutex = &sync.RWMutex{}
-		m.store[key] = mutex
+		entry = &mutexEntry{}
+		m.store[key] = entry
 	}
-	return mutex
+	return entry
 }
 
 // Returns a properly initialized MutexKV
 func NewMutexKV() *MutexKV {
 	return &MutexKV{
-		store: make(map[string]*sync.RWMutex),
+		store:                make(map[string]*mutexEntry),
+		HoldWarningThreshold: DefaultMutexHoldWarningThreshold,
 	}
 }
 
@@ -76,3 +288,19 @@ func LockedCall(lockKey string, f func() error) error {
 
 	return f()
 }
+
+// LogMutexStoreOnSIGQUIT logs the lock table of MutexStore when the provider
+// receives SIGQUIT, before exiting with Go's usual goroutine dump.
+func LogMutexStoreOnSIGQUIT() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGQUIT)
+	go func() {
+		<-c
+		log.Printf("[WARN] Received SIGQUIT. Lock table:\n%s", MutexStore.LockTable())
+
+		signal.Reset(syscall.SIGQUIT)
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			p.Signal(syscall.SIGQUIT)
+		}
+	}()
+}
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMutexKV_LockWithContextGivesUp(t *testing.T) {
+	m := NewMutexKV()
+	m.Lock("key")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := m.LockWithContext(ctx, "key"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected to give up waiting for the lock, got %v", err)
+	}
+	if table := m.LockTable(); strings.Contains(table, "waited for") {
+		t.Fatalf("expected no waiters after giving up, got:\n%s", table)
+	}
+
+	m.Unlock("key")
+	if err := m.LockWithContext(context.Background(), "key"); err != nil {
+		t.Fatalf("expected the lock to be released after giving up, got %v", err)
+	}
+	m.Unlock("key")
+}
+
+func TestMutexKV_LockTable(t *testing.T) {
+	m := NewMutexKV()
+	if table := m.LockTable(); table != "no locks are held or waited for" {
+		t.Fatalf("unexpected lock table for an unused MutexKV:\n%s", table)
+	}
+
+	m.RLock("router")
+	m.RLock("router")
+	m.Lock("peering")
+	m.Unlock("peering")
+
+	locked := make(chan struct{})
+	go func() {
+		m.Lock("router")
+		close(locked)
+	}()
+	for !strings.Contains(m.LockTable(), "waited for (write)") {
+		time.Sleep(time.Millisecond)
+	}
+
+	table := m.LockTable()
+	if strings.Contains(table, "peering") {
+		t.Errorf("expected released keys to be left out, got:\n%s", table)
+	}
+	if strings.Count(table, "held (read)") != 2 {
+		t.Errorf("expected 2 read holders, got:\n%s", table)
+	}
+
+	m.RUnlock("router")
+	m.RUnlock("router")
+	<-locked
+	if table := m.LockTable(); !strings.Contains(table, "held (write)") || strings.Contains(table, "read") {
+		t.Errorf("expected only the write holder to remain, got:\n%s", table)
+	}
+	m.Unlock("router")
+}
+
+func TestMutexKV_HoldWarning(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	m := NewMutexKV()
+	m.HoldWarningThreshold = 10 * time.Millisecond
+
+	m.Lock("released")
+	m.Unlock("released")
+	m.Lock("held")
+	time.Sleep(50 * time.Millisecond)
+	m.Unlock("held")
+
+	if !strings.Contains(buf.String(), `[WARN] "held" has been locked (write) for over 10ms`) {
+		t.Errorf("expected a warning for the lock held past the threshold, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), `[WARN] "released"`) {
+		t.Errorf("expected no warning for the lock released in time, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOperationPollInitialInterval is the wait before the second poll
+	// of an operation for services without a built-in initial interval.
+	DefaultOperationPollInitialInterval = 1 * time.Second
+
+	// DefaultOperationPollMaxInterval caps the wait between two polls of an
+	// operation when the provider's `operation_polling` block doesn't set
+	// `max_interval`. It is the interval every operation used to be polled at.
+	DefaultOperationPollMaxInterval = 10 * time.Second
+
+	// DefaultOperationPollMultiplier scales the wait between polls after
+	// every poll that doesn't find the operation done.
+	DefaultOperationPollMultiplier = 2.0
+)
+
+// operationPollInitialIntervals holds the initial poll interval of services
+// whose operations are known to take longer than a few seconds. The keys are
+// the service names accepted by the `operation_polling` block.
+var operationPollInitialIntervals = map[string]time.Duration{
+	"compute":            1 * time.Second,
+	"container":          5 * time.Second,
+	"service_networking": 2 * time.Second,
+	"sql":                2 * time.Second,
+}
+
+// OperationPollingServices returns the service names that can be given a
+// `service` block in the provider's `operation_polling` block.
+func OperationPollingServices() []string {
+	services := make([]string, 0, len(operationPollInitialIntervals))
+	for s := range operationPollInitialIntervals {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// OperationPollingPolicy controls how often a long-running operation is
+// polled. The first poll is sent immediately; the wait before each
+// following poll starts at InitialInterval and grows by Multiplier up to
+// MaxInterval. Waiters that report the operation's progress may shorten or
+// lengthen a wait within the same bounds.
+type OperationPollingPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+
+	// Context is the context of the resource CRUD call waiting on the
+	// operation, which the spans of the wait are children of. It is nil
+	// outside of a CRUD call.
+	Context context.Context
+}
+
+// OperationPollingOverride holds the intervals set by a `service` block of
+// the provider's `operation_polling` block. Zero values are unset.
+type OperationPollingOverride struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// OperationPollingConfig is the expanded `operation_polling` block.
+type OperationPollingConfig struct {
+	// MaxInterval is the ceiling of the wait between polls for all
+	// operations, and is stored as the provider's PollInterval.
+	MaxInterval time.Duration
+
+	// Services holds per-service overrides, keyed by service name.
+	Services map[string]OperationPollingOverride
+}
+
+// ExpandProviderOperationPolling returns the operation polling configuration
+// for the provider's `operation_polling` block, or nil if the block is not
+// set.
+func ExpandProviderOperationPolling(v interface{}) (*OperationPollingConfig, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return nil, nil
+	}
+
+	cfg := &OperationPollingConfig{
+		MaxInterval: DefaultOperationPollMaxInterval,
+	}
+	cfgV := ls[0].(map[string]interface{})
+	if raw, ok := cfgV["max_interval"]; ok && raw != "" {
+		d, err := parseOperationPollingInterval("max_interval", raw.(string))
+		if err != nil {
+			return nil, err
+		}
+		cfg.MaxInterval = d
+	}
+
+	if raw, ok := cfgV["service"]; ok && raw != nil {
+		for _, s := range raw.([]interface{}) {
+			if s == nil {
+				continue
+			}
+			sV := s.(map[string]interface{})
+			name := sV["name"].(string)
+			if _, ok := operationPollInitialIntervals[name]; !ok {
+				return nil, fmt.Errorf("operation_polling: unsupported service %q, expected one of %s", name, strings.Join(OperationPollingServices(), ", "))
+			}
+			if _, ok := cfg.Services[name]; ok {
+				return nil, fmt.Errorf("operation_polling: service %q is configured more than once", name)
+			}
+
+			var o OperationPollingOverride
+			for k, dst := range map[string]*time.Duration{
+				"initial_interval": &o.InitialInterval,
+				"max_interval":     &o.MaxInterval,
+			} {
+				if raw, ok := sV[k]; ok && raw != "" {
+					d, err := parseOperationPollingInterval(k, raw.(string))
+					if err != nil {
+						return nil, err
+					}
+					*dst = d
+				}
+			}
+			if o.InitialInterval != 0 && o.MaxInterval != 0 && o.MaxInterval < o.InitialInterval {
+				return nil, fmt.Errorf("operation_polling: service %q max_interval (%s) must not be less than initial_interval (%s)", name, o.MaxInterval, o.InitialInterval)
+			}
+
+			if cfg.Services == nil {
+				cfg.Services = make(map[string]OperationPollingOverride)
+			}
+			cfg.Services[name] = o
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseOperationPollingInterval(k, raw string) (time.Duration, error) {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse duration from '%s' value %q", k, raw)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("operation_polling: %s must be positive, got %s", k, d)
+	}
+	return d, nil
+}
+
+// OperationPollingPolicy returns the polling policy for operations of the
+// given service. The initial interval comes from the service's override,
+// then the service's built-in interval, then
+// DefaultOperationPollInitialInterval. The maximum interval comes from the
+// service's override, then the provider's PollInterval.
+func (c *Config) OperationPollingPolicy(service string) *OperationPollingPolicy {
+	p := &OperationPollingPolicy{
+		InitialInterval: DefaultOperationPollInitialInterval,
+		MaxInterval:     c.PollInterval,
+		Multiplier:      DefaultOperationPollMultiplier,
+		Context:         c.RequestContext,
+	}
+	if d, ok := operationPollInitialIntervals[service]; ok {
+		p.InitialInterval = d
+	}
+	if o, ok := c.OperationPolling[service]; ok {
+		if o.InitialInterval != 0 {
+			p.InitialInterval = o.InitialInterval
+		}
+		if o.MaxInterval != 0 {
+			p.MaxInterval = o.MaxInterval
+		}
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = DefaultOperationPollMaxInterval
+	}
+	if p.InitialInterval > p.MaxInterval {
+		p.InitialInterval = p.MaxInterval
+	}
+	return p
+}
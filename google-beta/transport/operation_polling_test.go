@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandProviderOperationPolling(t *testing.T) {
+	if cfg, err := ExpandProviderOperationPolling([]interface{}{}); err != nil || cfg != nil {
+		t.Fatalf("expected no config without a block, got %v, %v", cfg, err)
+	}
+
+	cfg, err := ExpandProviderOperationPolling([]interface{}{map[string]interface{}{
+		"max_interval": "30s",
+		"service": []interface{}{
+			map[string]interface{}{
+				"name":             "container",
+				"initial_interval": "15s",
+				"max_interval":     "1m",
+			},
+			map[string]interface{}{
+				"name":             "sql",
+				"initial_interval": "",
+				"max_interval":     "5s",
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.MaxInterval != 30*time.Second {
+		t.Errorf("expected a max interval of 30s, got %s", cfg.MaxInterval)
+	}
+	if got := cfg.Services["container"]; got.InitialInterval != 15*time.Second || got.MaxInterval != time.Minute {
+		t.Errorf("unexpected container override %+v", got)
+	}
+	if got := cfg.Services["sql"]; got.InitialInterval != 0 || got.MaxInterval != 5*time.Second {
+		t.Errorf("unexpected sql override %+v", got)
+	}
+}
+
+func TestExpandProviderOperationPolling_invalid(t *testing.T) {
+	cases := map[string]struct {
+		Config        map[string]interface{}
+		ExpectedError string
+	}{
+		"zero max interval": {
+			Config:        map[string]interface{}{"max_interval": "0s"},
+			ExpectedError: "max_interval must be positive",
+		},
+		"unsupported service": {
+			Config: map[string]interface{}{
+				"service": []interface{}{
+					map[string]interface{}{"name": "storage"},
+				},
+			},
+			ExpectedError: "unsupported service \"storage\"",
+		},
+		"duplicate service": {
+			Config: map[string]interface{}{
+				"service": []interface{}{
+					map[string]interface{}{"name": "compute"},
+					map[string]interface{}{"name": "compute"},
+				},
+			},
+			ExpectedError: "configured more than once",
+		},
+		"max interval below initial interval": {
+			Config: map[string]interface{}{
+				"service": []interface{}{
+					map[string]interface{}{"name": "compute", "initial_interval": "10s", "max_interval": "5s"},
+				},
+			},
+			ExpectedError: "must not be less than initial_interval",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := ExpandProviderOperationPolling([]interface{}{tc.Config})
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got none", tc.ExpectedError)
+			}
+			if !strings.Contains(err.Error(), tc.ExpectedError) {
+				t.Fatalf("expected an error containing %q, got %q", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestConfigOperationPollingPolicy(t *testing.T) {
+	config := &Config{
+		PollInterval: 20 * time.Second,
+		OperationPolling: map[string]OperationPollingOverride{
+			"sql": {InitialInterval: 3 * time.Second, MaxInterval: time.Minute},
+		},
+	}
+
+	cases := map[string]struct {
+		Service         string
+		InitialInterval time.Duration
+		MaxInterval     time.Duration
+	}{
+		"built-in initial interval": {
+			Service:         "container",
+			InitialInterval: 5 * time.Second,
+			MaxInterval:     20 * time.Second,
+		},
+		"override": {
+			Service:         "sql",
+			InitialInterval: 3 * time.Second,
+			MaxInterval:     time.Minute,
+		},
+		"unknown service": {
+			Service:         "storage",
+			InitialInterval: DefaultOperationPollInitialInterval,
+			MaxInterval:     20 * time.Second,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			p := config.OperationPollingPolicy(tc.Service)
+			if p.InitialInterval != tc.InitialInterval || p.MaxInterval != tc.MaxInterval {
+				t.Errorf("expected intervals %s to %s, got %s to %s", tc.InitialInterval, tc.MaxInterval, p.InitialInterval, p.MaxInterval)
+			}
+		})
+	}
+
+	// A poll interval lowered below a service's initial interval, as in
+	// VCR replay, caps it.
+	p := (&Config{PollInterval: 10 * time.Millisecond}).OperationPollingPolicy("container")
+	if p.InitialInterval != 10*time.Millisecond {
+		t.Errorf("expected the initial interval to be capped at 10ms, got %s", p.InitialInterval)
+	}
+}
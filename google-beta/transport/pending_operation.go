@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// PendingOperationTracker records the last long-running operation started
+// while creating a resource, and the ID the resource had while waiting on
+// it. Resources clear their ID when waiting on the operation fails, so the
+// ID is recorded while the operation is polled.
+type PendingOperationTracker struct {
+	resource interface{ Id() string }
+
+	mu  sync.Mutex
+	url string
+	id  string
+}
+
+// NewPendingOperationTracker returns a tracker for the resource being created.
+func NewPendingOperationTracker(resource interface{ Id() string }) *PendingOperationTracker {
+	return &PendingOperationTracker{resource: resource}
+}
+
+// Track records an operation that was started, by the URL it is polled at.
+func (t *PendingOperationTracker) Track(operationUrl string) {
+	if t == nil || operationUrl == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.url = operationUrl
+	t.observeId()
+}
+
+// Observe records the ID of the resource if an operation was started, and is
+// called on each request made while creating the resource.
+func (t *PendingOperationTracker) Observe() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.url != "" {
+		t.observeId()
+	}
+}
+
+func (t *PendingOperationTracker) observeId() {
+	if id := t.resource.Id(); id != "" {
+		t.id = id
+	}
+}
+
+// Pending returns the URL of the last operation started, and the ID of the
+// resource while it was polled. Either is empty if not known.
+func (t *PendingOperationTracker) Pending() (operationUrl, id string) {
+	if t == nil {
+		return "", ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.url, t.id
+}
+
+// PendingOperationUrl returns the URL a pending operation returned by a
+// request to requestUrl is polled at, or "" if res is not a pending
+// operation. Compute Engine operations are polled at their selfLink, and
+// other operations at their name, relative to the base path of requestUrl.
+func PendingOperationUrl(requestUrl string, res map[string]interface{}) string {
+	if kind, _ := res["kind"].(string); strings.HasSuffix(kind, "#operation") {
+		if status, _ := res["status"].(string); status == "DONE" {
+			return ""
+		}
+		selfLink, _ := res["selfLink"].(string)
+		return selfLink
+	}
+
+	name, _ := res["name"].(string)
+	if !strings.HasPrefix(name, "operations/") && !strings.Contains(name, "/operations/") {
+		return ""
+	}
+	if done, _ := res["done"].(bool); done {
+		return ""
+	}
+
+	u, err := url.Parse(requestUrl)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	// Operation names start with the same collection as the resource they
+	// were started for, such as "projects/", or with "operations/"
+	path := strings.TrimPrefix(u.Path, "/")
+	first := strings.SplitN(name, "/", 2)[0] + "/"
+	if i := strings.Index(path, first); i >= 0 && (i == 0 || path[i-1] == '/') {
+		path = path[:i]
+	} else {
+		path = strings.SplitN(path, "/", 2)[0] + "/"
+	}
+	return u.Scheme + "://" + u.Host + "/" + path + name
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"testing"
+)
+
+func TestPendingOperationUrl(t *testing.T) {
+	cases := map[string]struct {
+		RequestUrl string
+		Response   map[string]interface{}
+		Expected   string
+	}{
+		"running compute operation": {
+			RequestUrl: "https://compute.googleapis.com/compute/beta/projects/p/regions/r/addresses",
+			Response:   map[string]interface{}{"kind": "compute#operation", "status": "RUNNING", "selfLink": "https://compute.googleapis.com/compute/beta/projects/p/regions/r/operations/op"},
+			Expected:   "https://compute.googleapis.com/compute/beta/projects/p/regions/r/operations/op",
+		},
+		"finished compute operation": {
+			RequestUrl: "https://compute.googleapis.com/compute/beta/projects/p/regions/r/addresses",
+			Response:   map[string]interface{}{"kind": "compute#operation", "status": "DONE", "selfLink": "https://compute.googleapis.com/compute/beta/projects/p/regions/r/operations/op"},
+		},
+		"running operation named after the resource's parent": {
+			RequestUrl: "https://redis.googleapis.com/v1beta1/projects/p/locations/l/instances?instanceId=i",
+			Response:   map[string]interface{}{"name": "projects/p/locations/l/operations/op"},
+			Expected:   "https://redis.googleapis.com/v1beta1/projects/p/locations/l/operations/op",
+		},
+		"running operation named operations/": {
+			RequestUrl: "https://servicenetworking.googleapis.com/v1/services/s/connections",
+			Response:   map[string]interface{}{"name": "operations/op", "done": false},
+			Expected:   "https://servicenetworking.googleapis.com/v1/operations/op",
+		},
+		"finished operation": {
+			RequestUrl: "https://redis.googleapis.com/v1beta1/projects/p/locations/l/instances?instanceId=i",
+			Response:   map[string]interface{}{"name": "projects/p/locations/l/operations/op", "done": true},
+		},
+		"resource": {
+			RequestUrl: "https://pubsub.googleapis.com/v1/projects/p/topics/t",
+			Response:   map[string]interface{}{"name": "projects/p/topics/t"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if got := PendingOperationUrl(tc.RequestUrl, tc.Response); got != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, got)
+			}
+		})
+	}
+}
+
+type fakeResource struct {
+	id string
+}
+
+func (r *fakeResource) Id() string {
+	return r.id
+}
+
+func TestPendingOperationTracker(t *testing.T) {
+	var nilTracker *PendingOperationTracker
+	nilTracker.Track("https://example.com/operations/op")
+	nilTracker.Observe()
+	if url, id := nilTracker.Pending(); url != "" || id != "" {
+		t.Errorf("expected a nil tracker to record nothing")
+	}
+
+	r := &fakeResource{}
+	tracker := NewPendingOperationTracker(r)
+	r.id = "before"
+	tracker.Observe()
+	if _, id := tracker.Pending(); id != "" {
+		t.Errorf("expected the ID not to be recorded before an operation is started, got %q", id)
+	}
+
+	r.id = ""
+	tracker.Track("https://example.com/operations/op")
+	r.id = "projects/p/topics/t"
+	tracker.Observe()
+	r.id = ""
+	tracker.Observe()
+	if url, id := tracker.Pending(); url != "https://example.com/operations/op" || id != "projects/p/topics/t" {
+		t.Errorf("expected the operation and the ID it was polled with, got %q and %q", url, id)
+	}
+}
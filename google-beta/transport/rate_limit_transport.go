@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit limits the rate of outbound requests sent to a single API. It is
+// configured through the provider-level `rate_limit` block.
+type RateLimit struct {
+	// Service names the API being limited, using the same name as its
+	// `<service>_custom_endpoint` provider field, e.g. "compute" or "iam".
+	Service string
+
+	// BasePath is the resolved base path of the service. Requests whose URL
+	// starts with it are subject to this limit.
+	BasePath string
+
+	// RequestsPerSecond is the sustained request rate allowed for the service.
+	RequestsPerSecond float64
+
+	// Burst is the number of requests that may be sent at once before
+	// RequestsPerSecond applies.
+	Burst int
+}
+
+// ExpandProviderRateLimits returns the rate limits set in the provider's
+// `rate_limit` blocks. basePathFor resolves a service name to its configured
+// base path and reports whether the service is known.
+func ExpandProviderRateLimits(v interface{}, basePathFor func(service string) (string, bool)) ([]RateLimit, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	var limits []RateLimit
+	seen := make(map[string]bool)
+	for _, raw := range v.([]interface{}) {
+		if raw == nil {
+			continue
+		}
+		cfgV := raw.(map[string]interface{})
+
+		limit := RateLimit{
+			Service:           cfgV["service"].(string),
+			RequestsPerSecond: cfgV["requests_per_second"].(float64),
+		}
+		if burst, ok := cfgV["burst"]; ok {
+			limit.Burst = burst.(int)
+		}
+
+		if seen[limit.Service] {
+			return nil, fmt.Errorf("rate_limit: service %q is configured more than once", limit.Service)
+		}
+		seen[limit.Service] = true
+
+		basePath, ok := basePathFor(limit.Service)
+		if !ok {
+			return nil, fmt.Errorf("rate_limit: unknown service %q, expected the name of a `<service>_custom_endpoint` field such as \"compute\"", limit.Service)
+		}
+		limit.BasePath = basePath
+
+		if err := limit.Validate(); err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// Validate checks the rate limit for unusable values and fills in the
+// default burst.
+func (l *RateLimit) Validate() error {
+	if l.RequestsPerSecond <= 0 {
+		return fmt.Errorf("rate_limit: requests_per_second for %q must be positive, got %v", l.Service, l.RequestsPerSecond)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("rate_limit: burst for %q must be non-negative, got %d", l.Service, l.Burst)
+	}
+	if l.Burst == 0 {
+		l.Burst = int(math.Max(1, math.Ceil(l.RequestsPerSecond)))
+	}
+	if l.BasePath == "" {
+		return fmt.Errorf("rate_limit: no base path configured for service %q", l.Service)
+	}
+	return nil
+}
+
+type basePathLimiter struct {
+	service string
+	pattern *regexp.Regexp
+	limiter *rate.Limiter
+}
+
+// rateLimitTransport delays outbound requests so that each rate limited
+// service receives at most its configured rate of requests.
+type rateLimitTransport struct {
+	internal http.RoundTripper
+	limiters []basePathLimiter
+}
+
+var basePathTemplateVarRegex = regexp.MustCompile(`\\\{\\\{[^}]+\\\}\\\}`)
+
+var (
+	sharedRateLimitersMu sync.Mutex
+	// sharedRateLimiters holds the limiters of every rate limit configuration,
+	// keyed by rateLimitsKey. The SDK and plugin framework halves of the
+	// provider are configured from the same provider block, so they share
+	// limiters and the configured rate applies to the provider as a whole.
+	sharedRateLimiters = make(map[string][]basePathLimiter)
+)
+
+// NewTransportWithRateLimits constructs a rateLimitTransport wrapping t. If
+// limits is empty, t is returned unchanged. Transports built for the same
+// limits share their rate.
+func NewTransportWithRateLimits(t http.RoundTripper, limits []RateLimit) http.RoundTripper {
+	return newTransportWithLimiters(t, sharedBasePathLimiters(limits))
+}
+
+// sharedBasePathLimiters returns the limiters for limits, building them on
+// the first use of the configuration.
+func sharedBasePathLimiters(limits []RateLimit) []basePathLimiter {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	sharedRateLimitersMu.Lock()
+	defer sharedRateLimitersMu.Unlock()
+
+	key := rateLimitsKey(limits)
+	if limiters, ok := sharedRateLimiters[key]; ok {
+		return limiters
+	}
+	limiters := newBasePathLimiters(limits)
+	sharedRateLimiters[key] = limiters
+	return limiters
+}
+
+// rateLimitsKey identifies a rate limit configuration regardless of the order
+// of its limits.
+func rateLimitsKey(limits []RateLimit) string {
+	keys := make([]string, 0, len(limits))
+	for _, l := range limits {
+		keys = append(keys, fmt.Sprintf("%q %q %v %d", l.Service, l.BasePath, l.RequestsPerSecond, l.Burst))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
+
+// newBasePathLimiters builds a limiter for each rate limit. Transports built
+// from the same limiters share their rate.
+func newBasePathLimiters(limits []RateLimit) []basePathLimiter {
+	var limiters []basePathLimiter
+	for _, l := range limits {
+		// Base paths such as "https://{{location}}-gkemulticloud.googleapis.com/v1/"
+		// match any value of their template variables.
+		expr := basePathTemplateVarRegex.ReplaceAllString(regexp.QuoteMeta(l.BasePath), `[^/]+`)
+		limiters = append(limiters, basePathLimiter{
+			service: l.Service,
+			pattern: regexp.MustCompile("^" + expr),
+			limiter: rate.NewLimiter(rate.Limit(l.RequestsPerSecond), l.Burst),
+		})
+	}
+
+	// Prefer the most specific base path when several match a request.
+	sort.SliceStable(limiters, func(i, j int) bool {
+		return len(limiters[i].pattern.String()) > len(limiters[j].pattern.String())
+	})
+	return limiters
+}
+
+func newTransportWithLimiters(t http.RoundTripper, limiters []basePathLimiter) http.RoundTripper {
+	if len(limiters) == 0 {
+		return t
+	}
+	return &rateLimitTransport{internal: t, limiters: limiters}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+// It waits for the request's rate limiter, if any, before sending it.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l := t.limiterFor(req); l != nil {
+		start := time.Now()
+		if err := l.limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("rate limit for %s: %w", l.service, err)
+		}
+		if waited := time.Since(start); waited > time.Millisecond {
+			log.Printf("[DEBUG] Rate Limit Transport: waited %s to send %s request for %s", waited, req.Method, l.service)
+		}
+	}
+	return t.internal.RoundTrip(req)
+}
+
+func (t *rateLimitTransport) limiterFor(req *http.Request) *basePathLimiter {
+	if req.URL == nil {
+		return nil
+	}
+	u := *req.URL
+	u.RawQuery = ""
+	rawURL := u.String()
+	for i := range t.limiters {
+		if t.limiters[i].pattern.MatchString(rawURL) {
+			return &t.limiters[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTransport_ThrottlesMatchingRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.Transport = NewTransportWithRateLimits(http.DefaultTransport, []RateLimit{
+		{
+			Service:           "compute",
+			BasePath:          ts.URL + "/compute/v1/",
+			RequestsPerSecond: 10,
+			Burst:             1,
+		},
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(ts.URL + "/compute/v1/projects/p/global/networks?alt=json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+	// The first request uses the burst, the next two wait 100ms each.
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Fatalf("expected throttled requests to take at least 180ms, took %s", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(ts.URL + "/storage/v1/b")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Fatalf("expected requests to other services not to be throttled, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransport_NoLimitsReturnsBaseTransport(t *testing.T) {
+	if rt := NewTransportWithRateLimits(http.DefaultTransport, nil); rt != http.DefaultTransport {
+		t.Fatalf("expected base transport to be returned unchanged, got %T", rt)
+	}
+}
+
+func TestRateLimitTransport_SharesLimitersOfTheSameLimits(t *testing.T) {
+	limits := []RateLimit{
+		{Service: "compute", BasePath: "https://compute.googleapis.com/compute/v1/shared/", RequestsPerSecond: 1, Burst: 1},
+		{Service: "iam", BasePath: "https://iam.googleapis.com/v1/shared/", RequestsPerSecond: 1, Burst: 1},
+	}
+	reordered := []RateLimit{limits[1], limits[0]}
+	a := NewTransportWithRateLimits(http.DefaultTransport, limits).(*rateLimitTransport)
+	b := NewTransportWithRateLimits(http.DefaultTransport, reordered).(*rateLimitTransport)
+	if a.limiters[0].limiter != b.limiters[0].limiter || a.limiters[1].limiter != b.limiters[1].limiter {
+		t.Errorf("expected transports for the same limits to share their limiters")
+	}
+
+	other := []RateLimit{limits[0]}
+	other[0].RequestsPerSecond = 2
+	c := NewTransportWithRateLimits(http.DefaultTransport, other).(*rateLimitTransport)
+	if c.limiters[0].limiter == a.limiters[0].limiter || c.limiters[0].limiter == a.limiters[1].limiter {
+		t.Errorf("expected transports for other limits to have their own limiters")
+	}
+}
+
+func TestRateLimitTransport_LimiterFor(t *testing.T) {
+	rt := NewTransportWithRateLimits(http.DefaultTransport, []RateLimit{
+		{Service: "compute", BasePath: "https://compute.googleapis.com/compute/beta/", RequestsPerSecond: 1, Burst: 1},
+		{Service: "container_aws", BasePath: "https://{{location}}-gkemulticloud.googleapis.com/v1/", RequestsPerSecond: 1, Burst: 1},
+		{Service: "storage", BasePath: "https://storage.googleapis.com/storage/v1/", RequestsPerSecond: 1, Burst: 1},
+		{Service: "storage_upload", BasePath: "https://storage.googleapis.com/storage/v1/b/", RequestsPerSecond: 1, Burst: 1},
+	}).(*rateLimitTransport)
+
+	cases := map[string]string{
+		"https://compute.googleapis.com/compute/beta/projects/p/zones/z/instances?alt=json": "compute",
+		"https://us-east4-gkemulticloud.googleapis.com/v1/projects/p/locations/us-east4":    "container_aws",
+		"https://storage.googleapis.com/storage/v1/b/my-bucket":                             "storage_upload",
+		"https://storage.googleapis.com/storage/v1/projects/p":                              "storage",
+		"https://iam.googleapis.com/v1/projects/p/serviceAccounts":                          "",
+	}
+	for rawURL, expected := range cases {
+		req, err := http.NewRequest("GET", rawURL, nil)
+		if err != nil {
+			t.Fatalf("unable to construct request: %v", err)
+		}
+		l := rt.limiterFor(req)
+		actual := ""
+		if l != nil {
+			actual = l.service
+		}
+		if actual != expected {
+			t.Errorf("expected %q to be limited by %q, got %q", rawURL, expected, actual)
+		}
+	}
+}
+
+func TestExpandProviderRateLimits(t *testing.T) {
+	basePaths := map[string]string{
+		"compute": "https://compute.googleapis.com/compute/beta/",
+		"iam":     "https://iam.googleapis.com/v1/",
+	}
+	basePathFor := func(service string) (string, bool) {
+		bp, ok := basePaths[service]
+		return bp, ok
+	}
+
+	limits, err := ExpandProviderRateLimits([]interface{}{
+		map[string]interface{}{"service": "compute", "requests_per_second": 20.0, "burst": 40},
+		map[string]interface{}{"service": "iam", "requests_per_second": 2.5, "burst": 0},
+	}, basePathFor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(limits) != 2 {
+		t.Fatalf("expected 2 rate limits, got %d", len(limits))
+	}
+	if limits[0].BasePath != basePaths["compute"] || limits[0].Burst != 40 {
+		t.Errorf("unexpected compute rate limit: %#v", limits[0])
+	}
+	// Burst defaults to the per-second rate, rounded up.
+	if limits[1].Burst != 3 {
+		t.Errorf("expected default burst of 3, got %d", limits[1].Burst)
+	}
+
+	errorCases := map[string][]interface{}{
+		"unknown service": {
+			map[string]interface{}{"service": "not_a_service", "requests_per_second": 1.0},
+		},
+		"duplicate service": {
+			map[string]interface{}{"service": "compute", "requests_per_second": 1.0},
+			map[string]interface{}{"service": "compute", "requests_per_second": 2.0},
+		},
+		"non-positive rate": {
+			map[string]interface{}{"service": "compute", "requests_per_second": 0.0},
+		},
+	}
+	for tn, raw := range errorCases {
+		if _, err := ExpandProviderRateLimits(raw, basePathFor); err == nil {
+			t.Errorf("%s: expected error, got none", tn)
+		}
+	}
+}
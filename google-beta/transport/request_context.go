@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ForRequestContext returns a copy of the config whose requests are made with
+// values of ctx, such as the resource recorded by WithResource and the span
+// tracing them. SendRequest, operation waiters, mutexes and batches use ctx
+// itself, while the requests of API clients built from the copy keep their
+// own context and only gain the values.
+func (c *Config) ForRequestContext(ctx context.Context) *Config {
+	config := *c
+	config.RequestContext = ctx
+	if c.Client != nil {
+		client := *c.Client
+		client.Transport = &requestContextTransport{internal: client.Transport, ctx: ctx}
+		config.Client = &client
+	}
+	return &config
+}
+
+// requestContext returns the context requests made with the config start
+// from.
+func (c *Config) requestContext() context.Context {
+	if c == nil || c.RequestContext == nil {
+		return context.Background()
+	}
+	return c.RequestContext
+}
+
+// requestContextTransport adds the values of ctx to the context of each
+// request.
+type requestContextTransport struct {
+	internal http.RoundTripper
+	ctx      context.Context
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *requestContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	internal := t.internal
+	if internal == nil {
+		internal = http.DefaultTransport
+	}
+	ctx := req.Context()
+	if resourceType, id := ResourceFromContext(t.ctx); resourceType != "" {
+		if existing, _ := ResourceFromContext(ctx); existing == "" {
+			ctx = WithResource(ctx, resourceType, id)
+		}
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpan(ctx, trace.SpanFromContext(t.ctx))
+	}
+	return internal.RoundTrip(req.WithContext(ctx))
+}
@@ -0,0 +1,324 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const DefaultResponseCacheMaxEntries = 10000
+
+// ResponseCacheConfig configures the in-memory cache of GET responses set up
+// by the provider-level `response_cache` block.
+type ResponseCacheConfig struct {
+	// MaxEntries bounds the number of cached responses. The least recently
+	// used response is evicted once it is reached.
+	MaxEntries int
+
+	// ETagRevalidation revalidates cached responses that carry an ETag with a
+	// conditional request instead of serving them without contacting the API.
+	ETagRevalidation bool
+}
+
+// ExpandProviderResponseCacheConfig returns the response cache configuration
+// for the provider's `response_cache` block, or nil if the block is not set
+// and responses should not be cached.
+func ExpandProviderResponseCacheConfig(v interface{}) (*ResponseCacheConfig, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 {
+		return nil, nil
+	}
+
+	config := &ResponseCacheConfig{
+		MaxEntries: DefaultResponseCacheMaxEntries,
+	}
+	// An empty block enables the cache with default settings.
+	if ls[0] == nil {
+		return config, nil
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	if v, ok := cfgV["max_entries"]; ok && v.(int) != 0 {
+		config.MaxEntries = v.(int)
+	}
+	if v, ok := cfgV["etag_revalidation"]; ok {
+		config.ETagRevalidation = v.(bool)
+	}
+
+	if config.MaxEntries < 0 {
+		return nil, fmt.Errorf("response_cache: max_entries must be positive, got %d", config.MaxEntries)
+	}
+	return config, nil
+}
+
+type cachedResponse struct {
+	key  string
+	path string
+
+	status     string
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (e *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// responseCacheTransport serves repeated GET requests from memory for the
+// lifetime of the provider process.
+//
+// Any other request, apart from read-only custom methods such as
+// ":getIamPolicy", is treated as a mutation of the resource at its URL path.
+// It evicts cached responses for that resource, its children and its parent
+// collection, and stops responses for the resource and its children from
+// being cached again, since the API may keep changing them while a
+// long-running operation completes. Operations are never cached.
+type responseCacheTransport struct {
+	internal http.RoundTripper
+	config   ResponseCacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// mutated holds the resource paths mutated by this process. Responses for
+	// these paths and their children are never cached.
+	mutated map[string]bool
+	// mutatedCollections holds the parent collections of mutated resources.
+	// Responses for exactly these paths are never cached.
+	mutatedCollections map[string]bool
+}
+
+// NewTransportWithResponseCache constructs a responseCacheTransport wrapping
+// t. If config is nil, t is returned unchanged.
+func NewTransportWithResponseCache(t http.RoundTripper, config *ResponseCacheConfig) http.RoundTripper {
+	if config == nil {
+		return t
+	}
+	return &responseCacheTransport{
+		internal:           t,
+		config:             *config,
+		entries:            make(map[string]*list.Element),
+		lru:                list.New(),
+		mutated:            make(map[string]bool),
+		mutatedCollections: make(map[string]bool),
+	}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *responseCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := cacheResourcePath(req.URL)
+	if req.Method != http.MethodGet && req.Method != http.MethodHead && !isReadOnlyCustomMethod(req.URL) {
+		t.invalidate(path)
+		return t.internal.RoundTrip(req)
+	}
+	if req.Method != http.MethodGet || !t.cacheable(req, path) {
+		return t.internal.RoundTrip(req)
+	}
+
+	key := responseCacheKey(req)
+	entry := t.get(key)
+	if entry != nil && !t.config.ETagRevalidation {
+		log.Printf("[DEBUG] Response Cache: serving cached response for GET %s", req.URL)
+		return entry.response(req), nil
+	}
+
+	outReq := req
+	if etag := etagOf(entry); etag != "" {
+		outReq = req.Clone(req.Context())
+		outReq.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := t.internal.RoundTrip(outReq)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		log.Printf("[DEBUG] Response Cache: revalidated cached response for GET %s", req.URL)
+		return entry.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.put(&cachedResponse{
+		key:        key,
+		path:       path,
+		status:     resp.Status,
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+	})
+	return resp, nil
+}
+
+func (t *responseCacheTransport) cacheable(req *http.Request, path string) bool {
+	if req.Header.Get("Range") != "" || req.Header.Get("Cache-Control") == "no-cache" {
+		return false
+	}
+	if isOperationPath(path) {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.isMutatedLocked(path)
+}
+
+// isMutatedLocked reports whether responses for path may have been changed
+// by a mutation made by this process. t.mu must be held.
+func (t *responseCacheTransport) isMutatedLocked(path string) bool {
+	if t.mutatedCollections[path] {
+		return true
+	}
+	for p := path; p != ""; p = cacheParentPath(p) {
+		if t.mutated[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *responseCacheTransport) get(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	elem, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(elem)
+	return elem.Value.(*cachedResponse)
+}
+
+func (t *responseCacheTransport) put(entry *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// A mutation may have started while the response was in flight.
+	if t.isMutatedLocked(entry.path) {
+		return
+	}
+
+	if elem, ok := t.entries[entry.key]; ok {
+		elem.Value = entry
+		t.lru.MoveToFront(elem)
+		return
+	}
+	t.entries[entry.key] = t.lru.PushFront(entry)
+	for t.config.MaxEntries > 0 && t.lru.Len() > t.config.MaxEntries {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// invalidate evicts cached responses affected by a mutation of the resource
+// at path and records the mutation.
+func (t *responseCacheTransport) invalidate(path string) {
+	parent := cacheParentPath(path)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mutated[path] = true
+	if parent != "" {
+		t.mutatedCollections[parent] = true
+	}
+
+	for elem := t.lru.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*cachedResponse)
+		if entry.path == path || entry.path == parent || strings.HasPrefix(entry.path, path+"/") {
+			log.Printf("[DEBUG] Response Cache: evicting cached response for %s", entry.key)
+			t.lru.Remove(elem)
+			delete(t.entries, entry.key)
+		}
+		elem = next
+	}
+}
+
+func etagOf(entry *cachedResponse) string {
+	if entry == nil {
+		return ""
+	}
+	return entry.header.Get("ETag")
+}
+
+func responseCacheKey(req *http.Request) string {
+	// The billing project changes which project's quota and permissions
+	// apply, so it is part of the key.
+	return req.URL.String() + "|" + req.Header.Get("X-Goog-User-Project")
+}
+
+// cacheResourcePath returns the host and path identifying the resource a
+// request acts on, without any custom method suffix such as ":setIamPolicy".
+func cacheResourcePath(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		if j := strings.Index(path[i:], ":"); j >= 0 {
+			path = path[:i+j]
+		}
+	}
+	return u.Host + path
+}
+
+// readOnlyCustomMethodPrefixes are prefixes of custom methods, such as
+// ":getIamPolicy", that are sent with POST but do not change the resource.
+var readOnlyCustomMethodPrefixes = []string{"get", "list", "search", "test", "batchGet", "lookup", "query", "fetch"}
+
+func isReadOnlyCustomMethod(u *url.URL) bool {
+	if u == nil {
+		return false
+	}
+	segment := u.Path[strings.LastIndex(u.Path, "/")+1:]
+	i := strings.Index(segment, ":")
+	if i < 0 {
+		return false
+	}
+	verb := segment[i+1:]
+	for _, prefix := range readOnlyCustomMethodPrefixes {
+		if strings.HasPrefix(verb, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func cacheParentPath(path string) string {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type responseCacheTestServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	calls map[string]int
+	etag  string
+}
+
+func newResponseCacheTestServer(t *testing.T) *responseCacheTestServer {
+	s := &responseCacheTestServer{calls: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.calls[r.Method+" "+r.URL.Path]++
+		n := s.calls[r.Method+" "+r.URL.Path]
+		etag := s.etag
+		s.mu.Unlock()
+
+		if etag != "" {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		fmt.Fprintf(w, `{"path":%q,"call":%d}`, r.URL.Path, n)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *responseCacheTestServer) callCount(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method+" "+path]
+}
+
+func responseCacheTestDo(t *testing.T, client *http.Client, method, rawURL string) string {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read body: %v", err)
+	}
+	return string(b)
+}
+
+func TestResponseCacheTransport_CachesGets(t *testing.T) {
+	s := newResponseCacheTestServer(t)
+	client := &http.Client{Transport: NewTransportWithResponseCache(http.DefaultTransport, &ResponseCacheConfig{})}
+
+	network := s.URL + "/projects/p/global/networks/n"
+	first := responseCacheTestDo(t, client, "GET", network)
+	second := responseCacheTestDo(t, client, "GET", network)
+	if first != second {
+		t.Fatalf("expected cached response %q, got %q", first, second)
+	}
+	if n := s.callCount("GET", "/projects/p/global/networks/n"); n != 1 {
+		t.Fatalf("expected 1 GET to reach the server, got %d", n)
+	}
+
+	// A different query is a different request.
+	responseCacheTestDo(t, client, "GET", network+"?fields=name")
+	if n := s.callCount("GET", "/projects/p/global/networks/n"); n != 2 {
+		t.Fatalf("expected 2 GETs to reach the server, got %d", n)
+	}
+}
+
+func TestResponseCacheTransport_InvalidatesOnMutation(t *testing.T) {
+	s := newResponseCacheTestServer(t)
+	client := &http.Client{Transport: NewTransportWithResponseCache(http.DefaultTransport, &ResponseCacheConfig{})}
+
+	networks := s.URL + "/projects/p/global/networks"
+	responseCacheTestDo(t, client, "GET", networks)
+	responseCacheTestDo(t, client, "GET", networks+"/n")
+	responseCacheTestDo(t, client, "GET", networks+"/other")
+	responseCacheTestDo(t, client, "PATCH", networks+"/n")
+
+	// The mutated resource and its collection are fetched again, and keep
+	// being fetched while the mutation may still be in progress.
+	responseCacheTestDo(t, client, "GET", networks+"/n")
+	responseCacheTestDo(t, client, "GET", networks+"/n")
+	responseCacheTestDo(t, client, "GET", networks)
+	// Other resources in the collection stay cached.
+	responseCacheTestDo(t, client, "GET", networks+"/other")
+
+	cases := map[string]int{
+		"/projects/p/global/networks":       2,
+		"/projects/p/global/networks/n":     3,
+		"/projects/p/global/networks/other": 1,
+	}
+	for path, want := range cases {
+		if got := s.callCount("GET", path); got != want {
+			t.Errorf("expected %d GETs of %s, got %d", want, path, got)
+		}
+	}
+}
+
+func TestResponseCacheTransport_SkipsOperationsAndReadOnlyMethods(t *testing.T) {
+	s := newResponseCacheTestServer(t)
+	client := &http.Client{Transport: NewTransportWithResponseCache(http.DefaultTransport, &ResponseCacheConfig{})}
+
+	op := s.URL + "/projects/p/global/operations/op-1"
+	responseCacheTestDo(t, client, "GET", op)
+	responseCacheTestDo(t, client, "GET", op)
+	if n := s.callCount("GET", "/projects/p/global/operations/op-1"); n != 2 {
+		t.Fatalf("expected operations to never be cached, got %d GETs", n)
+	}
+
+	project := s.URL + "/v1/projects/p"
+	responseCacheTestDo(t, client, "GET", project)
+	responseCacheTestDo(t, client, "POST", project+":getIamPolicy")
+	responseCacheTestDo(t, client, "GET", project)
+	if n := s.callCount("GET", "/v1/projects/p"); n != 1 {
+		t.Fatalf("expected getIamPolicy not to invalidate the project, got %d GETs", n)
+	}
+
+	responseCacheTestDo(t, client, "POST", project+":setIamPolicy")
+	responseCacheTestDo(t, client, "GET", project)
+	if n := s.callCount("GET", "/v1/projects/p"); n != 2 {
+		t.Fatalf("expected setIamPolicy to invalidate the project, got %d GETs", n)
+	}
+}
+
+func TestResponseCacheTransport_ETagRevalidation(t *testing.T) {
+	s := newResponseCacheTestServer(t)
+	s.etag = `"v1"`
+	client := &http.Client{Transport: NewTransportWithResponseCache(http.DefaultTransport, &ResponseCacheConfig{ETagRevalidation: true})}
+
+	bucket := s.URL + "/storage/v1/b/bucket"
+	first := responseCacheTestDo(t, client, "GET", bucket)
+	second := responseCacheTestDo(t, client, "GET", bucket)
+	if first != second || !strings.Contains(second, `"call":1`) {
+		t.Fatalf("expected the revalidated response to be served from cache, got %q", second)
+	}
+	if n := s.callCount("GET", "/storage/v1/b/bucket"); n != 2 {
+		t.Fatalf("expected each GET to be revalidated, got %d GETs", n)
+	}
+}
+
+func TestResponseCacheTransport_EvictsLeastRecentlyUsed(t *testing.T) {
+	s := newResponseCacheTestServer(t)
+	client := &http.Client{Transport: NewTransportWithResponseCache(http.DefaultTransport, &ResponseCacheConfig{MaxEntries: 2})}
+
+	for _, name := range []string{"a", "b", "a", "c", "a", "b"} {
+		responseCacheTestDo(t, client, "GET", s.URL+"/things/"+name)
+	}
+	cases := map[string]int{"/things/a": 1, "/things/b": 2, "/things/c": 1}
+	for path, want := range cases {
+		if got := s.callCount("GET", path); got != want {
+			t.Errorf("expected %d GETs of %s, got %d", want, path, got)
+		}
+	}
+}
+
+func TestCacheResourcePath(t *testing.T) {
+	cases := map[string]string{
+		"https://compute.googleapis.com/compute/v1/projects/p/global/networks/n":   "compute.googleapis.com/compute/v1/projects/p/global/networks/n",
+		"https://iam.googleapis.com/v1/projects/p/serviceAccounts/sa:setIamPolicy": "iam.googleapis.com/v1/projects/p/serviceAccounts/sa",
+		"https://storage.googleapis.com/storage/v1/b/bucket/?alt=json":             "storage.googleapis.com/storage/v1/b/bucket",
+	}
+	for raw, want := range cases {
+		u, _ := url.Parse(raw)
+		if got := cacheResourcePath(u); got != want {
+			t.Errorf("expected %q for %q, got %q", want, raw, got)
+		}
+	}
+}
+
+func TestExpandProviderResponseCacheConfig(t *testing.T) {
+	if cfg, err := ExpandProviderResponseCacheConfig([]interface{}{}); err != nil || cfg != nil {
+		t.Fatalf("expected no cache without a block, got %v, %v", cfg, err)
+	}
+
+	cfg, err := ExpandProviderResponseCacheConfig([]interface{}{nil})
+	if err != nil || cfg == nil || cfg.MaxEntries != DefaultResponseCacheMaxEntries {
+		t.Fatalf("expected an empty block to enable the cache with defaults, got %v, %v", cfg, err)
+	}
+
+	cfg, err = ExpandProviderResponseCacheConfig([]interface{}{map[string]interface{}{
+		"max_entries":       5,
+		"etag_revalidation": true,
+	}})
+	if err != nil || cfg.MaxEntries != 5 || !cfg.ETagRevalidation {
+		t.Fatalf("unexpected config %v, %v", cfg, err)
+	}
+
+	if _, err := ExpandProviderResponseCacheConfig([]interface{}{map[string]interface{}{"max_entries": -1}}); err == nil {
+		t.Fatalf("expected an error for negative max_entries")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"google.golang.org/api/googleapi"
+)
+
+// RetryPolicy controls how long and how often the provider waits between
+// retries of a failed request. It is configured through the provider-level
+// `retry_policy` block and shared by the retry transport, Retry and the IAM
+// read-modify-write loop.
+type RetryPolicy struct {
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps a single wait between retries. It never ends a retry
+	// loop, which MaxAttempts and Timeout do.
+	MaxBackoff time.Duration
+
+	// Multiplier scales the wait after every retry. If zero, waits follow
+	// the Fibonacci progression the retry transport has always used
+	// (0.5s, 1s, 1.5s, 2.5s, 4s, ... for the default InitialBackoff).
+	Multiplier float64
+
+	// Jitter randomizes each wait by up to +/- this fraction of its value.
+	Jitter float64
+
+	// MaxAttempts bounds the total number of attempts, including the first.
+	// Zero means attempts are only bounded by the timeout.
+	MaxAttempts int
+
+	// Timeout bounds the retry transport's loop for requests whose context
+	// has no deadline, and the total wait of the IAM read-modify-write loop.
+	Timeout time.Duration
+
+	// StatusCodeOverrides forces errors with the given HTTP status code to be
+	// treated as retryable (true) or non-retryable (false), regardless of the
+	// registered retry predicates.
+	StatusCodeOverrides map[int]bool
+}
+
+// DefaultRetryPolicy returns the policy used by the retry transport when
+// no `retry_policy` block is configured.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Timeout:        defaultRetryTransportTimeoutSec * time.Second,
+	}
+}
+
+// ExpandProviderRetryPolicy returns the retry policy for the provider's
+// `retry_policy` block, or nil if the block is not set. Unset fields take
+// their value from DefaultRetryPolicy.
+func ExpandProviderRetryPolicy(v interface{}) (*RetryPolicy, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return nil, nil
+	}
+
+	policy := DefaultRetryPolicy()
+	cfgV := ls[0].(map[string]interface{})
+
+	durations := map[string]*time.Duration{
+		"initial_backoff": &policy.InitialBackoff,
+		"max_backoff":     &policy.MaxBackoff,
+		"timeout":         &policy.Timeout,
+	}
+	for k, dst := range durations {
+		raw, ok := cfgV[k]
+		if !ok || raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw.(string))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse duration from '%s' value %q", k, raw)
+		}
+		*dst = d
+	}
+
+	if v, ok := cfgV["multiplier"]; ok {
+		policy.Multiplier = v.(float64)
+	}
+	if v, ok := cfgV["jitter"]; ok {
+		policy.Jitter = v.(float64)
+	}
+	if v, ok := cfgV["max_attempts"]; ok {
+		policy.MaxAttempts = v.(int)
+	}
+
+	if v, ok := cfgV["status_code_override"]; ok && v != nil {
+		for _, raw := range v.([]interface{}) {
+			if raw == nil {
+				continue
+			}
+			o := raw.(map[string]interface{})
+			if policy.StatusCodeOverrides == nil {
+				policy.StatusCodeOverrides = make(map[int]bool)
+			}
+			policy.StatusCodeOverrides[o["code"].(int)] = o["retry"].(bool)
+		}
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate checks the policy for values that would make the retry loops
+// misbehave.
+func (p *RetryPolicy) Validate() error {
+	if p.InitialBackoff <= 0 {
+		return fmt.Errorf("retry_policy: initial_backoff must be positive, got %s", p.InitialBackoff)
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		return fmt.Errorf("retry_policy: max_backoff (%s) must not be less than initial_backoff (%s)", p.MaxBackoff, p.InitialBackoff)
+	}
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return fmt.Errorf("retry_policy: multiplier must be at least 1, got %v", p.Multiplier)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("retry_policy: jitter must be between 0 and 1, got %v", p.Jitter)
+	}
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("retry_policy: max_attempts must be non-negative, got %d", p.MaxAttempts)
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("retry_policy: timeout must be non-negative, got %s", p.Timeout)
+	}
+	for code := range p.StatusCodeOverrides {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("retry_policy: status_code_override code %d is not a valid HTTP status code", code)
+		}
+	}
+	return nil
+}
+
+// StatusCodeOverride reports whether err, or an error it wraps, is a
+// googleapi.Error whose status code has an override in the policy. If so,
+// retry is the configured decision.
+func (p *RetryPolicy) StatusCodeOverride(err error) (retry bool, ok bool) {
+	if p == nil || len(p.StatusCodeOverrides) == 0 || err == nil {
+		return false, false
+	}
+	gerr, isGerr := errwrap.GetType(err, &googleapi.Error{}).(*googleapi.Error)
+	if !isGerr || gerr == nil {
+		return false, false
+	}
+	retry, ok = p.StatusCodeOverrides[gerr.Code]
+	return retry, ok
+}
+
+// NewBackoff starts a new sequence of waits following the policy.
+func (p *RetryPolicy) NewBackoff() *RetryBackoff {
+	return &RetryBackoff{
+		policy:   p,
+		current:  p.InitialBackoff,
+		previous: p.InitialBackoff,
+	}
+}
+
+// RetryBackoff yields successive waits for one retry loop.
+type RetryBackoff struct {
+	policy *RetryPolicy
+
+	current  time.Duration
+	previous time.Duration
+	retries  int
+	capped   bool
+}
+
+// Next returns the wait before the next retry. ok is false once the
+// policy's MaxAttempts has been reached and no further retry should be made.
+func (b *RetryBackoff) Next() (wait time.Duration, ok bool) {
+	b.retries++
+	if b.policy.MaxAttempts > 0 && b.retries >= b.policy.MaxAttempts {
+		return 0, false
+	}
+
+	wait = b.current
+	b.capped = wait >= b.policy.MaxBackoff
+	if b.capped {
+		wait = b.policy.MaxBackoff
+	}
+
+	// Stop growing once capped so long retry loops cannot overflow.
+	if !b.capped {
+		if b.policy.Multiplier == 0 {
+			b.current, b.previous = b.current+b.previous, b.current
+		} else {
+			b.current = time.Duration(float64(b.current) * b.policy.Multiplier)
+		}
+	}
+
+	if b.policy.Jitter > 0 {
+		delta := float64(wait) * b.policy.Jitter
+		wait = time.Duration(float64(wait) - delta + rand.Float64()*2*delta)
+	}
+	return wait, true
+}
+
+// Capped reports whether the last wait returned by Next was limited by the
+// policy's MaxBackoff.
+func (b *RetryBackoff) Capped() bool {
+	return b.capped
+}
@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryBackoff_DefaultIsFibonacci(t *testing.T) {
+	b := DefaultRetryPolicy().NewBackoff()
+	expected := []time.Duration{
+		500 * time.Millisecond,
+		1 * time.Second,
+		1500 * time.Millisecond,
+		2500 * time.Millisecond,
+		4 * time.Second,
+		6500 * time.Millisecond,
+	}
+	for i, want := range expected {
+		got, ok := b.Next()
+		if !ok {
+			t.Fatalf("expected retry %d to be allowed", i)
+		}
+		if got != want {
+			t.Fatalf("retry %d: expected wait %s, got %s", i, want, got)
+		}
+	}
+}
+
+func TestRetryBackoff_MultiplierAndCap(t *testing.T) {
+	b := (&RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	}).NewBackoff()
+	expected := []struct {
+		wait   time.Duration
+		capped bool
+	}{
+		{time.Second, false},
+		{2 * time.Second, false},
+		{4 * time.Second, false},
+		{5 * time.Second, true},
+		{5 * time.Second, true},
+	}
+	for i, want := range expected {
+		got, _ := b.Next()
+		if got != want.wait || b.Capped() != want.capped {
+			t.Fatalf("retry %d: expected wait %s (capped %t), got %s (capped %t)", i, want.wait, want.capped, got, b.Capped())
+		}
+	}
+}
+
+func TestRetryBackoff_MaxAttempts(t *testing.T) {
+	b := (&RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second,
+		MaxAttempts:    3,
+	}).NewBackoff()
+	for i := 0; i < 2; i++ {
+		if _, ok := b.Next(); !ok {
+			t.Fatalf("expected retry %d to be allowed", i)
+		}
+	}
+	if _, ok := b.Next(); ok {
+		t.Fatalf("expected no retries after 3 attempts")
+	}
+}
+
+func TestRetryBackoff_Jitter(t *testing.T) {
+	b := (&RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second,
+		Multiplier:     1,
+		Jitter:         0.5,
+	}).NewBackoff()
+	for i := 0; i < 20; i++ {
+		got, _ := b.Next()
+		if got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("expected jittered wait within [500ms, 1.5s], got %s", got)
+		}
+	}
+}
+
+func TestExpandProviderRetryPolicy(t *testing.T) {
+	cases := map[string]struct {
+		Raw         interface{}
+		Expected    *RetryPolicy
+		ExpectError bool
+	}{
+		"not set": {
+			Raw:      []interface{}{},
+			Expected: nil,
+		},
+		"empty block uses defaults": {
+			Raw:      []interface{}{map[string]interface{}{}},
+			Expected: DefaultRetryPolicy(),
+		},
+		"all fields set": {
+			Raw: []interface{}{
+				map[string]interface{}{
+					"initial_backoff": "1s",
+					"max_backoff":     "1m",
+					"multiplier":      2.0,
+					"jitter":          0.2,
+					"max_attempts":    5,
+					"timeout":         "5m",
+					"status_code_override": []interface{}{
+						map[string]interface{}{"code": 429, "retry": true},
+						map[string]interface{}{"code": 503, "retry": false},
+					},
+				},
+			},
+			Expected: &RetryPolicy{
+				InitialBackoff: time.Second,
+				MaxBackoff:     time.Minute,
+				Multiplier:     2,
+				Jitter:         0.2,
+				MaxAttempts:    5,
+				Timeout:        5 * time.Minute,
+				StatusCodeOverrides: map[int]bool{
+					429: true,
+					503: false,
+				},
+			},
+		},
+		"invalid duration": {
+			Raw:         []interface{}{map[string]interface{}{"initial_backoff": "soon"}},
+			ExpectError: true,
+		},
+		"max backoff below initial backoff": {
+			Raw:         []interface{}{map[string]interface{}{"initial_backoff": "10s", "max_backoff": "1s"}},
+			ExpectError: true,
+		},
+		"multiplier below one": {
+			Raw:         []interface{}{map[string]interface{}{"multiplier": 0.5}},
+			ExpectError: true,
+		},
+		"invalid status code": {
+			Raw: []interface{}{
+				map[string]interface{}{
+					"status_code_override": []interface{}{
+						map[string]interface{}{"code": 42, "retry": true},
+					},
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			policy, err := ExpandProviderRetryPolicy(tc.Raw)
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(policy, tc.Expected) {
+				t.Fatalf("expected %#v, got %#v", tc.Expected, policy)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_StatusCodeOverride(t *testing.T) {
+	policy := &RetryPolicy{StatusCodeOverrides: map[int]bool{429: false}}
+
+	if retry, ok := policy.StatusCodeOverride(&googleapi.Error{Code: 429}); !ok || retry {
+		t.Fatalf("expected 429 to be overridden as non-retryable, got retry=%t ok=%t", retry, ok)
+	}
+	if _, ok := policy.StatusCodeOverride(fmt.Errorf("wrapped: %w", &googleapi.Error{Code: 500})); ok {
+		t.Fatalf("expected no override for 500")
+	}
+	if _, ok := (*RetryPolicy)(nil).StatusCodeOverride(&googleapi.Error{Code: 429}); ok {
+		t.Fatalf("expected no override for a nil policy")
+	}
+}
@@ -40,9 +40,12 @@ import (
 	"log"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/api/googleapi"
 )
 
@@ -50,17 +53,30 @@ const defaultRetryTransportTimeoutSec = 90
 
 // NewTransportWithDefaultRetries constructs a default retryTransport that will retry common temporary errors
 func NewTransportWithDefaultRetries(t http.RoundTripper) *retryTransport {
+	return NewTransportWithRetryPolicy(t, nil)
+}
+
+// NewTransportWithRetryPolicy constructs a retryTransport that will retry common temporary errors,
+// waiting between attempts as described by the given policy. A nil policy uses DefaultRetryPolicy.
+func NewTransportWithRetryPolicy(t http.RoundTripper, policy *RetryPolicy) *retryTransport {
 	return &retryTransport{
 		retryPredicates: defaultErrorRetryPredicates,
 		internal:        t,
+		policy:          policy,
 	}
 }
 
 // Helper method to create a shallow copy of an HTTP client with a shallow-copied retryTransport
 // s.t. the base HTTP transport is the same (i.e. client connection pools are shared, retryPredicates are different)
 func ClientWithAdditionalRetries(baseClient *http.Client, predicates ...RetryErrorPredicateFunc) *http.Client {
+	return ClientWithRetryPolicy(baseClient, nil, predicates...)
+}
+
+// ClientWithRetryPolicy is like ClientWithAdditionalRetries, but the added retryTransport
+// waits between attempts as described by the given policy.
+func ClientWithRetryPolicy(baseClient *http.Client, policy *RetryPolicy, predicates ...RetryErrorPredicateFunc) *http.Client {
 	copied := *baseClient
-	baseRetryTransport := NewTransportWithDefaultRetries(baseClient.Transport)
+	baseRetryTransport := NewTransportWithRetryPolicy(baseClient.Transport, policy)
 	copied.Transport = baseRetryTransport.WithAddedPredicates(predicates...)
 	return &copied
 }
@@ -76,17 +92,27 @@ func (t *retryTransport) WithAddedPredicates(predicates ...RetryErrorPredicateFu
 type retryTransport struct {
 	retryPredicates []RetryErrorPredicateFunc
 	internal        http.RoundTripper
+	policy          *RetryPolicy
+}
+
+func (t *retryTransport) retryPolicy() *RetryPolicy {
+	if t.policy == nil {
+		return DefaultRetryPolicy()
+	}
+	return t.policy
 }
 
 // RoundTrip implements the RoundTripper interface method.
 // It retries the given HTTP request based on the retry predicates
 // registered under the retryTransport.
 func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, respErr error) {
+	policy := t.retryPolicy()
+
 	// Set timeout to default value.
 	ctx := req.Context()
 	var ccancel context.CancelFunc
-	if _, ok := ctx.Deadline(); !ok {
-		ctx, ccancel = context.WithTimeout(ctx, defaultRetryTransportTimeoutSec*time.Second)
+	if _, ok := ctx.Deadline(); !ok && policy.Timeout > 0 {
+		ctx, ccancel = context.WithTimeout(ctx, policy.Timeout)
 		defer func() {
 			if ctx.Err() == nil {
 				// Cleanup child context created for retry loop if ctx not done.
@@ -96,8 +122,7 @@ func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, resp
 	}
 
 	attempts := 0
-	backoff := time.Millisecond * 500
-	nextBackoff := time.Millisecond * 500
+	backoff := policy.NewBackoff()
 
 	// VCR depends on the original request body being consumed, so
 	// consume here. Since this won't affect the request itself,
@@ -107,6 +132,12 @@ func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, resp
 		log.Printf("[WARN] Retry Transport: Consuming original request body failed: %v", err)
 	}
 
+	// Every attempt shares a request ID so structured logs can correlate retries.
+	reqCtx := req.Context()
+	if requestIDFromContext(reqCtx) == "" {
+		reqCtx = withRequestID(reqCtx, newRequestID())
+	}
+
 	log.Printf("[DEBUG] Retry Transport: starting RoundTrip retry loop")
 Retry:
 	for {
@@ -121,12 +152,23 @@ Retry:
 			break Retry
 		}
 
+		attemptCtx, span := StartSpan(reqCtx, "RetryTransport attempt",
+			attribute.Int("attempt", attempts),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", RedactURL(req.URL)),
+		)
+		newRequest = newRequest.WithContext(withRetryAttempt(attemptCtx, attempts))
+
 		log.Printf("[DEBUG] Retry Transport: request attempt %d", attempts)
 		// Do the wrapped Roundtrip. This is one request in the retry loop.
 		resp, respErr = t.internal.RoundTrip(newRequest)
 		attempts++
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		EndSpan(span, respErr)
 
-		retryErr := t.checkForRetryableError(resp, respErr)
+		retryErr, retryReason := t.checkForRetryableError(resp, respErr)
 		if retryErr == nil {
 			log.Printf("[DEBUG] Retry Transport: Stopping retries, last request was successful")
 			break Retry
@@ -136,18 +178,32 @@ Retry:
 			break Retry
 		}
 
-		log.Printf("[DEBUG] Retry Transport: Waiting %s before trying request again", backoff)
+		wait, ok := backoff.Next()
+		if !ok {
+			log.Printf("[DEBUG] Retry Transport: Stopping retries, reached max attempts (%d)", policy.MaxAttempts)
+			break Retry
+		}
+
+		if hint, ok := serverRetryDelay(resp, time.Now()); ok && hint > wait {
+			log.Printf("[DEBUG] Retry Transport: Server asked to wait %s before retrying, overriding backoff of %s", hint, wait)
+			wait = hint
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			log.Printf("[DEBUG] Retry Transport: Stopping retries, waiting %s would exceed the context deadline", wait)
+			break Retry
+		}
+
+		metrics := usageMetricsFromContext(req.Context())
+		metrics.recordRetry(req.URL.Host)
+		metrics.recordRetryReason(retryReason)
+
+		log.Printf("[DEBUG] Retry Transport: Waiting %s before trying request again", wait)
 		select {
 		case <-ctx.Done():
 			log.Printf("[DEBUG] Retry Transport: Stopping retries, context done: %v", ctx.Err())
 			break Retry
-		case <-time.After(backoff):
-			log.Printf("[DEBUG] Retry Transport: Finished waiting %s before next retry", backoff)
-
-			// Fibonnaci backoff - 0.5, 1, 1.5, 2.5, 4, 6.5, 10.5, ...
-			lastBackoff := backoff
-			backoff = backoff + nextBackoff
-			nextBackoff = lastBackoff
+		case <-time.After(wait):
+			log.Printf("[DEBUG] Retry Transport: Finished waiting %s before next retry", wait)
 			continue
 		}
 	}
@@ -180,8 +236,9 @@ func copyHttpRequest(req *http.Request) (*http.Request, error) {
 
 // checkForRetryableError uses the googleapi.CheckResponse util to check for
 // errors in the response, and determines whether there is a retryable error.
-// in response/response error.
-func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr error) *resource.RetryError {
+// in response/response error. If there is, the reason it is retryable is also
+// returned.
+func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr error) (*resource.RetryError, string) {
 	var errToCheck error
 
 	if respErr != nil {
@@ -196,7 +253,7 @@ func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr err
 			// error code and messages in the response body.
 			dumpBytes, err := httputil.DumpResponse(resp, true)
 			if err != nil {
-				return resource.NonRetryableError(fmt.Errorf("unable to check response for error: %v", err))
+				return resource.NonRetryableError(fmt.Errorf("unable to check response for error: %v", err)), ""
 			}
 			respToCheck.Body = ioutil.NopCloser(bytes.NewReader(dumpBytes))
 		}
@@ -204,10 +261,65 @@ func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr err
 	}
 
 	if errToCheck == nil {
-		return nil
+		return nil, ""
+	}
+	if retry, ok := t.policy.StatusCodeOverride(errToCheck); ok {
+		log.Printf("[DEBUG] Retry Transport: retry_policy overrides status code handling (retry=%t): %s", retry, errToCheck)
+		if retry {
+			return resource.RetryableError(errToCheck), "Retryable status code set in retry_policy"
+		}
+		return resource.NonRetryableError(errToCheck), ""
+	}
+	if retryable, reason := retryableErrorReason(errToCheck, t.retryPredicates, nil); retryable {
+		return resource.RetryableError(errToCheck), reason
 	}
-	if IsRetryableError(errToCheck, t.retryPredicates, nil) {
-		return resource.RetryableError(errToCheck)
+	return resource.NonRetryableError(errToCheck), ""
+}
+
+// serverRetryDelay returns how long the server asked the client to wait
+// before retrying resp, using the Retry-After header in either its
+// delay-seconds or HTTP-date form. For rate limited responses without
+// Retry-After, an X-RateLimit-Reset header is used if X-RateLimit-Remaining
+// is exhausted. ok is false if the response carries no usable hint.
+func serverRetryDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	if v := strings.TrimSpace(resp.Header.Get("Retry-After")); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if secs < 0 {
+				return 0, false
+			}
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := t.Sub(now); d > 0 {
+				return d, true
+			}
+			return 0, true
+		}
+		log.Printf("[DEBUG] Retry Transport: Ignoring unparseable Retry-After header %q", v)
+	}
+
+	if strings.TrimSpace(resp.Header.Get("X-RateLimit-Remaining")) != "0" {
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")), 10, 64)
+	if err != nil || reset < 0 {
+		return 0, false
+	}
+	// X-RateLimit-Reset is sent either as seconds until the window resets or
+	// as the Unix time at which it resets. Values beyond a day from now can
+	// only be the latter.
+	if reset > int64((24 * time.Hour).Seconds()) {
+		if d := time.Unix(reset, 0).Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
 	}
-	return resource.NonRetryableError(errToCheck)
+	return time.Duration(reset) * time.Second, true
 }
@@ -171,6 +171,190 @@ func TestRetryTransport_DoesNotRetryEmptyGetBody(t *testing.T) {
 	testRetryTransport_checkFailedWhileRetrying(t, resp, err)
 }
 
+// Check that the retry policy's MaxAttempts bounds the number of requests made
+func TestRetryTransport_PolicyMaxAttempts(t *testing.T) {
+	var attempts int
+	ts, client := setUpRetryTransportServerClient(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(testRetryTransportCodeRetry)
+		}))
+	defer ts.Close()
+	client.Transport.(*retryTransport).policy = &RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		MaxAttempts:    3,
+	}
+
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkFailedWhileRetrying(t, resp, err)
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+// Check that a status code override stops retries for an otherwise retryable code
+func TestRetryTransport_PolicyStatusCodeOverride(t *testing.T) {
+	var attempts int
+	ts, client := setUpRetryTransportServerClient(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(testRetryTransportCodeRetry)
+		}))
+	defer ts.Close()
+	client.Transport.(*retryTransport).policy = &RetryPolicy{
+		InitialBackoff:      time.Millisecond,
+		MaxBackoff:          time.Millisecond,
+		StatusCodeOverrides: map[int]bool{testRetryTransportCodeRetry: false},
+	}
+
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkFailedWhileRetrying(t, resp, err)
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+// Check that a Retry-After header lengthens the wait before the next attempt
+func TestRetryTransport_HonorsRetryAfter(t *testing.T) {
+	var attempts int
+	ts, client := setUpRetryTransportServerClient(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(testRetryTransportCodeSuccess)
+		}))
+	defer ts.Close()
+	client.Transport.(*retryTransport).policy = &RetryPolicy{
+		InitialBackoff:      time.Millisecond,
+		MaxBackoff:          time.Millisecond,
+		StatusCodeOverrides: map[int]bool{http.StatusTooManyRequests: true},
+	}
+
+	start := time.Now()
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkSuccess(t, resp, err)
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected retry to wait at least 1s for Retry-After, waited %s", elapsed)
+	}
+}
+
+// Check that a Retry-After beyond the context deadline stops retries immediately
+func TestRetryTransport_RetryAfterExceedsDeadline(t *testing.T) {
+	var attempts int
+	ts, client := setUpRetryTransportServerClient(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if _, err := w.Write([]byte(fmt.Sprintf("Code: %d", http.StatusServiceUnavailable))); err != nil {
+				t.Errorf("[ERROR] unable to write to response writer: %v", err)
+			}
+		}))
+	defer ts.Close()
+	client.Transport.(*retryTransport).policy = &RetryPolicy{
+		InitialBackoff:      time.Millisecond,
+		MaxBackoff:          time.Millisecond,
+		StatusCodeOverrides: map[int]bool{http.StatusServiceUnavailable: true},
+	}
+
+	ctx, cc := context.WithTimeout(context.Background(), time.Second*2)
+	defer cc()
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	if err != nil {
+		t.Fatalf("unable to construct err: %v", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	testRetryTransport_checkFailure(t, resp, err, http.StatusServiceUnavailable)
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected to give up without waiting, waited %s", elapsed)
+	}
+}
+
+func TestServerRetryDelay(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		code    int
+		headers map[string]string
+		want    time.Duration
+		wantOk  bool
+	}{
+		"no headers": {
+			code: http.StatusTooManyRequests,
+		},
+		"retry-after seconds": {
+			code:    http.StatusTooManyRequests,
+			headers: map[string]string{"Retry-After": "7"},
+			want:    7 * time.Second,
+			wantOk:  true,
+		},
+		"retry-after http date": {
+			code:    http.StatusServiceUnavailable,
+			headers: map[string]string{"Retry-After": now.Add(90 * time.Second).Format(http.TimeFormat)},
+			want:    90 * time.Second,
+			wantOk:  true,
+		},
+		"retry-after date in the past": {
+			code:    http.StatusServiceUnavailable,
+			headers: map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)},
+			want:    0,
+			wantOk:  true,
+		},
+		"retry-after unparseable": {
+			code:    http.StatusTooManyRequests,
+			headers: map[string]string{"Retry-After": "soon"},
+		},
+		"retry-after ignored for other codes": {
+			code:    http.StatusInternalServerError,
+			headers: map[string]string{"Retry-After": "7"},
+		},
+		"ratelimit reset seconds": {
+			code:    http.StatusTooManyRequests,
+			headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"},
+			want:    30 * time.Second,
+			wantOk:  true,
+		},
+		"ratelimit reset unix time": {
+			code:    http.StatusTooManyRequests,
+			headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": fmt.Sprint(now.Add(time.Minute).Unix())},
+			want:    time.Minute,
+			wantOk:  true,
+		},
+		"ratelimit not exhausted": {
+			code:    http.StatusTooManyRequests,
+			headers: map[string]string{"X-RateLimit-Remaining": "3", "X-RateLimit-Reset": "30"},
+		},
+		"retry-after preferred over ratelimit": {
+			code:    http.StatusTooManyRequests,
+			headers: map[string]string{"Retry-After": "2", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"},
+			want:    2 * time.Second,
+			wantOk:  true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tc.code, Header: http.Header{}}
+			for k, v := range tc.headers {
+				resp.Header.Set(k, v)
+			}
+			got, ok := serverRetryDelay(resp, now)
+			if ok != tc.wantOk || got != tc.want {
+				t.Fatalf("expected (%s, %t), got (%s, %t)", tc.want, tc.wantOk, got, ok)
+			}
+		})
+	}
+}
+
 // handlers
 func testRetryTransportHandler_noRetries(t *testing.T, code int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
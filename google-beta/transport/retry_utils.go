@@ -3,6 +3,7 @@
 package transport
 
 import (
+	"fmt"
 	"log"
 	"time"
 
@@ -16,6 +17,22 @@ type RetryOptions struct {
 	PollInterval         time.Duration
 	ErrorRetryPredicates []RetryErrorPredicateFunc
 	ErrorAbortPredicates []RetryErrorPredicateFunc
+	// Policy, if set, replaces the default backoff between attempts and can
+	// override retryability for specific HTTP status codes. It is ignored when
+	// PollInterval is set.
+	Policy *RetryPolicy
+	// Metrics, if set, records why errors were found retryable.
+	Metrics *UsageMetrics
+}
+
+// isRetryableError reports whether err should be retried, recording the
+// reason in opt.Metrics if it should.
+func (opt RetryOptions) isRetryableError(err error) bool {
+	retryable, reason := retryableErrorReason(err, opt.ErrorRetryPredicates, opt.ErrorAbortPredicates)
+	if retryable {
+		opt.Metrics.recordRetryReason(reason)
+	}
+	return retryable
 }
 
 func Retry(opt RetryOptions) error {
@@ -23,6 +40,10 @@ func Retry(opt RetryOptions) error {
 		opt.Timeout = 1 * time.Minute
 	}
 
+	if opt.Policy != nil && opt.PollInterval == 0 {
+		return retryWithPolicy(opt)
+	}
+
 	if opt.PollInterval != 0 {
 		refreshFunc := func() (interface{}, string, error) {
 			err := opt.RetryFunc()
@@ -31,7 +52,7 @@ func Retry(opt RetryOptions) error {
 			}
 
 			// Check if it is a retryable error.
-			if IsRetryableError(err, opt.ErrorRetryPredicates, opt.ErrorAbortPredicates) {
+			if opt.isRetryableError(err) {
 				return "", "retrying", nil
 			}
 
@@ -59,16 +80,59 @@ func Retry(opt RetryOptions) error {
 		if err == nil {
 			return nil
 		}
-		if IsRetryableError(err, opt.ErrorRetryPredicates, opt.ErrorAbortPredicates) {
+		if opt.isRetryableError(err) {
 			return resource.RetryableError(err)
 		}
 		return resource.NonRetryableError(err)
 	})
 }
 
+// retryWithPolicy retries opt.RetryFunc until it succeeds, fails with a
+// non-retryable error, exhausts opt.Policy's attempts or reaches opt.Timeout.
+func retryWithPolicy(opt RetryOptions) error {
+	deadline := time.Now().Add(opt.Timeout)
+	backoff := opt.Policy.NewBackoff()
+	for {
+		err := opt.RetryFunc()
+		if err == nil {
+			return nil
+		}
+
+		if retry, ok := opt.Policy.StatusCodeOverride(err); ok {
+			log.Printf("[DEBUG] retry_policy overrides status code handling (retry=%t): %s", retry, err)
+			if !retry {
+				return err
+			}
+		} else if !opt.isRetryableError(err) {
+			return err
+		}
+
+		wait, ok := backoff.Next()
+		if !ok {
+			return errwrap.Wrapf(fmt.Sprintf("giving up after %d attempts: {{err}}", opt.Policy.MaxAttempts), err)
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return &resource.TimeoutError{
+				LastError: err,
+				Timeout:   opt.Timeout,
+			}
+		}
+
+		log.Printf("[DEBUG] Waiting %s before retrying: %s", wait, err)
+		time.Sleep(wait)
+	}
+}
+
 func IsRetryableError(topErr error, retryPredicates, abortPredicates []RetryErrorPredicateFunc) bool {
+	retryable, _ := retryableErrorReason(topErr, retryPredicates, abortPredicates)
+	return retryable
+}
+
+// retryableErrorReason is IsRetryableError, also returning the reason given by
+// the predicate that found the error retryable.
+func retryableErrorReason(topErr error, retryPredicates, abortPredicates []RetryErrorPredicateFunc) (bool, string) {
 	if topErr == nil {
-		return false
+		return false, ""
 	}
 
 	retryPredicates = append(
@@ -88,19 +152,23 @@ func IsRetryableError(topErr error, retryPredicates, abortPredicates []RetryErro
 		}
 	})
 	if isAbortable {
-		return false
+		return false, ""
 	}
 
 	// Check all wrapped errors for a retryable error status.
 	isRetryable := false
+	reason := ""
 	errwrap.Walk(topErr, func(werr error) {
 		for _, pred := range retryPredicates {
 			if predRetry, predReason := pred(werr); predRetry {
 				log.Printf("[DEBUG] Dismissed an error as retryable. %s - %s", predReason, werr)
+				if !isRetryable {
+					reason = predReason
+				}
 				isRetryable = true
 				return
 			}
 		}
 	})
-	return isRetryable
+	return isRetryable, reason
 }
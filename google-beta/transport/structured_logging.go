@@ -0,0 +1,439 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LogFormatText logs HTTP and gRPC traffic as human readable text. This is
+	// the default.
+	LogFormatText = "text"
+
+	// LogFormatJSON logs HTTP and gRPC traffic as one JSON object per line,
+	// with sensitive fields redacted.
+	LogFormatJSON = "json"
+)
+
+// LogFormats lists the accepted values of the provider's `log_format` field.
+var LogFormats = []string{LogFormatText, LogFormatJSON}
+
+const redactedValue = "REDACTED"
+
+// sensitiveFieldNames are the JSON field names whose values are never
+// written to structured logs. Names are compared after lowercasing and
+// removing underscores, so both "private_key" and "privateKey" match.
+var sensitiveFieldNames = map[string]bool{
+	"accesstoken":    true,
+	"refreshtoken":   true,
+	"idtoken":        true,
+	"clientsecret":   true,
+	"privatekey":     true,
+	"privatekeydata": true,
+	"secretdata":     true,
+	"password":       true,
+	"plaintext":      true,
+}
+
+// sensitiveFieldPaths are the paths, from the root of a request or response
+// body, of fields whose names are too common to be redacted everywhere:
+// the payload of Secret Manager secret versions and the data signed by
+// Cloud KMS keys. Paths are compared like sensitiveFieldNames, with the
+// names of nested fields separated by ".".
+var sensitiveFieldPaths = map[string]bool{
+	"payload.data": true,
+	"data":         true,
+}
+
+// sensitiveHeaders are the HTTP headers whose values are never written to
+// structured logs.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Goog-Api-Key",
+}
+
+// sensitiveQueryParams are the URL query parameters whose values are never
+// written to structured logs.
+var sensitiveQueryParams = []string{
+	"access_token",
+	"key",
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "")
+}
+
+func isSensitiveFieldName(name string) bool {
+	return sensitiveFieldNames[normalizeFieldName(name)]
+}
+
+// RedactJSON returns a copy of the JSON document b with the values of
+// sensitive fields replaced, at any depth. If b is not valid JSON, ok is false.
+func RedactJSON(b []byte) (redacted json.RawMessage, ok bool) {
+	return RedactJSONWithValue(b, redactedValue)
+}
+
+// RedactJSONWithValue is RedactJSON, replacing the values of sensitive fields
+// with value.
+func RedactJSONWithValue(b []byte, value string) (redacted json.RawMessage, ok bool) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	out, err := json.Marshal(redactValue(v, "", value))
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// redactValue redacts v, found at path in the document being redacted,
+// replacing the values of sensitive fields with value.
+func redactValue(v interface{}, path, value string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range v {
+			fieldPath := normalizeFieldName(k)
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if isSensitiveFieldName(k) || sensitiveFieldPaths[fieldPath] {
+				v[k] = value
+				continue
+			}
+			v[k] = redactValue(fv, fieldPath, value)
+		}
+		return v
+	case []interface{}:
+		for i, ev := range v {
+			v[i] = redactValue(ev, path, value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// RedactHTTPHeader returns a copy of h with the values of sensitive headers
+// replaced.
+func RedactHTTPHeader(h http.Header) http.Header {
+	redacted := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(k)]; ok {
+			redacted.Set(k, redactedValue)
+		}
+	}
+	return redacted
+}
+
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k := range h {
+		out[k] = strings.Join(h.Values(k), ", ")
+	}
+	for _, k := range sensitiveHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(k)]; ok {
+			out[http.CanonicalHeaderKey(k)] = redactedValue
+		}
+	}
+	return out
+}
+
+// RedactURL returns u as a string, with the values of sensitive query
+// parameters replaced.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	q := redacted.Query()
+	changed := false
+	for _, k := range sensitiveQueryParams {
+		if q.Has(k) {
+			q.Set(k, redactedValue)
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawQuery = q.Encode()
+	}
+	return redacted.String()
+}
+
+// redactBody returns the value logged for an HTTP body: the redacted
+// document for JSON bodies, or a placeholder describing any other body so
+// that opaque payloads are never logged.
+func redactBody(b []byte, contentType string) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	if redacted, ok := RedactJSON(b); ok {
+		return redacted
+	}
+	return fmt.Sprintf("<%d bytes of %s omitted>", len(b), contentType)
+}
+
+type requestIDContextKey struct{}
+type retryAttemptContextKey struct{}
+type resourceContextKey struct{}
+
+// resourceContext identifies the Terraform resource requests are made for.
+type resourceContext struct {
+	resourceType string
+	id           string
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+func withRetryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, retryAttemptContextKey{}, attempt)
+}
+
+func retryAttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptContextKey{}).(int)
+	return attempt
+}
+
+// WithResource returns a copy of ctx that records the type, e.g.
+// "google_compute_instance", and the ID of the Terraform resource requests
+// are made for. The ID is empty while a resource is created. Structured logs
+// include them for requests made with the returned context.
+func WithResource(ctx context.Context, resourceType, id string) context.Context {
+	return context.WithValue(ctx, resourceContextKey{}, resourceContext{resourceType: resourceType, id: id})
+}
+
+// ResourceFromContext returns the resource type and ID recorded in ctx by
+// WithResource, or empty strings if there are none.
+func ResourceFromContext(ctx context.Context) (resourceType, id string) {
+	r, _ := ctx.Value(resourceContextKey{}).(resourceContext)
+	return r.resourceType, r.id
+}
+
+// writeJSONLogLine writes entry to the standard logger's output as a single
+// line. The line is written without the logger's prefix so that it stays
+// valid JSON.
+func writeJSONLogLine(entry map[string]interface{}) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[WARN] Unable to marshal structured log entry: %s", err)
+		return
+	}
+	if _, err := log.Writer().Write(append(b, '\n')); err != nil {
+		log.Printf("[WARN] Unable to write structured log entry: %s", err)
+	}
+}
+
+// NewLoggingTransport returns the transport used to log HTTP requests to GCP
+// APIs in the given log format.
+func NewLoggingTransport(t http.RoundTripper, logFormat string) http.RoundTripper {
+	if logFormat == LogFormatJSON {
+		return &jsonLoggingTransport{internal: t}
+	}
+	return logging.NewTransport("Google", t)
+}
+
+// jsonLoggingTransport logs each HTTP request and response as a JSON line
+// with sensitive values redacted.
+type jsonLoggingTransport struct {
+	internal http.RoundTripper
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *jsonLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !logging.IsDebugOrHigher() {
+		return t.internal.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	requestID := requestIDFromContext(ctx)
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	common := map[string]interface{}{
+		"@level":     "debug",
+		"@module":    "google.http",
+		"request_id": requestID,
+		"attempt":    retryAttemptFromContext(ctx),
+		"method":     req.Method,
+		"url":        RedactURL(req.URL),
+	}
+	if resourceType, id := ResourceFromContext(ctx); resourceType != "" {
+		common["resource_type"] = resourceType
+		if id != "" {
+			common["resource_id"] = id
+		}
+	}
+
+	reqEntry := copyLogEntry(common)
+	reqEntry["@timestamp"] = time.Now().Format(time.RFC3339Nano)
+	reqEntry["@message"] = "HTTP request"
+	reqEntry["headers"] = redactHeaders(req.Header)
+	if body, err := peekRequestBody(req); err != nil {
+		reqEntry["body_error"] = err.Error()
+	} else if b := redactBody(body, req.Header.Get("Content-Type")); b != nil {
+		reqEntry["body"] = b
+	}
+	writeJSONLogLine(reqEntry)
+
+	start := time.Now()
+	resp, err := t.internal.RoundTrip(req)
+	latency := time.Since(start)
+
+	respEntry := copyLogEntry(common)
+	respEntry["@timestamp"] = time.Now().Format(time.RFC3339Nano)
+	respEntry["latency_ms"] = latency.Milliseconds()
+	if err != nil {
+		respEntry["@message"] = "HTTP request failed"
+		respEntry["error"] = err.Error()
+		writeJSONLogLine(respEntry)
+		return resp, err
+	}
+
+	respEntry["@message"] = "HTTP response"
+	respEntry["status"] = resp.StatusCode
+	respEntry["headers"] = redactHeaders(resp.Header)
+	if body, err := peekResponseBody(resp); err != nil {
+		respEntry["body_error"] = err.Error()
+	} else if b := redactBody(body, resp.Header.Get("Content-Type")); b != nil {
+		respEntry["body"] = b
+	}
+	writeJSONLogLine(respEntry)
+	return resp, nil
+}
+
+func copyLogEntry(entry map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(entry)+6)
+	for k, v := range entry {
+		out[k] = v
+	}
+	return out
+}
+
+// peekRequestBody returns the request body, leaving req able to send it.
+func peekRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return b, err
+}
+
+// peekResponseBody returns the response body, leaving resp readable.
+func peekResponseBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return b, err
+}
+
+// NewLogFormatter returns the logrus formatter used for gRPC logs in the
+// given log format.
+func NewLogFormatter(logFormat string) logrus.Formatter {
+	if logFormat == LogFormatJSON {
+		return &JSONFormatter{}
+	}
+	return &Formatter{
+		TimestampFormat: "2006/01/02 15:04:05",
+		LogFormat:       "%time% [%lvl%] %msg% \n",
+	}
+}
+
+// JSONFormatter formats gRPC log entries as JSON lines with sensitive
+// fields redacted. Like Formatter, it only logs when TF_LOG is DEBUG or
+// TRACE.
+type JSONFormatter struct{}
+
+func (f *JSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if !logging.IsDebugOrHigher() {
+		return nil, nil
+	}
+	if strings.Contains(entry.Message, "transport is closing") {
+		return nil, nil
+	}
+
+	out := map[string]interface{}{
+		"@timestamp": entry.Time.Format(time.RFC3339Nano),
+		"@level":     "debug",
+		"@module":    "google.grpc",
+		"@message":   entry.Message,
+	}
+	for k, v := range entry.Data {
+		if isSensitiveFieldName(k) {
+			out[k] = redactedValue
+			continue
+		}
+		switch v := v.(type) {
+		case error:
+			// Otherwise errors are ignored by `encoding/json`
+			out[k] = v.Error()
+		case json.Marshaler:
+			// gRPC payloads are logged as protobuf messages marshaled to JSON.
+			b, err := v.MarshalJSON()
+			if err != nil {
+				out[k] = fmt.Sprintf("<unable to marshal: %s>", err)
+				continue
+			}
+			if redacted, ok := RedactJSON(b); ok {
+				out[k] = redacted
+			} else {
+				out[k] = fmt.Sprintf("<%d bytes omitted>", len(b))
+			}
+		default:
+			out[k] = v
+		}
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedactJSON(t *testing.T) {
+	cases := map[string]struct {
+		in   string
+		want string
+	}{
+		"top level fields": {
+			in:   `{"name":"user","password":"hunter2"}`,
+			want: `{"name":"user","password":"REDACTED"}`,
+		},
+		"nested and camel case fields": {
+			in:   `{"key":{"privateKeyData":"abc","name":"k"},"items":[{"secret_data":"s"},{"plaintext":"p"}]}`,
+			want: `{"items":[{"secret_data":"REDACTED"},{"plaintext":"REDACTED"}],"key":{"name":"k","privateKeyData":"REDACTED"}}`,
+		},
+		"tokens": {
+			in:   `{"access_token":"ya29","nextPageToken":"keep"}`,
+			want: `{"access_token":"REDACTED","nextPageToken":"keep"}`,
+		},
+		"numbers keep precision": {
+			in:   `{"id":12345678901234567890}`,
+			want: `{"id":12345678901234567890}`,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, ok := RedactJSON([]byte(tc.in))
+			if !ok {
+				t.Fatalf("expected %s to be redacted", tc.in)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+
+	if _, ok := RedactJSON([]byte("grant_type=x&assertion=y")); ok {
+		t.Fatalf("expected non-JSON input to be rejected")
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("https://example.com/v1/things?access_token=abc&pageSize=5")
+	got := RedactURL(u)
+	if strings.Contains(got, "abc") || !strings.Contains(got, "pageSize=5") {
+		t.Fatalf("unexpected redacted URL %q", got)
+	}
+}
+
+func captureStructuredLogs(t *testing.T) *bytes.Buffer {
+	t.Setenv("TF_LOG", "DEBUG")
+	var buf bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(orig) })
+	return &buf
+}
+
+func TestJSONLoggingTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "hunter2") {
+			t.Errorf("expected request body to reach the server unredacted, got %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"secret","secretData":"c2VjcmV0"}`))
+	}))
+	defer ts.Close()
+
+	buf := captureStructuredLogs(t)
+	client := &http.Client{Transport: NewTransportWithDefaultRetries(NewLoggingTransport(http.DefaultTransport, LogFormatJSON))}
+
+	req, err := http.NewRequestWithContext(
+		WithResource(context.Background(), "google_sql_user", "u/p/i/default"),
+		"POST", ts.URL, strings.NewReader(`{"password":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer ya29.token")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "c2VjcmV0") {
+		t.Fatalf("expected response body to be returned unredacted, got %s", body)
+	}
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not valid JSON: %s", line)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a request and a response entry, got %d: %s", len(entries), buf.String())
+	}
+
+	for _, secret := range []string{"hunter2", "ya29.token", "c2VjcmV0"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Fatalf("expected %q to be redacted from logs: %s", secret, buf.String())
+		}
+	}
+
+	reqEntry, respEntry := entries[0], entries[1]
+	if reqEntry["request_id"] == "" || reqEntry["request_id"] != respEntry["request_id"] {
+		t.Fatalf("expected request and response to share a request ID, got %v and %v", reqEntry["request_id"], respEntry["request_id"])
+	}
+	if reqEntry["resource_type"] != "google_sql_user" || respEntry["resource_id"] != "u/p/i/default" {
+		t.Fatalf("expected the resource type and ID in log entries, got %v and %v", reqEntry["resource_type"], respEntry["resource_id"])
+	}
+	if reqEntry["attempt"] != float64(0) {
+		t.Fatalf("expected attempt 0, got %v", reqEntry["attempt"])
+	}
+	if respEntry["status"] != float64(200) {
+		t.Fatalf("expected status 200, got %v", respEntry["status"])
+	}
+	if _, ok := respEntry["latency_ms"]; !ok {
+		t.Fatalf("expected latency in response entry")
+	}
+}
+
+type testJSONMarshaler string
+
+func (m testJSONMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(m), nil
+}
+
+func TestJSONFormatter(t *testing.T) {
+	t.Setenv("TF_LOG", "DEBUG")
+
+	entry := logrus.NewEntry(logrus.New()).WithFields(logrus.Fields{
+		"grpc.method":           "CreateSecret",
+		"grpc.request.content":  testJSONMarshaler(`{"payload":{"data":"x","secret_data":"y"}}`),
+		"grpc.response.content": testJSONMarshaler(`{"name":"k","data":"z","labels":{"data":"kept"}}`),
+		"error":                 errors.New("boom"),
+	})
+	entry.Message = "client request payload logged as grpc.request.content field"
+
+	b, err := (&JSONFormatter{}).Format(entry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("formatted entry is not valid JSON: %s", b)
+	}
+	if out["@message"] != entry.Message || out["error"] != "boom" || out["grpc.method"] != "CreateSecret" {
+		t.Fatalf("unexpected formatted entry: %s", b)
+	}
+	if strings.Contains(string(b), `"y"`) {
+		t.Fatalf("expected secret_data to be redacted: %s", b)
+	}
+	if strings.Contains(string(b), `"x"`) {
+		t.Fatalf("expected the secret version's payload.data to be redacted: %s", b)
+	}
+	if strings.Contains(string(b), `"z"`) {
+		t.Fatalf("expected the KMS data to be redacted: %s", b)
+	}
+	if !strings.Contains(string(b), `"kept"`) {
+		t.Fatalf("expected nested fields named data to be kept: %s", b)
+	}
+}
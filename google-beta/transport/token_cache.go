@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenRefreshWindow is how long before their expiry cached tokens are
+// refreshed in the background, so long-running applies don't see a burst of
+// expired tokens while every request waits for a new one.
+const TokenRefreshWindow = 5 * time.Minute
+
+// tokenExpiryDelta matches the margin the oauth2 package treats tokens as
+// expired with.
+const tokenExpiryDelta = 10 * time.Second
+
+// TokenSourceCacheKey identifies the credentials a token source was built
+// from. Token sources built from the same credentials are shared by the SDK
+// and plugin framework providers, and by every client built from them.
+type TokenSourceCacheKey struct {
+	AccessToken                        string
+	Credentials                        string
+	ExternalCredentials                string
+	ApplicationDefaultCredentials      string
+	ImpersonateServiceAccount          string
+	ImpersonateServiceAccountDelegates []string
+	Scopes                             []string
+	UniverseDomain                     string
+}
+
+// NewTokenSourceCacheKey returns the key for credentials, falling back to the
+// application default credentials the environment points to. Credentials for
+// different universe domains get their own keys, as their tokens are minted
+// by different token endpoints, while the default one shares the key of an
+// unset universe domain. If
+// initialCredentialsOnly is true, the impersonation settings are ignored, as
+// they are when building the credentials.
+func NewTokenSourceCacheKey(accessToken, credentials string, externalCredentials *ExternalCredentialsConfig, impersonateServiceAccount string, delegates, scopes []string, universeDomain string, initialCredentialsOnly bool) (TokenSourceCacheKey, error) {
+	if initialCredentialsOnly {
+		impersonateServiceAccount = ""
+		delegates = nil
+	}
+	if universeDomain == "googleapis.com" {
+		universeDomain = ""
+	}
+	key := TokenSourceCacheKey{
+		AccessToken:                        accessToken,
+		Credentials:                        credentials,
+		ApplicationDefaultCredentials:      os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"),
+		ImpersonateServiceAccount:          impersonateServiceAccount,
+		ImpersonateServiceAccountDelegates: delegates,
+		Scopes:                             append([]string(nil), scopes...),
+		UniverseDomain:                     universeDomain,
+	}
+	sort.Strings(key.Scopes)
+	if externalCredentials != nil {
+		contents, err := externalCredentials.CredentialsJSON()
+		if err != nil {
+			return TokenSourceCacheKey{}, err
+		}
+		key.ExternalCredentials = string(contents)
+	}
+	return key, nil
+}
+
+// hash avoids keeping credentials in the keys of the cache.
+func (k TokenSourceCacheKey) hash() string {
+	b, _ := json.Marshal(k)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+var tokenSourceCache = struct {
+	sync.Mutex
+	sources map[string]*refreshingTokenSource
+}{
+	sources: make(map[string]*refreshingTokenSource),
+}
+
+// CachedTokenSource returns the shared token source for key, or nil if none
+// was cached yet.
+func CachedTokenSource(key TokenSourceCacheKey) oauth2.TokenSource {
+	tokenSourceCache.Lock()
+	defer tokenSourceCache.Unlock()
+
+	if source, ok := tokenSourceCache.sources[key.hash()]; ok {
+		return source
+	}
+	return nil
+}
+
+// CacheTokenSource shares tokenSource for key, and returns the shared token
+// source, which is the one cached first if several callers race. rebuild
+// returns a token source built from the credentials again, and is used to
+// fetch new tokens before the current one expires.
+func CacheTokenSource(key TokenSourceCacheKey, tokenSource oauth2.TokenSource, rebuild func() (oauth2.TokenSource, error)) oauth2.TokenSource {
+	tokenSourceCache.Lock()
+	defer tokenSourceCache.Unlock()
+
+	h := key.hash()
+	if source, ok := tokenSourceCache.sources[h]; ok {
+		return source
+	}
+	source := &refreshingTokenSource{
+		source:  tokenSource,
+		rebuild: rebuild,
+		now:     time.Now,
+	}
+	tokenSourceCache.sources[h] = source
+	return source
+}
+
+// refreshingTokenSource caches the token of a token source, and replaces it
+// in the background once it is within TokenRefreshWindow of expiring.
+//
+// Credentials cache their tokens until just before they expire, so a fresh
+// token can only be fetched early from newly built credentials.
+type refreshingTokenSource struct {
+	rebuild func() (oauth2.TokenSource, error)
+	now     func() time.Time
+
+	mu         sync.Mutex
+	source     oauth2.TokenSource
+	token      *oauth2.Token
+	refreshing bool
+}
+
+func (s *refreshingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && s.token.Expiry.IsZero() {
+		return s.token, nil
+	}
+	now := s.now()
+	if s.token != nil && now.Before(s.token.Expiry.Add(-tokenExpiryDelta)) {
+		if !s.refreshing && s.rebuild != nil && !now.Before(s.token.Expiry.Add(-TokenRefreshWindow)) {
+			s.refreshing = true
+			go s.refresh()
+		}
+		return s.token, nil
+	}
+
+	// Callers wait for the token while holding the lock, so an expired token
+	// is only fetched once.
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// refresh fetches a new token from newly built credentials.
+func (s *refreshingTokenSource) refresh() {
+	source, err := s.rebuild()
+	var token *oauth2.Token
+	if err == nil {
+		token, err = source.Token()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = false
+	if err != nil {
+		// The token is fetched again once it expires
+		log.Printf("[WARN] Error refreshing access token before it expires: %s", err)
+		return
+	}
+	s.source = source
+	s.token = token
+}
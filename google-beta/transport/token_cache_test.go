@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// countingTokenSource returns a new token expiring after lifetime on each call.
+type countingTokenSource struct {
+	mu       sync.Mutex
+	calls    int
+	lifetime time.Duration
+	now      time.Time
+}
+
+func (s *countingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	token := &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", s.calls)}
+	if s.lifetime != 0 {
+		token.Expiry = s.now.Add(s.lifetime)
+	}
+	return token, nil
+}
+
+func TestNewTokenSourceCacheKey(t *testing.T) {
+	a, err := NewTokenSourceCacheKey("", "creds", nil, "sa@my-project.iam.gserviceaccount.com", nil, []string{"a", "b"}, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b, _ := NewTokenSourceCacheKey("", "creds", nil, "sa@my-project.iam.gserviceaccount.com", nil, []string{"b", "a"}, "", false)
+	if a.hash() != b.hash() {
+		t.Errorf("expected the order of scopes to be ignored")
+	}
+
+	initial, _ := NewTokenSourceCacheKey("", "creds", nil, "sa@my-project.iam.gserviceaccount.com", nil, []string{"a", "b"}, "", true)
+	unimpersonated, _ := NewTokenSourceCacheKey("", "creds", nil, "", nil, []string{"a", "b"}, "", false)
+	if initial.hash() != unimpersonated.hash() {
+		t.Errorf("expected the initial credentials to ignore impersonation")
+	}
+	if initial.hash() == a.hash() {
+		t.Errorf("expected impersonated credentials to have their own key")
+	}
+
+	otherUniverse, _ := NewTokenSourceCacheKey("", "creds", nil, "sa@my-project.iam.gserviceaccount.com", nil, []string{"a", "b"}, "example.com", false)
+	if otherUniverse.hash() == a.hash() {
+		t.Errorf("expected credentials for another universe domain to have their own key")
+	}
+	defaultUniverse, _ := NewTokenSourceCacheKey("", "creds", nil, "sa@my-project.iam.gserviceaccount.com", nil, []string{"a", "b"}, "googleapis.com", false)
+	if defaultUniverse.hash() != a.hash() {
+		t.Errorf("expected the default universe domain to share the key of an unset one")
+	}
+
+	external, err := NewTokenSourceCacheKey("", "", &ExternalCredentialsConfig{Audience: testExternalCredentialsAudience, SubjectTokenFile: "/token"}, "", nil, nil, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if external.ExternalCredentials == "" {
+		t.Errorf("expected the external credentials to be part of the key")
+	}
+}
+
+func TestCacheTokenSource(t *testing.T) {
+	key := TokenSourceCacheKey{Credentials: t.Name()}
+	if CachedTokenSource(key) != nil {
+		t.Fatalf("expected no cached token source")
+	}
+
+	source := CacheTokenSource(key, &countingTokenSource{}, nil)
+	if CachedTokenSource(key) != source {
+		t.Errorf("expected the token source to be cached")
+	}
+	if CacheTokenSource(key, &countingTokenSource{}, nil) != source {
+		t.Errorf("expected the token source cached first to be kept")
+	}
+}
+
+func TestRefreshingTokenSource(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := &countingTokenSource{lifetime: time.Hour, now: now}
+	rebuilt := &countingTokenSource{lifetime: time.Hour, now: now}
+	rebuilds := make(chan struct{}, 1)
+	s := &refreshingTokenSource{
+		source: base,
+		rebuild: func() (oauth2.TokenSource, error) {
+			rebuilds <- struct{}{}
+			return rebuilt, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	token, err := s.Token()
+	if err != nil || token.AccessToken != "token-1" {
+		t.Fatalf("expected the first token, got %v, %v", token, err)
+	}
+	if token, _ := s.Token(); token.AccessToken != "token-1" || base.calls != 1 {
+		t.Errorf("expected the token to be cached, got %v after %d calls", token, base.calls)
+	}
+
+	// Within the refresh window, the token is still returned while a new one
+	// is fetched in the background
+	now = now.Add(time.Hour - TokenRefreshWindow + time.Minute)
+	if token, _ := s.Token(); token.AccessToken != "token-1" {
+		t.Errorf("expected the current token while refreshing, got %v", token)
+	}
+	select {
+	case <-rebuilds:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected the token source to be rebuilt")
+	}
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		refreshing := s.refreshing
+		s.mu.Unlock()
+		if !refreshing {
+			break
+		}
+		if i == 1000 {
+			t.Fatalf("expected the refresh to finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if token, _ := s.Token(); token.AccessToken != "token-1" || rebuilt.calls != 1 {
+		t.Errorf("expected the token of the rebuilt source, got %v after %d calls", token, rebuilt.calls)
+	}
+
+	// Expired tokens are fetched before returning
+	now = now.Add(2 * time.Hour)
+	rebuilt.now = now
+	if token, _ := s.Token(); rebuilt.calls != 2 || !token.Expiry.After(now) {
+		t.Errorf("expected a new token once expired, got %v after %d calls", token, rebuilt.calls)
+	}
+}
+
+func TestRefreshingTokenSource_noExpiry(t *testing.T) {
+	base := &countingTokenSource{}
+	s := &refreshingTokenSource{
+		source: base,
+		rebuild: func() (oauth2.TokenSource, error) {
+			t.Errorf("expected tokens without an expiry not to be refreshed")
+			return base, nil
+		},
+		now: time.Now,
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := s.Token(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if base.calls != 1 {
+		t.Errorf("expected a single token, got %d", base.calls)
+	}
+}
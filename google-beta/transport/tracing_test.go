@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setUpTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	orig := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(orig) })
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracingEnabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+	if TracingEnabled() {
+		t.Fatalf("expected tracing to be disabled without an endpoint")
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	if !TracingEnabled() {
+		t.Fatalf("expected tracing to be enabled with an endpoint")
+	}
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	if TracingEnabled() {
+		t.Fatalf("expected OTEL_SDK_DISABLED to disable tracing")
+	}
+}
+
+func TestRetryTransport_TracesAttempts(t *testing.T) {
+	recorder := setUpTestTracing(t)
+
+	var attempts int
+	ts, client := setUpRetryTransportServerClient(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(testRetryTransportCodeRetry)
+				return
+			}
+			w.WriteHeader(testRetryTransportCodeSuccess)
+		}))
+	defer ts.Close()
+	client.Transport.(*retryTransport).policy = &RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}
+
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkSuccess(t, resp, err)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected a span per attempt, got %d", len(spans))
+	}
+	for i, span := range spans {
+		if span.Name() != "RetryTransport attempt" {
+			t.Fatalf("unexpected span name %q", span.Name())
+		}
+		if v, ok := spanAttribute(span, "attempt"); !ok || v.AsInt64() != int64(i) {
+			t.Fatalf("expected attempt %d, got %v", i, v.Emit())
+		}
+	}
+	if v, _ := spanAttribute(spans[0], "http.response.status_code"); v.AsInt64() != testRetryTransportCodeRetry {
+		t.Fatalf("expected first attempt status %d, got %v", testRetryTransportCodeRetry, v.Emit())
+	}
+}
+
+func TestSendRequest_TracesRedactedURL(t *testing.T) {
+	recorder := setUpTestTracing(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	_, err := SendRequest(SendRequestOptions{
+		Config: &Config{Client: ts.Client()},
+		Method: "GET",
+		RawURL: ts.URL + "/v1/things?access_token=abc&pageSize=5",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, span := range recorder.Ended() {
+		if span.Name() != "SendRequest" {
+			continue
+		}
+		if v, _ := spanAttribute(span, "url.full"); strings.Contains(v.AsString(), "abc") || !strings.Contains(v.AsString(), "pageSize=5") {
+			t.Fatalf("expected a redacted URL, got %q", v.AsString())
+		}
+		return
+	}
+	t.Fatalf("expected a SendRequest span")
+}
+
+func TestMutexKV_TracesLockWait(t *testing.T) {
+	recorder := setUpTestTracing(t)
+
+	m := NewMutexKV()
+	m.Lock("project/test")
+	m.Unlock("project/test")
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "MutexKV.Lock" {
+		t.Fatalf("expected a MutexKV.Lock span, got %v", spans)
+	}
+	if v, _ := spanAttribute(spans[0], "mutex.key"); v.AsString() != "project/test" {
+		t.Fatalf("expected mutex key attribute, got %v", v.Emit())
+	}
+}
+
+func TestEndSpan_RecordsError(t *testing.T) {
+	recorder := setUpTestTracing(t)
+
+	_, span := StartSpan(nil, "test")
+	EndSpan(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error || spans[0].Status().Description != "boom" {
+		t.Fatalf("expected error status, got %v", spans[0].Status())
+	}
+}
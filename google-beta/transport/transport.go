@@ -4,6 +4,7 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/api/googleapi"
 )
 
@@ -28,9 +30,33 @@ type SendRequestOptions struct {
 	Timeout              time.Duration
 	ErrorRetryPredicates []RetryErrorPredicateFunc
 	ErrorAbortPredicates []RetryErrorPredicateFunc
+
+	// Context is the context the request is made with. It defaults to the
+	// config's RequestContext.
+	Context context.Context
 }
 
 func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
+	// The URL is redacted like those of the attempts, and left out if it
+	// can't be parsed, as sendRequest fails on it anyway.
+	var rawURL string
+	if u, err := url.Parse(opt.RawURL); err == nil {
+		rawURL = RedactURL(u)
+	}
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = opt.Config.requestContext()
+	}
+	ctx, span := StartSpan(ctx, "SendRequest",
+		attribute.String("http.request.method", opt.Method),
+		attribute.String("url.full", rawURL),
+	)
+	result, err := sendRequest(ctx, opt)
+	EndSpan(span, err)
+	return result, err
+}
+
+func sendRequest(ctx context.Context, opt SendRequestOptions) (map[string]interface{}, error) {
 	reqHeaders := make(http.Header)
 	reqHeaders.Set("User-Agent", opt.UserAgent)
 	reqHeaders.Set("Content-Type", "application/json")
@@ -66,7 +92,7 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 			if err != nil {
 				return err
 			}
-			req, err := http.NewRequest(opt.Method, u, &buf)
+			req, err := http.NewRequestWithContext(ctx, opt.Method, u, &buf)
 			if err != nil {
 				return err
 			}
@@ -87,6 +113,8 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 		Timeout:              opt.Timeout,
 		ErrorRetryPredicates: opt.ErrorRetryPredicates,
 		ErrorAbortPredicates: opt.ErrorAbortPredicates,
+		Policy:               opt.Config.RetryPolicy,
+		Metrics:              opt.Config.UsageMetrics,
 	})
 	if err != nil {
 		return nil, err
@@ -109,6 +137,11 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 		return nil, err
 	}
 
+	if opt.Method != "GET" {
+		opt.Config.PendingOperations.Track(PendingOperationUrl(opt.RawURL, result))
+	}
+	opt.Config.PendingOperations.Observe()
+
 	return result, nil
 }
 
@@ -0,0 +1,345 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UsageMetricsConfig configures the usage accounting set up by the
+// provider-level `usage_metrics` block.
+type UsageMetricsConfig struct {
+	// SummaryFile is the path a JSON summary of API usage is written to when
+	// the provider shuts down.
+	SummaryFile string
+
+	// PrometheusAddress is the local address, such as "127.0.0.1:9464", that
+	// API usage is served on in the Prometheus text format.
+	PrometheusAddress string
+}
+
+// ExpandProviderUsageMetricsConfig returns the usage metrics configuration for
+// the provider's `usage_metrics` block, or nil if the block is not set and
+// API usage should not be recorded.
+func ExpandProviderUsageMetricsConfig(v interface{}) (*UsageMetricsConfig, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return nil, nil
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	config := &UsageMetricsConfig{}
+	if v, ok := cfgV["summary_file"]; ok {
+		config.SummaryFile = v.(string)
+	}
+	if v, ok := cfgV["prometheus_address"]; ok {
+		config.PrometheusAddress = v.(string)
+	}
+
+	if config.SummaryFile == "" && config.PrometheusAddress == "" {
+		return nil, fmt.Errorf("usage_metrics: one of summary_file or prometheus_address must be set")
+	}
+	if config.PrometheusAddress != "" {
+		if _, _, err := net.SplitHostPort(config.PrometheusAddress); err != nil {
+			return nil, fmt.Errorf("usage_metrics: invalid prometheus_address %q: %s", config.PrometheusAddress, err)
+		}
+	}
+	return config, nil
+}
+
+// UsageMetrics counts the API calls made by a provider instance.
+type UsageMetrics struct {
+	mu           sync.Mutex
+	apis         map[string]*apiUsage
+	retryReasons map[string]int64
+	operations   map[string]*operationPolls
+	server       *http.Server
+}
+
+type apiUsage struct {
+	Calls   int64 `json:"calls"`
+	Retries int64 `json:"retries"`
+	Errors  int64 `json:"errors"`
+}
+
+// operationPolls records when an operation was first and last polled.
+type operationPolls struct {
+	first time.Time
+	last  time.Time
+}
+
+// UsageSummary is the JSON summary of API usage written to the summary file.
+type UsageSummary struct {
+	APIs                 map[string]apiUsage `json:"apis"`
+	RetryReasons         map[string]int64    `json:"retry_reasons"`
+	Operations           int64               `json:"operations"`
+	OperationWaitSeconds float64             `json:"operation_wait_seconds"`
+}
+
+var (
+	usageMetricsMu sync.Mutex
+	// usageMetrics holds the UsageMetrics of every configuration, keyed by
+	// configuration. The SDK and plugin framework halves of the provider are
+	// configured from the same provider block, so they share counters.
+	usageMetrics = make(map[UsageMetricsConfig]*UsageMetrics)
+)
+
+// NewUsageMetrics returns the UsageMetrics for config, starting a Prometheus
+// endpoint if one is configured. Provider instances with the same
+// configuration share the returned UsageMetrics. If config is nil, usage is
+// not recorded and nil is returned.
+func NewUsageMetrics(config *UsageMetricsConfig) (*UsageMetrics, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	usageMetricsMu.Lock()
+	defer usageMetricsMu.Unlock()
+
+	if m, ok := usageMetrics[*config]; ok {
+		return m, nil
+	}
+
+	m := &UsageMetrics{
+		apis:         make(map[string]*apiUsage),
+		retryReasons: make(map[string]int64),
+		operations:   make(map[string]*operationPolls),
+	}
+	if config.PrometheusAddress != "" {
+		listener, err := net.Listen("tcp", config.PrometheusAddress)
+		if err != nil {
+			return nil, fmt.Errorf("usage_metrics: unable to listen on %q: %s", config.PrometheusAddress, err)
+		}
+		m.server = &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			m.WritePrometheus(w)
+		})}
+		go func() {
+			if err := m.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				log.Printf("[WARN] Usage metrics endpoint stopped: %s", err)
+			}
+		}()
+		log.Printf("[DEBUG] Serving usage metrics on %s", listener.Addr())
+	}
+	usageMetrics[*config] = m
+	return m, nil
+}
+
+// FlushUsageMetrics writes the summary file of every UsageMetrics and stops
+// their Prometheus endpoints. It is called when the provider shuts down.
+func FlushUsageMetrics() {
+	usageMetricsMu.Lock()
+	defer usageMetricsMu.Unlock()
+
+	for config, m := range usageMetrics {
+		if m.server != nil {
+			m.server.Close()
+		}
+		if config.SummaryFile != "" {
+			if err := m.WriteSummaryFile(config.SummaryFile); err != nil {
+				log.Printf("[WARN] Unable to write usage summary to %q: %s", config.SummaryFile, err)
+			}
+		}
+		delete(usageMetrics, config)
+	}
+}
+
+func (m *UsageMetrics) api(name string) *apiUsage {
+	usage, ok := m.apis[name]
+	if !ok {
+		usage = &apiUsage{}
+		m.apis[name] = usage
+	}
+	return usage
+}
+
+func (m *UsageMetrics) recordCall(api string, failed bool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := m.api(api)
+	usage.Calls++
+	if failed {
+		usage.Errors++
+	}
+}
+
+func (m *UsageMetrics) recordRetry(api string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.api(api).Retries++
+}
+
+// recordRetryReason records the reason given by the RetryErrorPredicateFunc
+// that found an error retryable.
+func (m *UsageMetrics) recordRetryReason(reason string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retryReasons[reason]++
+}
+
+// recordOperationPoll records that the operation at path was polled at t. The
+// time spent waiting on an operation is the time between its first and last
+// poll.
+func (m *UsageMetrics) recordOperationPoll(path string, t time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if polls, ok := m.operations[path]; ok {
+		polls.last = t
+		return
+	}
+	m.operations[path] = &operationPolls{first: t, last: t}
+}
+
+// Summary returns the API usage recorded so far.
+func (m *UsageMetrics) Summary() UsageSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	summary := UsageSummary{
+		APIs:         make(map[string]apiUsage, len(m.apis)),
+		RetryReasons: make(map[string]int64, len(m.retryReasons)),
+		Operations:   int64(len(m.operations)),
+	}
+	for api, usage := range m.apis {
+		summary.APIs[api] = *usage
+	}
+	for reason, n := range m.retryReasons {
+		summary.RetryReasons[reason] = n
+	}
+	var wait time.Duration
+	for _, polls := range m.operations {
+		wait += polls.last.Sub(polls.first)
+	}
+	summary.OperationWaitSeconds = wait.Seconds()
+	return summary
+}
+
+// WriteSummaryFile writes the API usage recorded so far to path as JSON.
+func (m *UsageMetrics) WriteSummaryFile(path string) error {
+	b, err := json.MarshalIndent(m.Summary(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// WritePrometheus writes the API usage recorded so far to w in the Prometheus
+// text exposition format.
+func (m *UsageMetrics) WritePrometheus(w io.Writer) {
+	summary := m.Summary()
+
+	apis := make([]string, 0, len(summary.APIs))
+	for api := range summary.APIs {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(apiUsage) int64
+	}{
+		{"google_provider_api_calls_total", "API requests made. A request that was retried is counted once.", func(u apiUsage) int64 { return u.Calls }},
+		{"google_provider_api_retries_total", "Retries of failed API requests.", func(u apiUsage) int64 { return u.Retries }},
+		{"google_provider_api_errors_total", "API requests that failed, after any retries.", func(u apiUsage) int64 { return u.Errors }},
+	}
+	for _, c := range counters {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+		for _, api := range apis {
+			fmt.Fprintf(w, "%s{api=%q} %d\n", c.name, api, c.value(summary.APIs[api]))
+		}
+	}
+
+	reasons := make([]string, 0, len(summary.RetryReasons))
+	for reason := range summary.RetryReasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	fmt.Fprintf(w, "# HELP google_provider_retry_reasons_total Errors found retryable, by the reason given by the retry predicate.\n# TYPE google_provider_retry_reasons_total counter\n")
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "google_provider_retry_reasons_total{reason=%q} %d\n", prometheusLabelValue(reason), summary.RetryReasons[reason])
+	}
+
+	fmt.Fprintf(w, "# HELP google_provider_operations_total Long-running operations polled.\n# TYPE google_provider_operations_total counter\n")
+	fmt.Fprintf(w, "google_provider_operations_total %d\n", summary.Operations)
+	fmt.Fprintf(w, "# HELP google_provider_operation_wait_seconds_total Time spent waiting on long-running operations.\n# TYPE google_provider_operation_wait_seconds_total counter\n")
+	fmt.Fprintf(w, "google_provider_operation_wait_seconds_total %g\n", summary.OperationWaitSeconds)
+}
+
+// prometheusLabelValue keeps label values on one line; %q escapes the rest.
+func prometheusLabelValue(s string) string {
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+type usageMetricsContextKey struct{}
+
+func withUsageMetrics(ctx context.Context, m *UsageMetrics) context.Context {
+	return context.WithValue(ctx, usageMetricsContextKey{}, m)
+}
+
+func usageMetricsFromContext(ctx context.Context) *UsageMetrics {
+	m, _ := ctx.Value(usageMetricsContextKey{}).(*UsageMetrics)
+	return m
+}
+
+// usageMetricsTransport counts the API calls sent through it, and makes the
+// UsageMetrics available to the transports it wraps.
+type usageMetricsTransport struct {
+	internal http.RoundTripper
+	metrics  *UsageMetrics
+}
+
+// NewTransportWithUsageMetrics constructs a usageMetricsTransport wrapping t.
+// If m is nil, t is returned unchanged.
+func NewTransportWithUsageMetrics(t http.RoundTripper, m *UsageMetrics) http.RoundTripper {
+	if m == nil {
+		return t
+	}
+	return &usageMetricsTransport{internal: t, metrics: m}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *usageMetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && isOperationPath(req.URL.Path) {
+		t.metrics.recordOperationPoll(cacheResourcePath(req.URL), time.Now())
+	}
+
+	resp, err := t.internal.RoundTrip(req.WithContext(withUsageMetrics(req.Context(), t.metrics)))
+	t.metrics.recordCall(req.URL.Host, err != nil || resp.StatusCode >= 400)
+	return resp, err
+}
+
+func isOperationPath(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "operations" {
+			return true
+		}
+	}
+	return false
+}
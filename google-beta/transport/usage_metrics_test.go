@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func newTestUsageMetrics(t *testing.T) (*UsageMetrics, string) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	m, err := NewUsageMetrics(&UsageMetricsConfig{SummaryFile: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(FlushUsageMetrics)
+	return m, path
+}
+
+func TestUsageMetricsTransport_CountsCallsAndRetries(t *testing.T) {
+	m, _ := newTestUsageMetrics(t)
+
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	retryTransport := NewTransportWithRetryPolicy(http.DefaultTransport, &RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	})
+	client := &http.Client{Transport: NewTransportWithUsageMetrics(retryTransport, m)}
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	u, _ := url.Parse(ts.URL)
+	summary := m.Summary()
+	if got := summary.APIs[u.Host]; got.Calls != 1 || got.Retries != 1 || got.Errors != 0 {
+		t.Fatalf("expected 1 call with 1 retry, got %+v", got)
+	}
+	if n := summary.RetryReasons["Retryable error code 503"]; n != 1 {
+		t.Fatalf("expected the 503 retry reason to be recorded once, got %v", summary.RetryReasons)
+	}
+}
+
+func TestUsageMetricsTransport_OperationWait(t *testing.T) {
+	m, path := newTestUsageMetrics(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	client := &http.Client{Transport: NewTransportWithUsageMetrics(http.DefaultTransport, m)}
+
+	op := ts.URL + "/compute/v1/projects/p/zones/z/operations/op-1"
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(op)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	FlushUsageMetrics()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected a summary file to be written: %v", err)
+	}
+	var summary UsageSummary
+	if err := json.Unmarshal(b, &summary); err != nil {
+		t.Fatalf("summary file is not valid JSON: %s", b)
+	}
+	if summary.Operations != 1 || summary.OperationWaitSeconds < 0.05 {
+		t.Fatalf("expected 1 operation waited on for at least 50ms, got %s", b)
+	}
+}
+
+func TestRetry_RecordsRetryReasons(t *testing.T) {
+	m, _ := newTestUsageMetrics(t)
+
+	attempts := 0
+	err := Retry(RetryOptions{
+		RetryFunc: func() error {
+			attempts++
+			if attempts < 3 {
+				return &googleapi.Error{Code: 409, Message: "operation in progress"}
+			}
+			return nil
+		},
+		Timeout:              time.Minute,
+		PollInterval:         time.Millisecond,
+		ErrorRetryPredicates: []RetryErrorPredicateFunc{IsSqlOperationInProgressError},
+		Metrics:              m,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reasons := m.Summary().RetryReasons
+	if n := reasons["Waiting for other concurrent Cloud SQL operations to finish"]; n != 2 || len(reasons) != 1 {
+		t.Fatalf("expected the predicate's reason to be recorded twice, got %v", reasons)
+	}
+}
+
+func TestUsageMetrics_WritePrometheus(t *testing.T) {
+	m, _ := newTestUsageMetrics(t)
+	m.recordCall("compute.googleapis.com", false)
+	m.recordCall("compute.googleapis.com", true)
+	m.recordRetry("compute.googleapis.com")
+	m.recordRetryReason("Retryable error code 503")
+
+	var buf bytes.Buffer
+	m.WritePrometheus(&buf)
+	for _, want := range []string{
+		`google_provider_api_calls_total{api="compute.googleapis.com"} 2`,
+		`google_provider_api_retries_total{api="compute.googleapis.com"} 1`,
+		`google_provider_api_errors_total{api="compute.googleapis.com"} 1`,
+		`google_provider_retry_reasons_total{reason="Retryable error code 503"} 1`,
+		`google_provider_operations_total 0`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestNewUsageMetrics_SharedByConfiguration(t *testing.T) {
+	m, path := newTestUsageMetrics(t)
+	other, err := NewUsageMetrics(&UsageMetricsConfig{SummaryFile: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other != m {
+		t.Fatalf("expected provider instances with the same configuration to share usage metrics")
+	}
+
+	if m, err := NewUsageMetrics(nil); m != nil || err != nil {
+		t.Fatalf("expected no usage metrics without a configuration, got %v, %v", m, err)
+	}
+}
+
+func TestExpandProviderUsageMetricsConfig(t *testing.T) {
+	if cfg, err := ExpandProviderUsageMetricsConfig([]interface{}{}); err != nil || cfg != nil {
+		t.Fatalf("expected no config without a block, got %v, %v", cfg, err)
+	}
+
+	cfg, err := ExpandProviderUsageMetricsConfig([]interface{}{map[string]interface{}{
+		"summary_file":       "usage.json",
+		"prometheus_address": "127.0.0.1:9464",
+	}})
+	if err != nil || cfg.SummaryFile != "usage.json" || cfg.PrometheusAddress != "127.0.0.1:9464" {
+		t.Fatalf("unexpected config %v, %v", cfg, err)
+	}
+
+	for _, cfgV := range []map[string]interface{}{
+		{"summary_file": "", "prometheus_address": ""},
+		{"prometheus_address": "9464"},
+	} {
+		if _, err := ExpandProviderUsageMetricsConfig([]interface{}{cfgV}); err == nil {
+			t.Errorf("expected an error for %v", cfgV)
+		}
+	}
+}
+
+func TestUsageMetricsTransport_CountsErrors(t *testing.T) {
+	m, _ := newTestUsageMetrics(t)
+	client := &http.Client{Transport: NewTransportWithUsageMetrics(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}), m)}
+
+	if _, err := client.Get("https://storage.googleapis.com/storage/v1/b/bucket"); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if got := m.Summary().APIs["storage.googleapis.com"]; got.Calls != 1 || got.Errors != 1 {
+		t.Fatalf("expected 1 failed call, got %+v", got)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
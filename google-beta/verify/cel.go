@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package verify
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/ast"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// CELEnvironment declares the attributes and functions an API makes available
+// to Common Expression Language expressions, so they can be checked before
+// they are sent.
+type CELEnvironment struct {
+	env *cel.Env
+}
+
+func newCELEnvironment(opts ...cel.EnvOption) *CELEnvironment {
+	opts = append(opts, cel.ASTValidators(
+		cel.ValidateDurationLiterals(),
+		cel.ValidateTimestampLiterals(),
+		cel.ValidateRegexLiterals(),
+	))
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		panic(fmt.Sprintf("invalid CEL environment: %s", err))
+	}
+	return &CELEnvironment{env: env}
+}
+
+// CheckCELExpression parses and type-checks a boolean CEL expression. Syntax
+// errors are returned as errors. Problems found by the type checker, such as
+// an attribute or function the environment does not declare, are returned as
+// warnings, as the API may accept more than is declared here. Each message
+// names the line and column of the problem and quotes the line.
+func CheckCELExpression(env *CELEnvironment, expression string) (warnings []string, errors []error) {
+	source := common.NewTextSource(expression)
+	parsed, issues := env.env.ParseSource(source)
+	if issues.Err() != nil {
+		for _, err := range issues.Errors() {
+			errors = append(errors, fmt.Errorf("%s", formatCELIssue(err, source)))
+		}
+		return nil, errors
+	}
+
+	checked, issues := env.env.Check(parsed)
+	if issues.Err() != nil {
+		for _, err := range issues.Errors() {
+			warnings = append(warnings, formatCELIssue(err, source))
+		}
+		return warnings, nil
+	}
+	if t := checked.OutputType(); !t.IsAssignableType(cel.BoolType) {
+		warnings = append(warnings, fmt.Sprintf("expression must evaluate to a bool, found '%s'", t))
+	}
+	return warnings, nil
+}
+
+// formatCELIssue renders an issue as its position and message followed by
+// the quoted line, without the generic prefix cel-go adds.
+func formatCELIssue(err *common.Error, source common.Source) string {
+	message := strings.TrimPrefix(err.ToDisplayString(source), "ERROR: <input>:")
+	return strings.Replace(message, " (in container '')", "", 1)
+}
+
+// ValidateCELExpression checks that a string is a boolean CEL expression.
+// Syntax errors fail validation, while uses of anything the given
+// environment does not declare are reported as warnings.
+func ValidateCELExpression(env *CELEnvironment) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		warnings, errs := CheckCELExpression(env, v.(string))
+		for _, w := range warnings {
+			ws = append(ws, fmt.Sprintf("%q may not be a valid CEL expression: %s", k, w))
+		}
+		for _, err := range errs {
+			errors = append(errors, fmt.Errorf("%q is not a valid CEL expression: %s", k, err))
+		}
+		return
+	}
+}
+
+// celIPRangeValidator reports IP ranges given to inIpRange as literals that
+// are not valid addresses or CIDR ranges.
+type celIPRangeValidator struct{}
+
+func (celIPRangeValidator) Name() string {
+	return "google.cloudarmor.validate.inIpRange"
+}
+
+func (celIPRangeValidator) Validate(_ *cel.Env, _ cel.ValidatorConfig, a *ast.AST, issues *cel.Issues) {
+	for _, call := range ast.MatchDescendants(ast.NavigateAST(a), ast.FunctionMatcher("inIpRange")) {
+		args := call.AsCall().Args()
+		if len(args) != 2 || args[1].Kind() != ast.LiteralKind {
+			continue
+		}
+		ipRange, ok := args[1].AsLiteral().Value().(string)
+		if !ok {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ipRange); err != nil && net.ParseIP(ipRange) == nil {
+			issues.ReportErrorAtID(args[1].ID(), "invalid IP range %q", ipRange)
+		}
+	}
+}
+
+// CloudArmorCELEnvironment declares the attributes and functions of Cloud
+// Armor rule expressions.
+// https://cloud.google.com/armor/docs/rules-language-reference
+var CloudArmorCELEnvironment = func() *CELEnvironment {
+	opts := []cel.EnvOption{
+		cel.Variable("request.headers", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("request.method", cel.StringType),
+		cel.Variable("request.path", cel.StringType),
+		cel.Variable("request.query", cel.StringType),
+		cel.Variable("request.scheme", cel.StringType),
+		cel.Variable("origin.ip", cel.StringType),
+		cel.Variable("origin.user_ip", cel.StringType),
+		cel.Variable("origin.region_code", cel.StringType),
+		cel.Variable("origin.asn", cel.IntType),
+		cel.Variable("origin.tls_ja3_fingerprint", cel.StringType),
+		cel.Variable("origin.tls_ja4_fingerprint", cel.StringType),
+		// The reCAPTCHA tokens carry more attributes than their score.
+		cel.Variable("token.recaptcha_session", cel.DynType),
+		cel.Variable("token.recaptcha_action", cel.DynType),
+		cel.Variable("token.recaptcha_exemption", cel.DynType),
+
+		cel.Function("inIpRange", cel.Overload("inIpRange_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType)),
+		cel.Function("evaluatePreconfiguredExpr",
+			cel.Overload("evaluatePreconfiguredExpr_string", []*cel.Type{cel.StringType}, cel.BoolType),
+			cel.Overload("evaluatePreconfiguredExpr_string_list", []*cel.Type{cel.StringType, cel.ListType(cel.StringType)}, cel.BoolType),
+		),
+		cel.Function("evaluatePreconfiguredWaf",
+			cel.Overload("evaluatePreconfiguredWaf_string", []*cel.Type{cel.StringType}, cel.BoolType),
+			cel.Overload("evaluatePreconfiguredWaf_string_map", []*cel.Type{cel.StringType, cel.MapType(cel.StringType, cel.DynType)}, cel.BoolType),
+		),
+		cel.Function("evaluateThreatIntelligence", cel.Overload("evaluateThreatIntelligence_string", []*cel.Type{cel.StringType}, cel.BoolType)),
+		cel.Function("evaluateAddressGroup", cel.Overload("evaluateAddressGroup_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType)),
+		cel.ASTValidators(celIPRangeValidator{}),
+	}
+	for _, name := range []string{"lower", "upper", "base64Decode", "urlDecode", "urlDecodeUni", "utf8ToUnicode"} {
+		opts = append(opts, cel.Function(name, cel.MemberOverload("string_"+name, []*cel.Type{cel.StringType}, cel.StringType)))
+	}
+	return newCELEnvironment(opts...)
+}()
+
+// iamTagFunctions are the resource tag functions shared by IAM allow and
+// deny conditions.
+func iamTagFunctions() []cel.EnvOption {
+	var opts []cel.EnvOption
+	for name, args := range map[string]int{"matchTag": 2, "matchTagId": 2, "hasTagKey": 1, "hasTagKeyId": 1} {
+		params := []*cel.Type{cel.StringType}
+		if args == 2 {
+			params = append(params, cel.StringType)
+		}
+		opts = append(opts, cel.Function(name, cel.MemberOverload(
+			"resource_"+name, append([]*cel.Type{cel.OpaqueType("google.iam.Resource")}, params...), cel.BoolType)))
+	}
+	return opts
+}
+
+// IAMConditionCELEnvironment declares the attributes and functions of IAM
+// conditions in allow policies.
+// https://cloud.google.com/iam/docs/conditions-attribute-reference
+var IAMConditionCELEnvironment = newCELEnvironment(append([]cel.EnvOption{
+	cel.Variable("request.time", cel.TimestampType),
+	cel.Variable("request.host", cel.StringType),
+	cel.Variable("request.path", cel.StringType),
+	// The request.auth attributes depend on the access levels in use.
+	cel.Variable("request.auth", cel.DynType),
+	cel.Variable("resource", cel.OpaqueType("google.iam.Resource")),
+	cel.Variable("resource.name", cel.StringType),
+	cel.Variable("resource.type", cel.StringType),
+	cel.Variable("resource.service", cel.StringType),
+	cel.Variable("destination.ip", cel.StringType),
+	cel.Variable("destination.port", cel.IntType),
+	cel.Variable("api", cel.OpaqueType("google.iam.Api")),
+
+	cel.Function("getAttribute", cel.MemberOverload("api_getAttribute",
+		[]*cel.Type{cel.OpaqueType("google.iam.Api"), cel.StringType, cel.DynType}, cel.DynType)),
+	cel.Function("extract", cel.MemberOverload("string_extract", []*cel.Type{cel.StringType, cel.StringType}, cel.StringType)),
+	cel.Function("hasOnly", cel.MemberOverload("list_hasOnly",
+		[]*cel.Type{cel.ListType(cel.DynType), cel.ListType(cel.DynType)}, cel.BoolType)),
+}, iamTagFunctions()...)...)
+
+// IAMDenyConditionCELEnvironment declares what deny policy conditions may
+// use, which is limited to the resource tag functions.
+// https://cloud.google.com/iam/docs/deny-overview#conditions
+var IAMDenyConditionCELEnvironment = newCELEnvironment(append([]cel.EnvOption{
+	cel.Variable("resource", cel.OpaqueType("google.iam.Resource")),
+}, iamTagFunctions()...)...)
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCELExpression_valid(t *testing.T) {
+	cases := map[string]struct {
+		Env        *CELEnvironment
+		Expression string
+	}{
+		"cloud armor region": {
+			Env:        CloudArmorCELEnvironment,
+			Expression: "origin.region_code == 'AU' && !inIpRange(origin.ip, '10.0.0.0/8')",
+		},
+		"cloud armor headers": {
+			Env: CloudArmorCELEnvironment,
+			Expression: `request.headers['user-agent'].lower().contains("bot") ||
+				request.path.matches('/login(\\?.*)?$') ||
+				has(request.headers.cookie)`,
+		},
+		"cloud armor preconfigured waf": {
+			Env:        CloudArmorCELEnvironment,
+			Expression: "evaluatePreconfiguredWaf('sqli-v33-stable', {'sensitivity': 1, 'opt_out_rule_ids': ['owasp-crs-v030301-id942350-sqli']})",
+		},
+		"cloud armor recaptcha": {
+			Env:        CloudArmorCELEnvironment,
+			Expression: "token.recaptcha_session.score < 0.5 && origin.asn in [15169, 16509]",
+		},
+		"iam time": {
+			Env:        IAMConditionCELEnvironment,
+			Expression: `request.time < timestamp("2030-01-01T00:00:00Z") && request.time.getDayOfWeek("Europe/Berlin") > 0`,
+		},
+		"iam resource": {
+			Env: IAMConditionCELEnvironment,
+			Expression: `resource.type == "storage.googleapis.com/Object" &&
+				resource.name.startsWith("projects/_/buckets/example/objects/logs/") ||
+				resource.name.extract("/objects/{name}") != ""`,
+		},
+		"iam attributes and macros": {
+			Env:        IAMConditionCELEnvironment,
+			Expression: "api.getAttribute('iam.googleapis.com/modifiedGrantsByRole', []).all(r, r in ['roles/viewer'])",
+		},
+		"iam hasOnly": {
+			Env:        IAMConditionCELEnvironment,
+			Expression: "api.getAttribute('iam.googleapis.com/modifiedGrantsByRole', []).hasOnly(['roles/viewer'])",
+		},
+		"iam tags": {
+			Env:        IAMConditionCELEnvironment,
+			Expression: "resource.matchTag('123456789012/env', 'prod') ? true : resource.hasTagKey('123456789012/team')",
+		},
+		"deny tags": {
+			Env:        IAMDenyConditionCELEnvironment,
+			Expression: "!resource.matchTagId('tagKeys/123', 'tagValues/456')",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if ws, errs := CheckCELExpression(tc.Env, tc.Expression); len(ws) > 0 || len(errs) > 0 {
+				t.Fatalf("expected no warnings or errors, got %q, %v", ws, errs)
+			}
+		})
+	}
+}
+
+func TestCheckCELExpression_syntaxErrors(t *testing.T) {
+	cases := map[string]struct {
+		Expression    string
+		ExpectedError string
+	}{
+		"incomplete expression": {
+			Expression:    "origin.ip == '1.2.3.4' &&",
+			ExpectedError: "1:26: Syntax error: mismatched input '<EOF>'",
+		},
+		"unterminated string": {
+			Expression:    "request.path == '/admin",
+			ExpectedError: "1:17: Syntax error: token recognition error",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			_, errs := CheckCELExpression(CloudArmorCELEnvironment, tc.Expression)
+			if len(errs) == 0 {
+				t.Fatalf("expected an error containing %q, got none", tc.ExpectedError)
+			}
+			if !strings.Contains(errs[0].Error(), tc.ExpectedError) {
+				t.Fatalf("expected an error containing %q, got %q", tc.ExpectedError, errs[0])
+			}
+		})
+	}
+}
+
+func TestCheckCELExpression_warnings(t *testing.T) {
+	cases := map[string]struct {
+		Env             *CELEnvironment
+		Expression      string
+		ExpectedWarning string
+	}{
+		"misspelled variable": {
+			Env:             CloudArmorCELEnvironment,
+			Expression:      "reqest.path == '/'",
+			ExpectedWarning: "1:1: undeclared reference to 'reqest'",
+		},
+		"misspelled function": {
+			Env:             CloudArmorCELEnvironment,
+			Expression:      "request.path.startWith('/admin')",
+			ExpectedWarning: "1:23: undeclared reference to 'startWith'",
+		},
+		"wrong argument type": {
+			Env:             CloudArmorCELEnvironment,
+			Expression:      "origin.asn == '15169'",
+			ExpectedWarning: "1:12: found no matching overload for '_==_' applied to '(int, string)'",
+		},
+		"invalid ip range": {
+			Env:             CloudArmorCELEnvironment,
+			Expression:      "inIpRange(origin.ip, '10.0.0.0/33')",
+			ExpectedWarning: "1:22: invalid IP range \"10.0.0.0/33\"",
+		},
+		"not a bool": {
+			Env:             CloudArmorCELEnvironment,
+			Expression:      "request.headers['host']",
+			ExpectedWarning: "expression must evaluate to a bool, found 'string'",
+		},
+		"position on a later line": {
+			Env:             IAMConditionCELEnvironment,
+			Expression:      "resource.type == 'storage.googleapis.com/Bucket' &&\n  reqest.time < timestamp('2030-01-01T00:00:00Z')",
+			ExpectedWarning: "2:3: undeclared reference to 'reqest'\n |   reqest.time < timestamp('2030-01-01T00:00:00Z')\n | ..^",
+		},
+		"invalid timestamp": {
+			Env:             IAMConditionCELEnvironment,
+			Expression:      "request.time < timestamp('2030-01-01')",
+			ExpectedWarning: "1:26: invalid timestamp argument",
+		},
+		"cloud armor attribute in iam": {
+			Env:             IAMConditionCELEnvironment,
+			Expression:      "origin.region_code == 'AU'",
+			ExpectedWarning: "1:1: undeclared reference to 'origin'",
+		},
+		"invalid regular expression": {
+			Env:             IAMConditionCELEnvironment,
+			Expression:      "resource.name.matches('projects/(a|b')",
+			ExpectedWarning: "1:23: invalid matches argument",
+		},
+		"resource name in deny condition": {
+			Env:             IAMDenyConditionCELEnvironment,
+			Expression:      "resource.name.startsWith('projects/')",
+			ExpectedWarning: "1:9: type 'google.iam.Resource' does not support field selection",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ws, errs := CheckCELExpression(tc.Env, tc.Expression)
+			if len(errs) > 0 {
+				t.Fatalf("expected only warnings, got errors %v", errs)
+			}
+			if len(ws) == 0 {
+				t.Fatalf("expected a warning containing %q, got none", tc.ExpectedWarning)
+			}
+			if !strings.Contains(ws[0], tc.ExpectedWarning) {
+				t.Fatalf("expected a warning containing %q, got %q", tc.ExpectedWarning, ws[0])
+			}
+		})
+	}
+}
+
+func TestValidateCELExpression(t *testing.T) {
+	ws, errs := ValidateCELExpression(CloudArmorCELEnvironment)("origin.ip == '1.2.3.4' && reqest.method == 'POST'", "expression")
+	if len(errs) != 0 || len(ws) != 1 {
+		t.Fatalf("expected one warning and no errors, got %q, %v", ws, errs)
+	}
+	expected := "\"expression\" may not be a valid CEL expression: 1:27: undeclared reference to 'reqest'\n" +
+		" | origin.ip == '1.2.3.4' && reqest.method == 'POST'\n" +
+		" | ..........................^"
+	if ws[0] != expected {
+		t.Errorf("expected warning\n%s\ngot\n%s", expected, ws[0])
+	}
+
+	ws, errs = ValidateCELExpression(CloudArmorCELEnvironment)("origin.ip == '1.2.3.4' &&", "expression")
+	if len(errs) != 1 || len(ws) != 0 {
+		t.Fatalf("expected one error and no warnings, got %q, %v", ws, errs)
+	}
+	if !strings.HasPrefix(errs[0].Error(), "\"expression\" is not a valid CEL expression: 1:26: Syntax error") {
+		t.Errorf("unexpected error %q", errs[0])
+	}
+}
@@ -36,6 +36,12 @@ const (
 
 	// https://cloud.google.com/managed-microsoft-ad/reference/rest/v1/projects.locations.global.domains/create#query-parameters
 	ADDomainNameRegex = "^[a-z][a-z0-9-]{0,14}\\.[a-z0-9-\\.]*[a-z]+[a-z0-9]*$"
+
+	// https://cloud.google.com/resource-manager/docs/labels-overview#requirements
+	// Lowercase letters include international characters, such as those
+	// without case.
+	LabelKeyRegex   = "^[\\p{Ll}\\p{Lo}][\\p{Ll}\\p{Lo}\\p{N}_-]{0,62}$"
+	LabelValueRegex = "^[\\p{Ll}\\p{Lo}\\p{N}_-]{0,63}$"
 )
 
 var (
@@ -91,6 +97,16 @@ func ValidateGCEName(v interface{}, k string) (ws []string, errors []error) {
 	return ValidateRegexp(re)(v, k)
 }
 
+// ValidateLabelKey ensures that a field is a valid label key
+func ValidateLabelKey(v interface{}, k string) (ws []string, errors []error) {
+	return ValidateRegexp(LabelKeyRegex)(v, k)
+}
+
+// ValidateLabelValue ensures that a field is a valid label value
+func ValidateLabelValue(v interface{}, k string) (ws []string, errors []error) {
+	return ValidateRegexp(LabelValueRegex)(v, k)
+}
+
 // Ensure that the BGP ASN value of Cloud Router is a valid value as per RFC6996 or a value of 16550
 func ValidateRFC6996Asn(v interface{}, k string) (ws []string, errors []error) {
 	value := int64(v.(int))
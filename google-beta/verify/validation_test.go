@@ -141,6 +141,40 @@ func TestValidateGCEName(t *testing.T) {
 	}
 }
 
+func TestValidateLabelKeyAndValue(t *testing.T) {
+	keys := []StringValidationTestCase{
+		// No errors
+		{TestName: "basic", Value: "env"},
+		{TestName: "with numbers, underscores and hyphens", Value: "team_1-a"},
+		{TestName: "international", Value: "équipe"},
+		{TestName: "long", Value: "a" + strings.Repeat("b", 62)},
+
+		// With errors
+		{TestName: "empty", Value: "", ExpectError: true},
+		{TestName: "starts with a number", Value: "1env", ExpectError: true},
+		{TestName: "has a capital", Value: "Env", ExpectError: true},
+		{TestName: "has a dot", Value: "env.name", ExpectError: true},
+		{TestName: "too long", Value: "a" + strings.Repeat("b", 63), ExpectError: true},
+	}
+	if es := TestStringValidationCases(keys, ValidateLabelKey); len(es) > 0 {
+		t.Errorf("Failed to validate label keys: %v", es)
+	}
+
+	values := []StringValidationTestCase{
+		// No errors
+		{TestName: "empty", Value: ""},
+		{TestName: "starts with a number", Value: "1-prod"},
+		{TestName: "long", Value: strings.Repeat("a", 63)},
+
+		// With errors
+		{TestName: "has a space", Value: "my prod", ExpectError: true},
+		{TestName: "too long", Value: strings.Repeat("a", 64), ExpectError: true},
+	}
+	if es := TestStringValidationCases(values, ValidateLabelValue); len(es) > 0 {
+		t.Errorf("Failed to validate label values: %v", es)
+	}
+}
+
 func TestValidateRFC3339Time(t *testing.T) {
 	cases := []StringValidationTestCase{
 		// No errors
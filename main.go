@@ -14,6 +14,7 @@ import (
 
 	"github.com/hashicorp/terraform-provider-google-beta/google-beta/fwprovider"
 	"github.com/hashicorp/terraform-provider-google-beta/google-beta/provider"
+	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
 	ver "github.com/hashicorp/terraform-provider-google-beta/version"
 )
 
@@ -32,6 +33,23 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
+	// Export OpenTelemetry traces when OTEL_EXPORTER_OTLP_ENDPOINT is set.
+	shutdownTracing, err := transport_tpg.SetupTracing(context.Background(), version)
+	if err != nil {
+		log.Printf("[WARN] Unable to set up OpenTelemetry tracing: %s", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("[WARN] Unable to flush OpenTelemetry traces: %s", err)
+		}
+	}()
+
+	// Write usage summaries of provider configurations with a usage_metrics block.
+	defer transport_tpg.FlushUsageMetrics()
+
+	// Log which resource locks are held and waited for if the provider is sent SIGQUIT.
+	transport_tpg.LogMutexStoreOnSIGQUIT()
+
 	// concat with sdkv2 provider
 	providers := []func() tfprotov5.ProviderServer{
 		providerserver.NewProtocol5(fwprovider.New(version)), // framework provider